@@ -0,0 +1,42 @@
+package errors
+
+// Annotate wraps the error stored in *errp as Wrap would, if it is non-nil.
+// It is designed to be deferred by functions with a named error result:
+//
+//	func loadConfig(path string) (err error) {
+//		defer errors.Annotate(&err, "loading config")
+//		...
+//	}
+//
+// The stack trace is recorded in the function which deferred Annotate.
+func Annotate(errp *error, message string) {
+	err := *errp
+	if err == nil {
+		return
+	}
+	w := &withMessage{
+		cause: err,
+		msg:   message,
+	}
+	*errp = created(transform(err, &withStack{
+		w,
+		callers(),
+	}))
+}
+
+// Annotatef wraps the error stored in *errp as Wrapf would, if it is non-nil.
+// See Annotate. The arguments are only formatted if *errp is non-nil.
+func Annotatef(errp *error, format string, args ...interface{}) {
+	err := *errp
+	if err == nil {
+		return
+	}
+	w := &withMessage{
+		cause: err,
+		msg:   sprintf(format, args...),
+	}
+	*errp = created(transform(err, &withStack{
+		w,
+		callers(),
+	}))
+}
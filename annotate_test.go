@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func annotated(err error) (result error) {
+	defer Annotate(&result, "loading config")
+	return err
+}
+
+func annotatedf(err error) (result error) {
+	defer Annotatef(&result, "loading %s", "config")
+	return err
+}
+
+func TestAnnotate(t *testing.T) {
+	for _, fn := range []func(error) error{annotated, annotatedf} {
+		if err := fn(nil); err != nil {
+			t.Errorf("Annotate(nil): got %v, want nil", err)
+		}
+
+		err := fn(io.EOF)
+		if got, want := err.Error(), "loading config: EOF"; got != want {
+			t.Errorf("Annotate(): got %q, want %q", got, want)
+		}
+		if !Is(err, io.EOF) {
+			t.Error("Is(Annotate(), io.EOF): got false, want true")
+		}
+		want := "^EOF\nloading config\ngithub.com/peakle/errors.annotatedf?\n\t.+/annotate_test.go:\\d+\n"
+		if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+			t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+		}
+	}
+}
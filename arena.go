@@ -0,0 +1,246 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// arenaChunk is the number of values an Arena allocates at once.
+const arenaChunk = 256
+
+// An Arena allocates the errors created through its methods in batches,
+// and reuses their memory once freed, for batch jobs that create and
+// discard millions of errors per run, where it cuts the number of
+// allocations and thus the work of the garbage collector:
+//
+//	a := errors.NewArena()
+//	for _, batch := range batches {
+//		for _, item := range batch {
+//			if err := process(item); err != nil {
+//				failed = append(failed, a.Wrap(err, "process item"))
+//			}
+//		}
+//		report(failed)
+//		failed = failed[:0]
+//		a.Free()
+//	}
+//
+// The errors created by an Arena are valid only until its Free method is
+// called: their memory is then cleared and reused by the errors created
+// next. An error created by an Arena, or any error wrapping it, must
+// therefore neither be used after Free, nor escape the scope the Arena
+// serves, such as by being returned, stored or sent to another goroutine
+// that may outlive it. For the same reason, Arena errors are not passed to
+// the hooks registered by RegisterHook, nor logged to the execution trace
+// by SetTraceEvents, and do not record the goroutine set by
+// SetGoroutineCapture. Otherwise they behave as the errors returned by the
+// functions of the same names. While SetChainIndex is enabled, Free removes
+// the errors of a from the chain index, so that Is and As do not answer for
+// the errors created next from the chains of the freed ones.
+//
+// An Arena is safe for concurrent use. The zero Arena is ready to use. The
+// methods of a nil *Arena allocate nothing: they behave as the functions of
+// the same names, and Free does nothing.
+type Arena struct {
+	mu           sync.Mutex
+	fundamentals slab[fundamental]
+	stacks       slab[withStack]
+	messages     slab[withMessage]
+	traces       slab[stack]
+	pcs          pcSlab
+}
+
+// NewArena returns a new, empty Arena.
+func NewArena() *Arena { return new(Arena) }
+
+// New is like the function New, allocating the error from a.
+func (a *Arena) New(message string) error {
+	if a == nil {
+		return created(newFundamental(message))
+	}
+	return a.fundamental(message, a.callers())
+}
+
+// Errorf is like the function Errorf, allocating the error from a.
+func (a *Arena) Errorf(format string, args ...interface{}) error {
+	if a == nil {
+		return created(newFundamental(sprintf(format, args...)))
+	}
+	return a.fundamental(sprintf(format, args...), a.callers())
+}
+
+// fundamental returns an error with the supplied message and stack trace,
+// allocated from a.
+func (a *Arena) fundamental(msg string, st *stack) *fundamental {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	f := a.fundamentals.alloc()
+	f.msg, f.stack = msg, st
+	return f
+}
+
+// WithStack is like the function WithStack, allocating the error from a.
+func (a *Arena) WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	if a == nil {
+		return created(transform(err, &withStack{err, callersFor(err)}))
+	}
+	st := a.callers()
+	a.mu.Lock()
+	w := a.stacks.alloc()
+	w.error, w.stack = err, st
+	a.mu.Unlock()
+	return transform(err, w)
+}
+
+// WithMessage is like the function WithMessage, allocating the error
+// from a.
+func (a *Arena) WithMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	if a == nil {
+		return WithMessage(err, message)
+	}
+	a.mu.Lock()
+	m := a.messages.alloc()
+	m.cause, m.msg = err, message
+	a.mu.Unlock()
+	return transform(err, m)
+}
+
+// Wrap is like the function Wrap, allocating the error from a.
+func (a *Arena) Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	if a == nil {
+		w := &withMessage{cause: err, msg: message}
+		return created(transform(err, &withStack{w, callersFor(err)}))
+	}
+	st := a.callers()
+	a.mu.Lock()
+	m := a.messages.alloc()
+	m.cause, m.msg = err, message
+	w := a.stacks.alloc()
+	w.error, w.stack = m, st
+	a.mu.Unlock()
+	return transform(err, w)
+}
+
+// Wrapf is like the function Wrapf, allocating the error from a.
+func (a *Arena) Wrapf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	msg := sprintf(format, args...)
+	if a == nil {
+		w := &withMessage{cause: err, msg: msg}
+		return created(transform(err, &withStack{w, callersFor(err)}))
+	}
+	st := a.callers()
+	a.mu.Lock()
+	m := a.messages.alloc()
+	m.cause, m.msg = err, msg
+	w := a.stacks.alloc()
+	w.error, w.stack = m, st
+	a.mu.Unlock()
+	return transform(err, w)
+}
+
+// Free releases the errors created by a, clearing their memory for reuse
+// by the errors a creates next. See Arena for the constraints this places
+// on the errors created by a.
+func (a *Arena) Free() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if atomic.LoadInt32(&chainIndexSize) > 0 {
+		// The memory of the errors is reused by errors with other chains.
+		a.fundamentals.each(func(f *fundamental) { evictChainIndex(f) })
+		a.stacks.each(func(w *withStack) { evictChainIndex(w) })
+		a.messages.each(func(m *withMessage) { evictChainIndex(m) })
+	}
+	a.fundamentals.reset()
+	a.stacks.reset()
+	a.messages.reset()
+	a.traces.reset()
+	a.pcs.reset()
+}
+
+// callers returns the stack trace of the caller of its caller, as the
+// function callers does, allocated from a.
+func (a *Arena) callers() *stack {
+	buf := pcBuffers.Get().(*[defaultStackDepth]uintptr)
+	pcs := captureStack(4, buf[:])
+	a.mu.Lock()
+	st := a.traces.alloc()
+	st.pcs = a.pcs.alloc(len(pcs))
+	copy(st.pcs, pcs)
+	a.mu.Unlock()
+	pcBuffers.Put(buf)
+	return st
+}
+
+// A slab allocates values of type T in chunks of arenaChunk.
+type slab[T any] struct {
+	chunks [][]T
+	used   int // values allocated
+}
+
+func (s *slab[T]) alloc() *T {
+	i, j := s.used/arenaChunk, s.used%arenaChunk
+	if i == len(s.chunks) {
+		s.chunks = append(s.chunks, make([]T, arenaChunk))
+	}
+	s.used++
+	return &s.chunks[i][j]
+}
+
+// each calls fn with each value allocated.
+func (s *slab[T]) each(fn func(*T)) {
+	for i := 0; i < s.used; i++ {
+		fn(&s.chunks[i/arenaChunk][i%arenaChunk])
+	}
+}
+
+// reset clears the values allocated, so that they no longer reference
+// other memory, and makes them available again.
+func (s *slab[T]) reset() {
+	for i := 0; i*arenaChunk < s.used; i++ {
+		clear(s.chunks[i])
+	}
+	s.used = 0
+}
+
+// A pcSlab allocates program counter slices of any length in chunks.
+type pcSlab struct {
+	chunks [][]uintptr
+	chunk  int // index of the chunk allocated from
+	used   int // program counters allocated from the chunk
+}
+
+func (s *pcSlab) alloc(n int) []uintptr {
+	if n == 0 {
+		return nil
+	}
+	const size = arenaChunk * defaultStackDepth
+	if n > size {
+		return make([]uintptr, n)
+	}
+	if s.chunk < len(s.chunks) && s.used+n > size {
+		s.chunk, s.used = s.chunk+1, 0
+	}
+	if s.chunk == len(s.chunks) {
+		s.chunks = append(s.chunks, make([]uintptr, size))
+	}
+	pcs := s.chunks[s.chunk][s.used : s.used+n : s.used+n]
+	s.used += n
+	return pcs
+}
+
+func (s *pcSlab) reset() { s.chunk, s.used = 0, 0 }
@@ -0,0 +1,177 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestArena(t *testing.T) {
+	a := NewArena()
+	defer a.Free()
+
+	if a.Wrap(nil, "wrap") != nil || a.Wrapf(nil, "wrap") != nil || a.WithStack(nil) != nil || a.WithMessage(nil, "msg") != nil {
+		t.Errorf("Arena: wrapping nil returned non-nil")
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{a.New("error"), "error"},
+		{a.Errorf("error %d", 7), "error 7"},
+		{a.WithStack(io.EOF), "EOF"},
+		{a.WithMessage(io.EOF, "read"), "read: EOF"},
+		{a.Wrap(io.EOF, "read"), "read: EOF"},
+		{a.Wrapf(a.New("error"), "read %s", "config"), "read config: error"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if st := originStack(tt.err); i != 3 && (len(st) == 0 || st[0].name() != "github.com/peakle/errors.TestArena") {
+			t.Errorf("test %d: got stack trace %v, want one starting in TestArena", i+1, st)
+		}
+	}
+	if !Is(tests[4].err, io.EOF) {
+		t.Errorf("Is(a.Wrap(io.EOF), io.EOF): got false, want true")
+	}
+}
+
+func TestArenaFree(t *testing.T) {
+	var a Arena
+	first := a.New("first")
+	for i := 0; i < 2*arenaChunk; i++ {
+		a.Wrap(io.EOF, "wrap")
+	}
+	a.Free()
+	if again := a.New("again"); again != first {
+		t.Errorf("New after Free: memory not reused")
+	}
+	if got := first.Error(); got != "again" {
+		t.Errorf("error created before Free: got %q, want it overwritten", got)
+	}
+	a.Free()
+
+	// Free releases the errors wrapped by the errors of the arena.
+	collected := make(chan struct{})
+	func() {
+		cause := &fundamental{msg: "cause"}
+		runtime.SetFinalizer(cause, func(*fundamental) { close(collected) })
+		a.Wrap(cause, "wrap")
+	}()
+	a.Free()
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		select {
+		case <-collected:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Errorf("Free: wrapped error not collected")
+}
+
+func TestArenaConcurrent(t *testing.T) {
+	a := NewArena()
+	defer a.Free()
+
+	var wg sync.WaitGroup
+	errs := make([][]error, 8)
+	for g := range errs {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				errs[g] = append(errs[g], a.Wrapf(io.EOF, "worker %d item %d", g, i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for g := range errs {
+		for i, err := range errs[g] {
+			if got, want := err.Error(), fmt.Sprintf("worker %d item %d: EOF", g, i); got != want {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		}
+	}
+}
+
+func TestArenaAllocs(t *testing.T) {
+	a := NewArena()
+	defer a.Free()
+
+	a.Wrap(io.EOF, "read")
+	if n := testing.AllocsPerRun(100, func() { a.Wrap(io.EOF, "read") }); n >= 1 {
+		t.Errorf("Arena.Wrap: got %v allocations, want fewer than 1", n)
+	}
+}
+
+func TestArenaHooks(t *testing.T) {
+	var got []error
+	RegisterHook(func(err error) { got = append(got, err) })
+	defer resetHooks()
+
+	a := NewArena()
+	a.New("error")
+	a.Errorf("error %d", 1)
+	a.WithStack(io.EOF)
+	a.WithMessage(io.EOF, "read")
+	a.Wrap(io.EOF, "read")
+	a.Wrapf(io.EOF, "read %d", 1)
+	a.Free()
+	if len(got) != 0 {
+		t.Errorf("hooks called with %d arena errors, want none", len(got))
+	}
+}
+
+func TestArenaFreeChainIndex(t *testing.T) {
+	SetChainIndex(true)
+	defer SetChainIndex(false)
+
+	a := NewArena()
+	defer a.Free()
+	errA, errB := fmt.Errorf("a"), fmt.Errorf("b")
+
+	e1 := a.Wrap(errA, "x")
+	if !Is(e1, errA) {
+		t.Fatalf("Is(e1, errA): got false, want true")
+	}
+	a.Free()
+	e2 := a.Wrap(errB, "y")
+	if Is(e2, errA) || !Is(e2, errB) {
+		t.Errorf("after Free: got Is(e2, errA) = %t, Is(e2, errB) = %t, want false, true", Is(e2, errA), Is(e2, errB))
+	}
+}
+
+func TestArenaNil(t *testing.T) {
+	var a *Arena
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{a.New("error"), "error"},
+		{a.Errorf("error %d", 1), "error 1"},
+		{a.WithStack(io.EOF), "EOF"},
+		{a.WithMessage(io.EOF, "read"), "read: EOF"},
+		{a.Wrap(io.EOF, "read"), "read: EOF"},
+		{a.Wrapf(io.EOF, "read %d", 1), "read 1: EOF"},
+	}
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+		if i == 3 {
+			continue
+		}
+		if st := tt.err.(interface{ StackTrace() StackTrace }).StackTrace(); len(st) == 0 || st[0].Func() != "github.com/peakle/errors.TestArenaNil" {
+			t.Errorf("test %d: got stack trace %v, want one starting at the caller", i+1, st)
+		}
+	}
+	a.Free()
+}
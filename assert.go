@@ -0,0 +1,61 @@
+package errors
+
+import "sync/atomic"
+
+// assertPanics is non-zero when failed assertions panic.
+var assertPanics int32
+
+// SetAssertPanics controls whether Assert and AssertNoError panic with the
+// error they would otherwise return. Enabling it in development and tests
+// makes invariant violations impossible to miss; it is disabled by default.
+func SetAssertPanics(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&assertPanics, v)
+}
+
+// Assert returns nil if cond holds. Otherwise it returns an error of kind
+// Internal whose message is "assertion failed: " followed by the formatted
+// message, with a stack trace recorded at the point Assert was called.
+//
+//	if err := errors.Assert(n >= 0, "negative count %d", n); err != nil {
+//		return err
+//	}
+func Assert(cond bool, format string, args ...interface{}) error {
+	if cond {
+		return nil
+	}
+	return assertFailed(created(&withKind{
+		cause: newFundamental("assertion failed: " + sprintf(format, args...)),
+		kind:  Internal,
+	}))
+}
+
+// AssertNoError returns nil if err is nil. Otherwise it returns err wrapped
+// with the message "assertion failed" and the kind Internal, with a stack
+// trace recorded at the point AssertNoError was called.
+func AssertNoError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return assertFailed(created(transform(err, &withKind{
+		cause: &withStack{
+			&withMessage{
+				cause: err,
+				msg:   "assertion failed",
+			},
+			callers(),
+		},
+		kind: Internal,
+	})))
+}
+
+// assertFailed returns err, or panics with it if assertions panic.
+func assertFailed(err error) error {
+	if atomic.LoadInt32(&assertPanics) != 0 {
+		panic(err)
+	}
+	return err
+}
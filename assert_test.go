@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestAssert(t *testing.T) {
+	if err := Assert(true, "unreachable"); err != nil {
+		t.Errorf("Assert(true): got %v, want nil", err)
+	}
+	if err := AssertNoError(nil); err != nil {
+		t.Errorf("AssertNoError(nil): got %v, want nil", err)
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Assert(false, "negative count %d", -1), "assertion failed: negative count -1"},
+		{AssertNoError(io.EOF), "assertion failed: EOF"},
+	}
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if got := KindOf(tt.err); got != Internal {
+			t.Errorf("test %d: KindOf(): got %v, want %v", i+1, got, Internal)
+		}
+		want := "\ngithub.com/peakle/errors.TestAssert\n\t.+/assert_test.go:\\d+\n"
+		if got := fmt.Sprintf("%+v", tt.err); !regexp.MustCompile(want).MatchString(got) {
+			t.Errorf("test %d: stack not recorded at call site:\n%s", i+1, got)
+		}
+	}
+	if !Is(AssertNoError(io.EOF), io.EOF) {
+		t.Error("Is(AssertNoError(io.EOF), io.EOF): got false, want true")
+	}
+}
+
+func TestSetAssertPanics(t *testing.T) {
+	SetAssertPanics(true)
+	defer SetAssertPanics(false)
+
+	defer func() {
+		err, _ := recover().(error)
+		if KindOf(err) != Internal {
+			t.Errorf("Assert(false) with panics enabled: recovered %v", err)
+		}
+	}()
+	Assert(false, "boom")
+	t.Error("Assert(false) with panics enabled did not panic")
+}
@@ -108,3 +108,136 @@ func BenchmarkStackFormatting(b *testing.B) {
 	}
 	GlobalE = stackStr
 }
+
+func BenchmarkWrap(b *testing.B) {
+	cause := stderrors.New("cause")
+	runs := []struct {
+		name string
+		wrap func(error) error
+	}{
+		{"Wrap", func(err error) error { return Wrap(err, "context") }},
+		{"WrapNoStack", func(err error) error { return WrapNoStack(err, "context") }},
+	}
+	for _, r := range runs {
+		b.Run(r.name, func(b *testing.B) {
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err = r.wrap(cause)
+			}
+			b.StopTimer()
+			GlobalE = err
+		})
+	}
+}
+
+func BenchmarkErrorf(b *testing.B) {
+	runs := []struct {
+		name string
+		args []interface{}
+	}{
+		{"constant", nil},
+		{"formatted", []interface{}{42}},
+	}
+	for _, r := range runs {
+		format := "request failed"
+		if r.args != nil {
+			format = "request %d failed"
+		}
+		b.Run(r.name, func(b *testing.B) {
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err = Errorf(format, r.args...)
+			}
+			b.StopTimer()
+			GlobalE = err
+		})
+	}
+}
+
+func BenchmarkFrameMarshalText(b *testing.B) {
+	st := yesErrors(0, 10).(*fundamental).StackTrace()
+	var text []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, f := range st {
+			text, _ = f.MarshalText()
+		}
+	}
+	b.StopTimer()
+	GlobalE = text
+}
+
+func BenchmarkNew(b *testing.B) {
+	var err error
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err = New("error")
+	}
+	b.StopTimer()
+	GlobalE = err
+}
+
+func BenchmarkFrameFormat(b *testing.B) {
+	f := yesErrors(0, 1).(*fundamental).StackTrace()[0]
+	var s string
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s = fmt.Sprintf("%+v", f)
+	}
+	b.StopTimer()
+	GlobalE = s
+}
+
+func BenchmarkArenaWrap(b *testing.B) {
+	a := NewArena()
+	cause := New("cause")
+	var err error
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err = a.Wrap(cause, "error")
+		if i%1000 == 999 {
+			a.Free()
+		}
+	}
+	b.StopTimer()
+	GlobalE = err
+}
+
+var sentinels = func() []error {
+	errs := make([]error, 8)
+	for i := range errs {
+		errs[i] = New("sentinel")
+	}
+	return errs
+}()
+
+func BenchmarkIsDeepChain(b *testing.B) {
+	for _, tt := range []struct {
+		name  string
+		index bool
+	}{
+		{"walk", false},
+		{"index", true},
+	} {
+		b.Run(tt.name, func(b *testing.B) {
+			SetChainIndex(tt.index)
+			defer SetChainIndex(false)
+			err := New("cause")
+			for i := 0; i < 12; i++ {
+				err = WithCode(Wrap(err, "layer"), "CODE")
+			}
+			var ok bool
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, s := range sentinels {
+					ok = Is(err, s)
+				}
+			}
+			b.StopTimer()
+			GlobalE = ok
+		})
+	}
+}
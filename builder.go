@@ -0,0 +1,131 @@
+package errors
+
+// A Builder composes an error carrying several attachments without nesting
+// calls to the individual With functions:
+//
+//	err := errors.Build("payment failed").
+//		Code("PAY_DECLINED").
+//		Field("order", id).
+//		HTTPStatus(402).
+//		Wrap(cause).
+//		Err()
+//
+// A Builder must not be copied or reused after Err has been called. The
+// methods of a nil *Builder do nothing, and its Err returns nil.
+type Builder struct {
+	msg      string
+	cause    error
+	code     string
+	kind     Kind
+	severity Severity
+	status   int
+	fields   []field
+	stack    *stack
+}
+
+// Build starts building an error with the supplied message. The stack trace
+// is recorded at the point Build is called.
+func Build(message string) *Builder {
+	return &Builder{
+		msg:   message,
+		stack: callers(),
+	}
+}
+
+// Buildf starts building an error with the message formatted according to a
+// format specifier. The stack trace is recorded at the point Buildf is
+// called.
+func Buildf(format string, args ...interface{}) *Builder {
+	return &Builder{
+		msg:   sprintf(format, args...),
+		stack: callers(),
+	}
+}
+
+// Wrap sets the cause of the error. A nil cause builds an error without one.
+func (b *Builder) Wrap(cause error) *Builder {
+	if b != nil {
+		b.cause = cause
+	}
+	return b
+}
+
+// Code sets the error code, as with WithCode.
+func (b *Builder) Code(code string) *Builder {
+	if b != nil {
+		b.code = code
+	}
+	return b
+}
+
+// Kind sets the kind, as with WithKind.
+func (b *Builder) Kind(kind Kind) *Builder {
+	if b != nil {
+		b.kind = kind
+	}
+	return b
+}
+
+// Severity sets the severity, as with WithSeverity.
+func (b *Builder) Severity(severity Severity) *Builder {
+	if b != nil {
+		b.severity = severity
+	}
+	return b
+}
+
+// HTTPStatus sets the HTTP status code, as with WithHTTPStatus.
+func (b *Builder) HTTPStatus(status int) *Builder {
+	if b != nil {
+		b.status = status
+	}
+	return b
+}
+
+// Field adds a field, as with WithField.
+func (b *Builder) Field(key string, value interface{}) *Builder {
+	if b != nil {
+		b.fields = append(b.fields, field{key: key, value: value})
+	}
+	return b
+}
+
+// SensitiveField adds a sensitive field, as with WithSensitiveField.
+func (b *Builder) SensitiveField(key string, value interface{}) *Builder {
+	if b != nil {
+		b.fields = append(b.fields, field{key: key, value: value, sensitive: true})
+	}
+	return b
+}
+
+// Err returns the built error, or nil if b is nil.
+func (b *Builder) Err() error {
+	if b == nil {
+		return nil
+	}
+	var err error
+	if b.cause == nil {
+		err = &fundamental{msg: b.msg, stack: b.stack}
+	} else {
+		err = &withStack{&withMessage{cause: b.cause, msg: b.msg}, b.stack}
+	}
+	if len(b.fields) > 0 {
+		err = &withFields{cause: err, fields: b.fields}
+	}
+	if b.code != "" {
+		err = &withCode{cause: err, code: b.code}
+	}
+	if b.kind != Unknown {
+		err = &withKind{cause: err, kind: b.kind}
+	}
+	if b.severity != SeverityUnknown {
+		err = &withSeverity{cause: err, severity: b.severity}
+	}
+	if b.status != 0 {
+		err = &withHTTPStatus{cause: err, status: b.status}
+	}
+	if b.cause != nil {
+		err = transform(b.cause, err)
+	}
+	return created(err)
+}
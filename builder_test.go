@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	err := Build("payment failed").
+		Code("PAY_DECLINED").
+		Kind(InvalidArgument).
+		Severity(SeverityWarning).
+		Field("order", 42).
+		SensitiveField("card", "4111").
+		HTTPStatus(402).
+		Wrap(io.EOF).
+		Err()
+
+	if got, want := err.Error(), "payment failed: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if !Is(err, io.EOF) {
+		t.Error("Is(err, io.EOF): got false, want true")
+	}
+	if got := Code(err); got != "PAY_DECLINED" {
+		t.Errorf("Code(): got %q", got)
+	}
+	if got := KindOf(err); got != InvalidArgument {
+		t.Errorf("KindOf(): got %v", got)
+	}
+	if got := SeverityOf(err); got != SeverityWarning {
+		t.Errorf("SeverityOf(): got %v", got)
+	}
+	if got := HTTPStatus(err); got != 402 {
+		t.Errorf("HTTPStatus(): got %d", got)
+	}
+	if got, want := Fields(err), map[string]interface{}{"order": 42, "card": "[REDACTED]"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields(): got %v, want %v", got, want)
+	}
+	want := "\ngithub.com/peakle/errors.TestBuild\n\t.+/builder_test.go:12\n"
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("stack not recorded at Build:\n%s", got)
+	}
+}
+
+func TestBuildf(t *testing.T) {
+	err := Buildf("user %d not found", 7).Kind(NotFound).Err()
+	if got, want := err.Error(), "user 7 not found"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got := KindOf(err); got != NotFound {
+		t.Errorf("KindOf(): got %v", got)
+	}
+	if got := Code(err); got != "" {
+		t.Errorf("Code(): got %q, want none", got)
+	}
+}
@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// stampBuild is non-zero while serialized errors carry build information.
+var stampBuild int32
+
+// SetBuildInfo sets whether errors rendered in the JSON style or by Logfmt
+// carry the module path and version of the running program, and the VCS
+// revision and dirty flag it was built from, as reported by
+// debug.ReadBuildInfo, so that errors aggregated from fleets running mixed
+// builds can be attributed to a build. It is disabled by default. Programs
+// built without module or VCS information carry only what is known.
+func SetBuildInfo(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&stampBuild, v)
+}
+
+// buildStamp is the build information carried by serialized errors.
+type buildStamp struct {
+	Module   string `json:"module,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	Dirty    bool   `json:"dirty,omitempty"`
+}
+
+var (
+	buildOnce sync.Once
+	build     *buildStamp
+)
+
+// currentBuild returns the build information serialized errors carry, or
+// nil if SetBuildInfo is disabled or none is known.
+func currentBuild() *buildStamp {
+	if atomic.LoadInt32(&stampBuild) == 0 {
+		return nil
+	}
+	buildOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			build = newBuildStamp(info)
+		}
+	})
+	return build
+}
+
+// newBuildStamp returns the build information in info, or nil if there is
+// none.
+func newBuildStamp(info *debug.BuildInfo) *buildStamp {
+	b := buildStamp{
+		Module:  info.Main.Path,
+		Version: info.Main.Version,
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Revision = s.Value
+		case "vcs.modified":
+			b.Dirty, _ = strconv.ParseBool(s.Value)
+		}
+	}
+	if b == (buildStamp{}) {
+		return nil
+	}
+	return &b
+}
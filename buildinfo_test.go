@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestNewBuildStamp(t *testing.T) {
+	tests := []struct {
+		info *debug.BuildInfo
+		want *buildStamp
+	}{{
+		info: &debug.BuildInfo{},
+		want: nil,
+	}, {
+		info: &debug.BuildInfo{Main: debug.Module{Path: "example.com/app", Version: "v1.2.3"}},
+		want: &buildStamp{Module: "example.com/app", Version: "v1.2.3"},
+	}, {
+		info: &debug.BuildInfo{
+			Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs", Value: "git"},
+				{Key: "vcs.revision", Value: "0123abcd"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		},
+		want: &buildStamp{Module: "example.com/app", Version: "(devel)", Revision: "0123abcd", Dirty: true},
+	}}
+
+	for i, tt := range tests {
+		if got := newBuildStamp(tt.info); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: newBuildStamp(): got %+v, want %+v", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestSetBuildInfo(t *testing.T) {
+	defer SetBuildInfo(false)
+	defer SetFormatStyle(StyleClassic)
+
+	if got := Logfmt(io.EOF); strings.Contains(got, "build.") {
+		t.Errorf("Logfmt(): got %q with build information disabled", got)
+	}
+
+	SetBuildInfo(true)
+	build := currentBuild()
+	if build == nil {
+		t.Skip("no build information in test binary")
+	}
+	if got := Logfmt(io.EOF); !strings.Contains(got, " build.module="+build.Module) {
+		t.Errorf("Logfmt(): got %q, want build.module=%s", got, build.Module)
+	}
+
+	SetFormatStyle(StyleJSON)
+	var v struct {
+		Build *buildStamp `json:"build"`
+	}
+	if err := json.Unmarshal([]byte(fmt.Sprintf("%+v", New("error"))), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v.Build, build) {
+		t.Errorf("StyleJSON: got build %+v, want %+v", v.Build, build)
+	}
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+)
+
+// WithCaller annotates err with the frame of the function that called
+// WithCaller. Recording a single frame is much cheaper than recording a full
+// stack trace, which makes WithCaller suitable for hot paths that still need
+// to report where an error happened.
+// If err is nil, WithCaller returns nil.
+func WithCaller(err error) error {
+	if err == nil {
+		return nil
+	}
+	var pc [1]uintptr
+	if StackCaptureEnabled() {
+		runtime.Callers(2, pc[:])
+	}
+	return created(transform(err, &withCaller{
+		cause: err,
+		frame: Frame(pc[0]),
+	}))
+}
+
+// Caller returns the Frame of the function that called Caller, or of one of
+// its callers: the argument skip is the number of stack frames to ascend,
+// with 0 identifying the caller of Caller. Caller lets code outside of errors,
+// such as loggers, report where it was called from in the same format as
+// stack traces. Caller returns the zero Frame, which formats as "unknown",
+// if there is no such frame.
+func Caller(skip int) Frame {
+	var pc [1]uintptr
+	if runtime.Callers(skip+2, pc[:]) == 0 {
+		return 0
+	}
+	return Frame(pc[0])
+}
+
+type withCaller struct {
+	cause error
+	frame Frame
+}
+
+func (w *withCaller) Error() string { return redact(w.cause.Error()) }
+func (w *withCaller) Cause() error  { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withCaller) Unwrap() error { return w.cause }
+
+func (w *withCaller) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v", w.Cause()))
+			if w.frame != 0 {
+				name, file, line := w.frame.resolve()
+				io.WriteString(s, "\n"+file+":"+strconv.Itoa(line)+" "+name)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
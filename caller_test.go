@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWithCallerNil(t *testing.T) {
+	if got := WithCaller(nil); got != nil {
+		t.Errorf("WithCaller(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestFormatWithCaller(t *testing.T) {
+	err := WithCaller(io.EOF)
+	for format, want := range map[string]string{
+		"%s":  "^EOF$",
+		"%v":  "^EOF$",
+		"%+v": `^EOF\n.+/caller_test.go:18 .+\.TestFormatWithCaller$`,
+		"%q":  `^"EOF"$`,
+	} {
+		got := fmt.Sprintf(format, err)
+		if !regexp.MustCompile(want).MatchString(got) {
+			t.Errorf("fmt.Sprintf(%q, err): got %q, want match %q", format, got, want)
+		}
+	}
+}
+
+func TestWithCallerCaptureDisabled(t *testing.T) {
+	DisableStackCapture()
+	err := WithCaller(io.EOF)
+	EnableStackCapture()
+	if got := fmt.Sprintf("%+v", err); got != "EOF" {
+		t.Errorf("fmt.Sprintf(%%+v, err) with capture disabled: got %q, want %q", got, "EOF")
+	}
+}
+
+func TestCaller(t *testing.T) {
+	tests := []struct {
+		Frame
+		format string
+		want   string
+	}{
+		{Caller(0), "%v", `^caller_test.go:47$`},
+		{Caller(0), "%n", `^TestCaller$`},
+		{func() Frame { return Caller(1) }(), "%v", `^caller_test.go:49$`},
+		{Caller(1 << 20), "%v", `^unknown:0$`},
+	}
+
+	for i, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.Frame); !regexp.MustCompile(tt.want).MatchString(got) {
+			t.Errorf("test %d: fmt.Sprintf(%q, frame): got %q, want match %q", i+1, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestFrameAccessors(t *testing.T) {
+	f := Caller(0)
+	if got := f.Line(); got != 61 {
+		t.Errorf("Frame.Line(): got %d, want %d", got, 61)
+	}
+	if got := f.File(); !strings.HasSuffix(got, "/caller_test.go") {
+		t.Errorf("Frame.File(): got %q, want suffix %q", got, "/caller_test.go")
+	}
+	if got := f.Func(); !strings.HasSuffix(got, ".TestFrameAccessors") {
+		t.Errorf("Frame.Func(): got %q, want suffix %q", got, ".TestFrameAccessors")
+	}
+	if got, want := f.PC(), uintptr(f)-1; got != want {
+		t.Errorf("Frame.PC(): got %#x, want %#x", got, want)
+	}
+
+	var zero Frame
+	if got := zero.File(); got != "unknown" {
+		t.Errorf("Frame(0).File(): got %q, want %q", got, "unknown")
+	}
+	if got := zero.Line(); got != 0 {
+		t.Errorf("Frame(0).Line(): got %d, want 0", got)
+	}
+	if got := zero.Func(); got != "unknown" {
+		t.Errorf("Frame(0).Func(): got %q, want %q", got, "unknown")
+	}
+	if got := zero.PC(); got != 0 {
+		t.Errorf("Frame(0).PC(): got %#x, want 0", got)
+	}
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"context"
+	"reflect"
+)
+
+// IsCanceled reports whether any error in err's chain, including the
+// branches of errors wrapping several, reports a cancellation: the error
+// context.Canceled, an error of kind Canceled, or a gRPC status error with
+// the code Canceled. Request handlers can use it to skip logging requests
+// abandoned by their clients.
+func IsCanceled(err error) bool {
+	return anyLayer(err, context.Canceled, Canceled, kindGRPCCode[Canceled])
+}
+
+// IsDeadlineExceeded reports whether any error in err's chain, including the
+// branches of errors wrapping several, reports an exceeded deadline: the
+// error context.DeadlineExceeded, an error of kind DeadlineExceeded, such
+// as those of NewTimeout, or a gRPC status error with the code
+// DeadlineExceeded.
+func IsDeadlineExceeded(err error) bool {
+	return anyLayer(err, context.DeadlineExceeded, DeadlineExceeded, kindGRPCCode[DeadlineExceeded])
+}
+
+// anyLayer reports whether any error in err's chain is target, is of kind,
+// or is a gRPC status error with the status code code.
+func anyLayer(err, target error, kind Kind, code uint32) bool {
+	type kinder interface {
+		Kind() Kind
+	}
+
+	found := false
+	Walk(err, func(err error) bool {
+		if k, ok := err.(kinder); ok && k.Kind() == kind {
+			found = true
+		}
+		found = found || err == target || grpcStatusCode(err) == code
+		return !found
+	})
+	return found
+}
+
+// grpcStatusCode returns the status code of err if it is a gRPC status
+// error, as returned by package google.golang.org/grpc/status, or zero (OK)
+// otherwise. The status is read through its GRPCStatus and Code methods, so
+// that this package does not depend on gRPC.
+func grpcStatusCode(err error) uint32 {
+	m := reflect.ValueOf(err).MethodByName("GRPCStatus")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return 0
+	}
+	s := m.Call(nil)[0]
+	if s.Kind() == reflect.Ptr && s.IsNil() {
+		return 0
+	}
+	code := s.MethodByName("Code")
+	if !code.IsValid() || code.Type().NumIn() != 0 || code.Type().NumOut() != 1 {
+		return 0
+	}
+	c := code.Call(nil)[0]
+	if c.Kind() != reflect.Uint32 {
+		return 0
+	}
+	return uint32(c.Uint())
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// grpcCode and grpcStatus mimic the status errors of
+// google.golang.org/grpc/status.
+type grpcCode uint32
+
+type grpcStatus struct{ code grpcCode }
+
+func (s *grpcStatus) Code() grpcCode { return s.code }
+
+type grpcError struct{ s *grpcStatus }
+
+func (e *grpcError) Error() string           { return "rpc error" }
+func (e *grpcError) GRPCStatus() *grpcStatus { return e.s }
+
+func newGRPCError(code grpcCode) error { return &grpcError{&grpcStatus{code}} }
+
+func TestIsCanceled(t *testing.T) {
+	tests := []struct {
+		err                error
+		canceled, deadline bool
+	}{
+		{nil, false, false},
+		{io.EOF, false, false},
+		{context.Canceled, true, false},
+		{Wrap(context.Canceled, "query"), true, false},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), false, true},
+		{Join(io.EOF, Wrap(context.Canceled, "query")), true, false},
+		{WithKind(io.EOF, Canceled), true, false},
+		{Wrap(WithKind(io.EOF, DeadlineExceeded), "query"), false, true},
+		{NewTimeout("query", 2, 1), false, true},
+		{Wrap(newGRPCError(1), "call"), true, false},
+		{Wrap(newGRPCError(4), "call"), false, true},
+		{newGRPCError(14), false, false},
+		{&grpcError{}, false, false},
+	}
+
+	for i, tt := range tests {
+		if got := IsCanceled(tt.err); got != tt.canceled {
+			t.Errorf("test %d: IsCanceled(%v): got %t, want %t", i+1, tt.err, got, tt.canceled)
+		}
+		if got := IsDeadlineExceeded(tt.err); got != tt.deadline {
+			t.Errorf("test %d: IsDeadlineExceeded(%v): got %t, want %t", i+1, tt.err, got, tt.deadline)
+		}
+	}
+}
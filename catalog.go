@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+)
+
+var (
+	catalogMu sync.Mutex
+	catalog   []*Template
+)
+
+// A CatalogEntry describes a Template defined with Define.
+type CatalogEntry struct {
+	Code       string
+	Format     string
+	Kind       Kind
+	HTTPStatus int    // as reported by HTTPStatus for occurrences
+	GRPCCode   uint32 // as reported by GRPCCode for occurrences
+}
+
+// Catalog returns a description of every Template defined with Define,
+// sorted by code, for instance to generate a reference of the errors a
+// service can return.
+func Catalog() []CatalogEntry {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	entries := make([]CatalogEntry, len(catalog))
+	for i, t := range catalog {
+		status := t.status
+		if status == 0 {
+			status = http.StatusInternalServerError
+			if s, ok := kindStatus[t.kind]; ok {
+				status = s
+			}
+		}
+		entries[i] = CatalogEntry{
+			Code:       t.code,
+			Format:     t.format,
+			Kind:       t.kind,
+			HTTPStatus: status,
+			GRPCCode:   kindToGRPCCode(t.kind),
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries
+}
+
+// ValidateCatalog returns an error for every code shared by more than one
+// Template, joined as by Join, or nil if every code is unique. Services
+// typically call it at startup.
+func ValidateCatalog() error {
+	var errs []error
+	entries := Catalog()
+	for i := 1; i < len(entries); i++ {
+		if entries[i].Code == entries[i-1].Code && (i == 1 || entries[i-2].Code != entries[i].Code) {
+			errs = append(errs, Errorf("error code %q defined more than once", entries[i].Code))
+		}
+	}
+	return Join(errs...)
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+// isolateCatalog empties the catalog for the duration of the test, so that
+// it only holds the templates the test defines.
+func isolateCatalog(t *testing.T) {
+	catalogMu.Lock()
+	saved := catalog
+	catalog = nil
+	catalogMu.Unlock()
+	t.Cleanup(func() {
+		catalogMu.Lock()
+		catalog = saved
+		catalogMu.Unlock()
+	})
+}
+
+func TestCatalog(t *testing.T) {
+	isolateCatalog(t)
+	Define("USER_NOT_FOUND", "user %s not found").WithKind(NotFound)
+	Define("READ_FAILED", "reading %s").WithHTTPStatus(502)
+
+	want := []CatalogEntry{
+		{Code: "READ_FAILED", Format: "reading %s", Kind: Unknown, HTTPStatus: 502, GRPCCode: 2},
+		{Code: "USER_NOT_FOUND", Format: "user %s not found", Kind: NotFound, HTTPStatus: 404, GRPCCode: 5},
+	}
+	if got := Catalog(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Catalog(): got %+v, want %+v", got, want)
+	}
+}
+
+func TestValidateCatalog(t *testing.T) {
+	isolateCatalog(t)
+	Define("UNIQUE", "unique")
+	if err := ValidateCatalog(); err != nil {
+		t.Fatalf("ValidateCatalog(): got %v, want nil", err)
+	}
+
+	Define("DUPLICATE", "first")
+	Define("DUPLICATE", "second")
+	Define("DUPLICATE", "third")
+
+	err := ValidateCatalog()
+	if got, want := err.Error(), `error code "DUPLICATE" defined more than once`; got != want {
+		t.Errorf("ValidateCatalog(): got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package errors
+
+// Chain returns every error in err's chain, starting with err itself and
+// following Unwrap. Errors that wrap several errors, such as those returned
+// by Join, are flattened depth first: each wrapped error is followed by its
+// own chain before the next wrapped error is visited.
+// Chain returns nil if err is nil.
+func Chain(err error) []error {
+	var errs []error
+	Walk(err, func(err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+	return errs
+}
+
+// Walk calls fn for every error in err's chain, in the order of Chain,
+// until fn returns false. If the chain loops back on itself, Walk stops
+// following it once the loop is detected, which may be after visiting a few
+// errors of the loop more than once. Walk does nothing if err is nil.
+func Walk(err error, fn func(error) bool) {
+	walk(err, fn, nil)
+}
+
+// walk implements Walk, reporting whether the walk should continue. The
+// errors wrapping several errors that lead to err are in path.
+func walk(err error, fn func(error) bool, path []error) bool {
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if !fn(err) {
+			return false
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			if onPath(err, path) {
+				return true
+			}
+			path = append(path, err)
+			for _, e := range u.Unwrap() {
+				if !walk(e, fn, path) {
+					return false
+				}
+			}
+			return true
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return true
+		}
+	}
+	return true
+}
+
+// Root returns the innermost error in err's chain, following Unwrap through
+// both this package's wrappers and those of other packages. Unlike Cause,
+// Root does not stop at the first error lacking a Cause method. When an
+// error wraps several errors, as those returned by Join do, Root follows the
+// first of them.
+// Root returns nil if err is nil.
+func Root(err error) error {
+	var c cycle
+	for {
+		var next error
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			if errs := u.Unwrap(); len(errs) > 0 {
+				next = errs[0]
+			}
+		case interface{ Unwrap() error }:
+			next = u.Unwrap()
+		}
+		if next == nil || c.seen(next) {
+			return err
+		}
+		err = next
+	}
+}
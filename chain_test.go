@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	a := fmt.Errorf("a: %w", io.EOF)
+	b := io.ErrUnexpectedEOF
+	j := Join(a, b)
+	w := fmt.Errorf("w: %w", j)
+
+	tests := []struct {
+		err  error
+		want []error
+	}{
+		{nil, nil},
+		{io.EOF, []error{io.EOF}},
+		{a, []error{a, io.EOF}},
+		{j, []error{j, a, io.EOF, b}},
+		{w, []error{w, j, a, io.EOF, b}},
+	}
+
+	for i, tt := range tests {
+		if got := Chain(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: Chain(): got %v, want %v", i+1, got, tt.want)
+		}
+	}
+
+	err := Wrap(New("root"), "wrapped")
+	if got := len(Chain(err)); got != 3 {
+		t.Errorf("len(Chain(Wrap(New()))): got %d, want 3", got)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{nil, nil},
+		{io.EOF, io.EOF},
+		{Wrap(io.EOF, "read"), io.EOF},
+		{fmt.Errorf("outer: %w", Wrap(io.EOF, "read")), io.EOF},
+		{WithMessage(fmt.Errorf("inner: %w", io.EOF), "outer"), io.EOF},
+		{Join(Wrap(io.EOF, "first"), io.ErrUnexpectedEOF), io.EOF},
+		{Join(), nil},
+	}
+
+	for i, tt := range tests {
+		if got := Root(tt.err); got != tt.want {
+			t.Errorf("test %d: Root(): got %v, want %v", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	a := Wrap(io.EOF, "a")
+	b := WithCode(io.ErrUnexpectedEOF, "B")
+	err := Join(a, b)
+
+	var got []error
+	Walk(err, func(err error) bool {
+		got = append(got, err)
+		return true
+	})
+	if want := Chain(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk(): visited %v, want %v", got, want)
+	}
+
+	// Stop at the first coded error: the rest of the chain is not visited.
+	got = nil
+	Walk(err, func(err error) bool {
+		got = append(got, err)
+		return Code(err) == ""
+	})
+	if n := len(got); n == 0 || got[n-1] != b {
+		t.Errorf("Walk(): visited %v, want to stop at %v", got, b)
+	}
+
+	Walk(nil, func(err error) bool {
+		t.Errorf("Walk(nil): fn called with %v", err)
+		return true
+	})
+}
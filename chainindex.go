@@ -0,0 +1,228 @@
+package errors
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// maxChainIndexEntries bounds the number of chains indexed at once; the
+// index is emptied when it is full.
+const maxChainIndexEntries = 4096
+
+var (
+	chainIndexEnabled int32
+	chainIndexes      sync.Map // of error to *chainIndex
+	chainIndexSize    int32
+)
+
+// SetChainIndex controls whether Is and As index the chains of the errors of
+// this package they are called with, so that repeated calls with the same
+// error answer in constant time, whatever the depth of its chain, rather
+// than by walking the chain each time. It suits programs wrapping errors in
+// many layers and checking each against many sentinel errors or types:
+//
+//	switch {
+//	case errors.Is(err, ErrNotFound):
+//	case errors.Is(err, ErrConflict):
+//	case errors.Is(err, context.Canceled):
+//	}
+//
+// An index is built the first time Is or As is called with an error, at a
+// cost of a few walks of its chain, and is held until the index of up to
+// 4096 chains is full, which empties it. Until then, the index keeps the
+// errors of the chains it holds, and all they reference, from being
+// garbage collected. The chain of an error must not
+// change once indexed, which holds unless an error of another package in it
+// changes what its Unwrap method returns. The errors created by an Arena are
+// removed from the index when it is freed. Indexing is disabled by default;
+// disabling it empties the index.
+func SetChainIndex(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&chainIndexEnabled, v)
+	if !on {
+		clearChainIndex()
+	}
+}
+
+// clearChainIndex removes all chains from the index.
+func clearChainIndex() {
+	chainIndexes.Range(func(k, _ interface{}) bool {
+		chainIndexes.Delete(k)
+		return true
+	})
+	atomic.StoreInt32(&chainIndexSize, 0)
+}
+
+// evictChainIndex removes the chain of err from the index, if indexed.
+func evictChainIndex(err error) {
+	if _, loaded := chainIndexes.LoadAndDelete(err); loaded {
+		atomic.AddInt32(&chainIndexSize, -1)
+	}
+}
+
+// chainIndex records the errors of a chain, in the order Is and As visit
+// them, as needed to match them against targets without walking the chain.
+type chainIndex struct {
+	// errs holds the errors of the chain which can be hashed.
+	errs map[error]struct{}
+
+	// comparable holds the errors of the chain of comparable types, and
+	// unhashable those of them which cannot be hashed, as they hold values
+	// of types which are not comparable in fields of interface type.
+	comparable, unhashable []error
+
+	// matchers holds the errors of the chain with an Is method.
+	matchers []interface{ Is(error) bool }
+
+	// first holds the first error of each concrete type in the chain.
+	first map[reflect.Type]indexed
+
+	// asers holds the errors of the chain with an As method.
+	asers []indexedAser
+}
+
+// indexed is an error at position pos of a chain.
+type indexed struct {
+	pos int
+	err error
+}
+
+// indexedAser is an error with an As method at position pos of a chain.
+type indexedAser struct {
+	pos int
+	as  interface{ As(interface{}) bool }
+}
+
+// chainIndexFor returns the index of the chain of err if indexing is enabled
+// and err was produced by this package, or nil otherwise.
+func chainIndexFor(err error) *chainIndex {
+	if atomic.LoadInt32(&chainIndexEnabled) == 0 || !isOwn(err) {
+		return nil
+	}
+	if x, ok := chainIndexes.Load(err); ok {
+		return x.(*chainIndex)
+	}
+	if atomic.LoadInt32(&chainIndexSize) >= maxChainIndexEntries {
+		clearChainIndex()
+	}
+	x, loaded := chainIndexes.LoadOrStore(err, newChainIndex(err))
+	if !loaded {
+		atomic.AddInt32(&chainIndexSize, 1)
+	}
+	return x.(*chainIndex)
+}
+
+// newChainIndex indexes the chain of err.
+func newChainIndex(err error) *chainIndex {
+	x := &chainIndex{
+		errs:  make(map[error]struct{}),
+		first: make(map[reflect.Type]indexed),
+	}
+	pos := 0
+	Walk(err, func(err error) bool {
+		typ := reflect.TypeOf(err)
+		if typ.Comparable() {
+			x.comparable = append(x.comparable, err)
+			if !x.add(err) {
+				x.unhashable = append(x.unhashable, err)
+			}
+		}
+		if _, ok := x.first[typ]; !ok {
+			x.first[typ] = indexed{pos, err}
+		}
+		if m, ok := err.(interface{ Is(error) bool }); ok {
+			x.matchers = append(x.matchers, m)
+		}
+		if a, ok := err.(interface{ As(interface{}) bool }); ok {
+			x.asers = append(x.asers, indexedAser{pos, a})
+		}
+		pos++
+		return true
+	})
+	return x
+}
+
+// add adds err, whose type is comparable, to x.errs, and reports whether
+// it could be hashed.
+func (x *chainIndex) add(err error) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	x.errs[err] = struct{}{}
+	return true
+}
+
+// lookup reports whether target, whose type is comparable, is in x.errs,
+// and whether it could be hashed.
+func (x *chainIndex) lookup(target error) (found, ok bool) {
+	defer func() {
+		if recover() != nil {
+			found, ok = false, false
+		}
+	}()
+	_, found = x.errs[target]
+	return found, true
+}
+
+// is implements Is for a non-nil target.
+func (x *chainIndex) is(target error) bool {
+	if reflect.TypeOf(target).Comparable() {
+		// Errors which cannot be hashed are compared as by the errors
+		// package.
+		found, ok := x.lookup(target)
+		if found {
+			return true
+		}
+		others := x.unhashable
+		if !ok {
+			others = x.comparable
+		}
+		for _, err := range others {
+			if err == target {
+				return true
+			}
+		}
+	}
+	for _, m := range x.matchers {
+		if m.Is(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// as implements As for target, a non-nil pointer to a type implementing
+// error which is not an interface type.
+func (x *chainIndex) as(target interface{}) bool {
+	v := reflect.ValueOf(target)
+	first, found := x.first[v.Type().Elem()]
+	for _, a := range x.asers {
+		if found && a.pos >= first.pos {
+			break
+		}
+		if a.as.As(target) {
+			return true
+		}
+	}
+	if found {
+		v.Elem().Set(reflect.ValueOf(first.err))
+	}
+	return found
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// indexableTarget reports whether As can use the index of a chain to find an
+// error matching target. Other targets are left to the errors package, which
+// also reports invalid ones.
+func indexableTarget(target interface{}) bool {
+	typ := reflect.TypeOf(target)
+	return typ != nil && typ.Kind() == reflect.Ptr && !reflect.ValueOf(target).IsNil() &&
+		typ.Elem().Kind() != reflect.Interface && typ.Elem().Implements(errorType)
+}
@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// asCode is an error which can be converted to a *withCode by As.
+type asCode struct{ err error }
+
+func (e asCode) Error() string { return e.err.Error() }
+func (e asCode) Unwrap() error { return e.err }
+func (e asCode) As(target interface{}) bool {
+	if p, ok := target.(**withCode); ok {
+		*p = &withCode{cause: e.err, code: "FROM_AS"}
+		return true
+	}
+	return false
+}
+
+func TestSetChainIndex(t *testing.T) {
+	defer SetChainIndex(false)
+
+	tmpl := Define("NOT_FOUND", "%s not found")
+	pathErr := &os.PathError{Op: "open", Path: "/etc/app.conf", Err: os.ErrNotExist}
+	errs := []error{
+		Wrap(WithCode(Wrap(io.EOF, "read"), "READ"), "load"),
+		WithField(pathErr, "attempt", 1),
+		Join(New("a"), Wrap(context.Canceled, "b")),
+		Wrap(tmpl.New("user"), "lookup"),
+		Wrap(NewTimeout("query", 2*time.Second, time.Second), "handle"),
+		Wrap(NewValidation("invalid").AddField("name", "required").Err(), "create"),
+		WithMessage(asCode{WithCode(New("inner"), "INNER")}, "outer"),
+		WithStack(WithStack(WithStack(io.ErrUnexpectedEOF))),
+	}
+	targets := []error{
+		io.EOF, io.ErrUnexpectedEOF, os.ErrNotExist, context.Canceled,
+		context.DeadlineExceeded, tmpl, ErrValidation, stderrors.New("other"),
+	}
+
+	check := func(err error) {
+		t.Helper()
+		for _, target := range targets {
+			SetChainIndex(false)
+			want := Is(err, target)
+			SetChainIndex(true)
+			if got := Is(err, target); got != want {
+				t.Errorf("Is(%v, %v): got %t with index, %t without", err, target, got, want)
+			}
+			if got := Is(err, target); got != want {
+				t.Errorf("Is(%v, %v): got %t with built index, %t without", err, target, got, want)
+			}
+		}
+
+		SetChainIndex(false)
+		var wantCode *withCode
+		wantOK := As(err, &wantCode)
+		var wantPath *os.PathError
+		wantPathOK := As(err, &wantPath)
+		SetChainIndex(true)
+		for i := 0; i < 2; i++ {
+			var code *withCode
+			if ok := As(err, &code); ok != wantOK || ok && code.Code() != wantCode.Code() {
+				t.Errorf("As(%v, *withCode): got %v, %t with index, want %v, %t", err, code, ok, wantCode, wantOK)
+			}
+			var path *os.PathError
+			if ok := As(err, &path); ok != wantPathOK || path != wantPath {
+				t.Errorf("As(%v, *os.PathError): got %v, %t with index, want %v, %t", err, path, ok, wantPath, wantPathOK)
+			}
+		}
+	}
+	for _, err := range errs {
+		check(err)
+	}
+	Is(errs[0], io.EOF)
+	if _, ok := chainIndexes.Load(errs[0]); !ok {
+		t.Error("SetChainIndex(true): chain not indexed")
+	}
+	SetChainIndex(false)
+	if _, ok := chainIndexes.Load(errs[0]); ok {
+		t.Error("SetChainIndex(false): index not emptied")
+	}
+	if Is(nil, io.EOF) || !Is(nil, nil) {
+		t.Error("Is(nil): wrong result")
+	}
+}
+
+// sliceErr is a comparable error type holding a value which cannot be
+// hashed.
+type sliceErr struct{ v interface{} }
+
+func (e sliceErr) Error() string { return "slice error" }
+
+func TestChainIndexUnhashable(t *testing.T) {
+	defer SetChainIndex(false)
+	SetChainIndex(true)
+
+	unhashable := sliceErr{[]int{1}}
+	hashable := sliceErr{1}
+	tests := []struct {
+		err    error
+		target error
+		want   bool
+	}{
+		{Wrap(unhashable, "x"), io.EOF, false},
+		{Wrap(unhashable, "x"), hashable, false},
+		{Wrap(hashable, "x"), hashable, true},
+		{Wrap(Join(unhashable, io.EOF), "x"), io.EOF, true},
+		{Wrap(io.EOF, "x"), unhashable, false},
+	}
+	for i, tt := range tests {
+		if got := Is(tt.err, tt.target); got != tt.want {
+			t.Errorf("test %d: Is(%v, %v): got %t, want %t", i+1, tt.err, tt.target, got, tt.want)
+		}
+	}
+}
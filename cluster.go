@@ -0,0 +1,70 @@
+package errors
+
+import "sort"
+
+// maxExemplars is the number of errors kept as exemplars of a cluster.
+const maxExemplars = 3
+
+// ErrorCluster is a group of errors representing the same failure, as
+// returned by Cluster.
+type ErrorCluster struct {
+	// Fingerprint is the fingerprint shared by the errors, as returned by
+	// Fingerprint.
+	Fingerprint string
+
+	// Message is the message of the root cause of the errors, with volatile
+	// data such as numbers, identifiers and quoted strings replaced by "?".
+	Message string
+
+	// Code is the error code of the errors, if any.
+	Code string
+
+	// Functions are the names of the top application functions of the
+	// stack trace recorded closest to the root cause of the errors.
+	Functions []string
+
+	// Count is the number of errors in the cluster.
+	Count int
+
+	// Exemplars are the first errors of the cluster, at most three.
+	Exemplars []error
+}
+
+// Cluster groups errs by the failure they represent, as identified by
+// Fingerprint, to summarize large numbers of errors, such as the failures of
+// a batch job or a test run:
+//
+//	for _, c := range errors.Cluster(failures) {
+//		fmt.Printf("%6d  %s\n", c.Count, c.Message)
+//	}
+//
+// The clusters are returned by decreasing count, clusters of equal count in
+// the order their first errors appear in errs. Nil errors are ignored.
+func Cluster(errs []error) []ErrorCluster {
+	var clusters []ErrorCluster
+	index := make(map[string]int)
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		fp := Fingerprint(err)
+		i, ok := index[fp]
+		if !ok {
+			i = len(clusters)
+			index[fp] = i
+			clusters = append(clusters, ErrorCluster{
+				Fingerprint: fp,
+				Message:     normalizedMessage(err),
+				Code:        Code(err),
+				Functions:   topFunctions(err),
+			})
+		}
+		c := &clusters[i]
+		c.Count++
+		if len(c.Exemplars) < maxExemplars {
+			c.Exemplars = append(c.Exemplars, err)
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].Count > clusters[j].Count })
+	return clusters
+}
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCluster(t *testing.T) {
+	f := NewFrame("example.com/app.load", "/src/app/load.go", 10)
+	g := NewFrame("example.com/app.save", "/src/app/save.go", 20)
+	at := func(fr Frame, msg string) error {
+		return &fundamental{msg: msg, stack: &stack{pcs: []uintptr{uintptr(fr)}}}
+	}
+
+	var errs []error
+	for i := 0; i < 5; i++ {
+		errs = append(errs, Wrap(at(f, fmt.Sprintf("open /tmp/%d.txt: no such file", i)), "load"))
+	}
+	errs = append(errs, nil, at(g, "open /tmp/1.txt: no such file"))
+	errs = append(errs, WithCode(at(f, "timeout after 30s"), "TIMEOUT"), WithCode(at(f, "timeout after 5s"), "TIMEOUT"))
+
+	clusters := Cluster(errs)
+	tests := []struct {
+		message   string
+		code      string
+		functions []string
+		count     int
+		exemplars []error
+	}{
+		{"open /tmp/?.txt: no such file", "", []string{"example.com/app.load"}, 5, errs[:3]},
+		{"timeout after ?s", "TIMEOUT", []string{"example.com/app.load"}, 2, errs[7:]},
+		{"open /tmp/?.txt: no such file", "", []string{"example.com/app.save"}, 1, errs[6:7]},
+	}
+	if len(clusters) != len(tests) {
+		t.Fatalf("Cluster(): got %d clusters, want %d", len(clusters), len(tests))
+	}
+	for i, tt := range tests {
+		c := clusters[i]
+		if c.Message != tt.message || c.Code != tt.code || !reflect.DeepEqual(c.Functions, tt.functions) || c.Count != tt.count {
+			t.Errorf("test %d: got %q %q %q ×%d, want %q %q %q ×%d", i+1,
+				c.Message, c.Code, c.Functions, c.Count, tt.message, tt.code, tt.functions, tt.count)
+		}
+		if c.Fingerprint != Fingerprint(tt.exemplars[0]) {
+			t.Errorf("test %d: got fingerprint %s, want %s", i+1, c.Fingerprint, Fingerprint(tt.exemplars[0]))
+		}
+		if len(c.Exemplars) != len(tt.exemplars) {
+			t.Errorf("test %d: got %d exemplars, want %d", i+1, len(c.Exemplars), len(tt.exemplars))
+			continue
+		}
+		for j := range tt.exemplars {
+			if c.Exemplars[j] != tt.exemplars[j] {
+				t.Errorf("test %d: exemplar %d: got %v, want %v", i+1, j, c.Exemplars[j], tt.exemplars[j])
+			}
+		}
+	}
+
+	if got := Cluster(nil); got != nil {
+		t.Errorf("Cluster(nil): got %v, want nil", got)
+	}
+}
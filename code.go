@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// WithCode annotates err with a machine readable error code, such as
+// "USER_NOT_FOUND", for consumption by APIs, metrics and log aggregators.
+// If err is nil, WithCode returns nil.
+func WithCode(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withCode{
+		cause: err,
+		code:  code,
+	}))
+}
+
+// Code returns the outermost error code attached to err's chain, or the
+// empty string if there is none. An error value carries a code if it
+// implements the following interface and returns a non-empty code:
+//
+//	type coder interface {
+//	       Code() string
+//	}
+func Code(err error) string {
+	type coder interface {
+		Code() string
+	}
+
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if c, ok := err.(coder); ok && c.Code() != "" {
+			return c.Code()
+		}
+		err = Unwrap(err)
+	}
+	return ""
+}
+
+type withCode struct {
+	cause error
+	code  string
+}
+
+func (w *withCode) Error() string { return redact(w.cause.Error()) }
+func (w *withCode) Cause() error  { return w.cause }
+func (w *withCode) Code() string  { return w.code }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withCode) Unwrap() error { return w.cause }
+
+func (w *withCode) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, "code="+w.code)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
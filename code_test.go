@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestWithCodeNil(t *testing.T) {
+	if got := WithCode(nil, "CODE"); got != nil {
+		t.Errorf("WithCode(nil, \"CODE\"): got %#v, expected nil", got)
+	}
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{WithCode(io.EOF, "EOF"), "EOF"},
+		{Wrap(WithCode(io.EOF, "EOF"), "read"), "EOF"},
+		{WithCode(WithCode(io.EOF, "INNER"), "OUTER"), "OUTER"},
+		{fmt.Errorf("read: %w", WithCode(io.EOF, "EOF")), "EOF"},
+	}
+
+	for i, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("test %d: Code(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestFormatWithCode(t *testing.T) {
+	err := WithCode(io.EOF, "EOF")
+	for format, want := range map[string]string{
+		"%s":  "EOF",
+		"%v":  "EOF",
+		"%+v": "EOF\ncode=EOF",
+		"%q":  `"EOF"`,
+	} {
+		if got := fmt.Sprintf(format, err); got != want {
+			t.Errorf("fmt.Sprintf(%q, err): got %q, want %q", format, got, want)
+		}
+	}
+}
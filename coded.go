@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Coded annotates err with code, a value of an error code enum type of the
+// caller's own, such as
+//
+//	type PaymentCode string
+//
+//	const Declined PaymentCode = "PAY_DECLINED"
+//
+//	err = errors.Coded(err, Declined)
+//
+// so that attaching and retrieving codes, with CodeAs, is checked by the
+// compiler. The code is also returned by Code as a string, so that it reaches
+// APIs, metrics and logs as the codes attached with WithCode do.
+// If err is nil, Coded returns nil.
+func Coded[T ~string | ~int](err error, code T) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &coded[T]{
+		cause: err,
+		code:  code,
+	}))
+}
+
+// CodeAs returns the outermost code of type T attached to err's chain with
+// Coded, and whether there is one. Codes of other types are ignored:
+//
+//	if code, ok := errors.CodeAs[PaymentCode](err); ok && code == Declined {
+//		...
+//	}
+func CodeAs[T ~string | ~int](err error) (T, bool) {
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if w, ok := err.(*coded[T]); ok {
+			return w.code, true
+		}
+		err = Unwrap(err)
+	}
+	var zero T
+	return zero, false
+}
+
+// typedCoder is implemented by every instantiation of coded.
+type typedCoder interface {
+	error
+	Cause() error
+	typedCode() interface{}
+	withCause(cause error) error
+}
+
+type coded[T ~string | ~int] struct {
+	cause error
+	code  T
+}
+
+func (w *coded[T]) Error() string          { return redact(w.cause.Error()) }
+func (w *coded[T]) Cause() error           { return w.cause }
+func (w *coded[T]) Code() string           { return fmt.Sprint(w.code) }
+func (w *coded[T]) typedCode() interface{} { return w.code }
+
+// withCause returns a copy of w wrapping cause.
+func (w *coded[T]) withCause(cause error) error { return &coded[T]{cause: cause, code: w.code} }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *coded[T]) Unwrap() error { return w.cause }
+
+func (w *coded[T]) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, "code="+w.Code())
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+type paymentCode string
+
+type statusCode int
+
+const declined paymentCode = "PAY_DECLINED"
+
+func TestCoded(t *testing.T) {
+	if got := Coded(nil, declined); got != nil {
+		t.Errorf("Coded(nil): got %v, want nil", got)
+	}
+
+	err := Wrap(Coded(Coded(io.EOF, statusCode(7)), declined), "pay")
+	if code, ok := CodeAs[paymentCode](err); !ok || code != declined {
+		t.Errorf("CodeAs[paymentCode](): got %q, %v, want %q, true", code, ok, declined)
+	}
+	if code, ok := CodeAs[statusCode](err); !ok || code != 7 {
+		t.Errorf("CodeAs[statusCode](): got %d, %v, want 7, true", code, ok)
+	}
+	if code, ok := CodeAs[string](err); ok || code != "" {
+		t.Errorf("CodeAs[string](): got %q, %v, want \"\", false", code, ok)
+	}
+	if code, ok := CodeAs[paymentCode](WithCode(io.EOF, "PAY_DECLINED")); ok {
+		t.Errorf("CodeAs[paymentCode](WithCode()): got %q, want none", code)
+	}
+	if got := Code(err); got != "PAY_DECLINED" {
+		t.Errorf("Code(): got %q, want %q", got, "PAY_DECLINED")
+	}
+	if got := Code(Coded(io.EOF, statusCode(42))); got != "42" {
+		t.Errorf("Code(): got %q, want %q", got, "42")
+	}
+	if got, want := fmt.Sprintf("%+v", Coded(io.EOF, declined)), "EOF\ncode=PAY_DECLINED"; got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+
+	mapped := Map(err, func(err error) error { return nil })
+	if code, ok := CodeAs[paymentCode](mapped); !ok || code != declined {
+		t.Errorf("CodeAs[paymentCode](Map()): got %q, %v, want %q, true", code, ok, declined)
+	}
+}
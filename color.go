@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences used by the colorized renderer.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiMessage = "\x1b[1;31m" // bold red
+	ansiFunc    = "\x1b[36m"   // cyan
+	ansiFile    = "\x1b[2m"    // faint
+	ansiLine    = "\x1b[33m"   // yellow
+)
+
+// Colorize returns err formatted as by %+v, with messages, function names,
+// file paths and line numbers in distinct colors using ANSI escape
+// sequences. Colorize returns "" if err is nil.
+func Colorize(err error) string {
+	if err == nil {
+		return ""
+	}
+	return colorize(fmt.Sprintf("%+v", err))
+}
+
+// FprintColor writes err formatted as by %+v to w, followed by a newline.
+// The output is colorized as by Colorize if w is a terminal, unless the
+// NO_COLOR environment variable is set or TERM is "dumb"; otherwise it is
+// written as is.
+func FprintColor(w io.Writer, err error) (int, error) {
+	s := fmt.Sprintf("%+v", err)
+	if isTerminal(w) {
+		s = colorize(s)
+	}
+	return io.WriteString(w, s+"\n")
+}
+
+// isTerminal reports whether w is a terminal that accepts colors.
+func isTerminal(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize colors the output of %+v. In that output, a line starting with a
+// tab is the source location of the function named on the line before it,
+// or a source snippet line; any other line is a message.
+func colorize(s string) string {
+	lines := strings.Split(s, "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "\t"):
+			b.WriteString(colorizeLocation(line))
+		case i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") && !strings.Contains(lines[i+1], " |"):
+			b.WriteString(ansiFunc + line + ansiReset)
+		default:
+			b.WriteString(ansiMessage + line + ansiReset)
+		}
+	}
+	return b.String()
+}
+
+// colorizeLocation colors a "\tfile:line" line of the output of %+v.
+func colorizeLocation(line string) string {
+	if strings.Contains(line, " |") {
+		return line // source snippet
+	}
+	i := strings.LastIndexByte(line, ':')
+	if i < 0 {
+		return ansiFile + line + ansiReset
+	}
+	return "\t" + ansiFile + line[1:i] + ansiReset + ":" + ansiLine + line[i+1:] + ansiReset
+}
@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestColorize(t *testing.T) {
+	if got := Colorize(nil); got != "" {
+		t.Errorf("Colorize(nil): got %q, want %q", got, "")
+	}
+
+	err := &withStack{
+		WithMessage(io.EOF, "read"),
+		&stack{pcs: []uintptr{uintptr(NewFrame("app.main", "/src/app/main.go", 7))}},
+	}
+	want := "\x1b[1;31mEOF\x1b[0m\n" +
+		"\x1b[1;31mread\x1b[0m\n" +
+		"\x1b[36mapp.main\x1b[0m\n" +
+		"\t\x1b[2m/src/app/main.go\x1b[0m:\x1b[33m7\x1b[0m"
+	if got := Colorize(err); got != want {
+		t.Errorf("Colorize(): got %q, want %q", got, want)
+	}
+}
+
+func TestFprintColor(t *testing.T) {
+	var b bytes.Buffer
+	err := WithMessage(io.EOF, "read")
+	if _, werr := FprintColor(&b, err); werr != nil {
+		t.Fatal(werr)
+	}
+	if got, want := b.String(), "EOF\nread\n"; got != want {
+		t.Errorf("FprintColor() to a buffer: got %q, want %q", got, want)
+	}
+}
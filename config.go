@@ -0,0 +1,239 @@
+package errors
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultStackDepth is the default maximum number of frames recorded in a
+// stack trace.
+const defaultStackDepth = 32
+
+// stackDepth is the maximum number of frames recorded in a stack trace.
+var stackDepth int32 = defaultStackDepth
+
+// SetStackDepth sets the maximum number of frames recorded in the stack trace
+// of errors created from then on. A depth of zero or less restores the
+// default of 32.
+func SetStackDepth(depth int) {
+	if depth <= 0 {
+		depth = defaultStackDepth
+	}
+	atomic.StoreInt32(&stackDepth, int32(depth))
+}
+
+// PathStyle selects how source file paths are printed by the %+s and %+v
+// verbs of Frame.
+type PathStyle int32
+
+const (
+	// PathFull prints the full path of the source file, as recorded at
+	// compile time. This is the default.
+	PathFull PathStyle = iota
+
+	// PathBase prints only the base name of the source file.
+	PathBase
+)
+
+var pathStyle int32 // of PathStyle
+
+// SetPathStyle sets the style in which source file paths are printed.
+func SetPathStyle(style PathStyle) { atomic.StoreInt32(&pathStyle, int32(style)) }
+
+func loadPathStyle() PathStyle { return PathStyle(atomic.LoadInt32(&pathStyle)) }
+
+var frameFormatter atomic.Value // of func(io.Writer, Frame, bool)
+
+// SetFrameFormatter installs fn to print frames with the %v verb, in place of
+// the default layout, wherever frames are printed, including in the stack
+// traces of errors printed with %+v. verbose reports whether the + flag was
+// given, asking for the function name and full location of the frame:
+//
+//	errors.SetFrameFormatter(func(w io.Writer, f errors.Frame, verbose bool) {
+//		if verbose {
+//			fmt.Fprintf(w, "%s\n\t%s", f.Func(), strings.TrimPrefix(f.File(), repoRoot))
+//			return
+//		}
+//		io.WriteString(w, path.Base(f.File()))
+//	})
+//
+// Passing nil restores the default layout.
+func SetFrameFormatter(fn func(w io.Writer, f Frame, verbose bool)) {
+	frameFormatter.Store(fn)
+}
+
+func loadFrameFormatter() func(io.Writer, Frame, bool) {
+	fn, _ := frameFormatter.Load().(func(io.Writer, Frame, bool))
+	return fn
+}
+
+// frameLimit holds the number of innermost frames printed by %+v in its
+// upper 32 bits, and the number of outermost frames in its lower 32 bits.
+var frameLimit int64
+
+// SetFrameLimit limits the number of frames of a stack trace printed by the
+// %+v verb to its head innermost frames and its tail outermost frames; the
+// frames in between are replaced by a line such as "... 19 frames omitted".
+// The stack traces recorded, as returned by StackTrace, are left whole.
+// Calling SetFrameLimit(0, 0), the default, prints every frame.
+func SetFrameLimit(head, tail int) {
+	if head < 0 {
+		head = 0
+	}
+	if tail < 0 {
+		tail = 0
+	}
+	atomic.StoreInt64(&frameLimit, int64(head)<<32|int64(uint32(tail)))
+}
+
+func loadFrameLimit() (head, tail int) {
+	v := atomic.LoadInt64(&frameLimit)
+	return int(v >> 32), int(uint32(v))
+}
+
+// libraryFramesHidden is non-zero while library frames are hidden.
+var libraryFramesHidden int32
+
+// SetHideLibraryFrames sets whether the %+v verb leaves out the frames of the
+// standard library and of third-party packages, either vendored or from the
+// module cache, keeping stack traces focused on application code. Unlike
+// SetFrameFilter, it affects only printing: the stack traces recorded are
+// left whole, and a StackTrace printed with the %+#v verb shows every frame.
+func SetHideLibraryFrames(hide bool) {
+	var v int32
+	if hide {
+		v = 1
+	}
+	atomic.StoreInt32(&libraryFramesHidden, v)
+}
+
+func hideLibraryFrames() bool { return atomic.LoadInt32(&libraryFramesHidden) != 0 }
+
+// isLibraryFrame reports whether f belongs to the standard library or to a
+// third-party package.
+func isLibraryFrame(f Frame) bool {
+	if isStdlib(f.name()) {
+		return true
+	}
+	file := strings.Replace(f.file(), `\`, "/", -1)
+	return strings.Contains(file, "/vendor/") || strings.Contains(file, "/pkg/mod/")
+}
+
+var frameFilter atomic.Value // of []string
+
+// SetFrameFilter drops, at the time a stack trace is captured, every frame
+// whose fully qualified function name starts with one of prefixes, such as
+// "runtime." or "net/http.". Calling SetFrameFilter with no prefixes
+// removes the filter.
+func SetFrameFilter(prefixes ...string) {
+	frameFilter.Store(append([]string(nil), prefixes...))
+}
+
+var skipPackages atomic.Value // of []string
+
+// SetSkipPackages drops, at the time a stack trace is captured, the leading
+// frames whose function belongs to one of the packages named by their
+// import paths, such as "github.com/acme/platform/errwrap". Libraries built
+// on this package thus keep their own frames out of the stack traces of the
+// errors they create, which start at the user code calling them instead.
+// Only the frames at the top of a stack trace are dropped: those of the
+// packages found further down are kept. Calling SetSkipPackages with no
+// packages keeps every frame.
+func SetSkipPackages(pkgs ...string) {
+	skipPackages.Store(append([]string(nil), pkgs...))
+}
+
+// filterFrames removes the leading frames of the packages set by
+// SetSkipPackages and the frames matched by the frame filter from pcs,
+// in place.
+func filterFrames(pcs []uintptr) []uintptr {
+	if pkgs, _ := skipPackages.Load().([]string); len(pkgs) > 0 {
+		for len(pcs) > 0 && inPackages(Frame(pcs[0]).Package(), pkgs) {
+			pcs = pcs[1:]
+		}
+	}
+	prefixes, _ := frameFilter.Load().([]string)
+	if len(prefixes) == 0 {
+		return pcs
+	}
+	kept := pcs[:0]
+	for _, pc := range pcs {
+		if !hasAnyPrefix(Frame(pc).name(), prefixes) {
+			kept = append(kept, pc)
+		}
+	}
+	return kept
+}
+
+func inPackages(pkg string, pkgs []string) bool {
+	for _, p := range pkgs {
+		if pkg == p {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() { configureFromEnv(os.Getenv) }
+
+// configureFromEnv sets the package defaults from the following environment
+// variables, as returned by getenv:
+//
+//	ERRORS_STACK_DEPTH    maximum number of frames in a stack trace
+//	ERRORS_STACK_CAPTURE  "off" (or any false value) disables stack capture
+//	ERRORS_PATH_STYLE     "full" or "base"
+//	ERRORS_FRAME_FILTER   comma separated function name prefixes to drop
+//	ERRORS_SKIP_PACKAGES  comma separated import paths of leading frames to drop
+//	ERRORS_SOURCE_CONTEXT source lines printed around application frames
+//
+// Unset variables and unparsable values leave the defaults unchanged.
+func configureFromEnv(getenv func(string) string) {
+	if n, err := strconv.Atoi(getenv("ERRORS_STACK_DEPTH")); err == nil {
+		SetStackDepth(n)
+	}
+	switch strings.ToLower(getenv("ERRORS_STACK_CAPTURE")) {
+	case "off", "false", "0", "no":
+		DisableStackCapture()
+	case "on", "true", "1", "yes":
+		EnableStackCapture()
+	}
+	switch strings.ToLower(getenv("ERRORS_PATH_STYLE")) {
+	case "full":
+		SetPathStyle(PathFull)
+	case "base":
+		SetPathStyle(PathBase)
+	}
+	if v := getenv("ERRORS_FRAME_FILTER"); v != "" {
+		SetFrameFilter(splitList(v)...)
+	}
+	if v := getenv("ERRORS_SKIP_PACKAGES"); v != "" {
+		SetSkipPackages(splitList(v)...)
+	}
+	if n, err := strconv.Atoi(getenv("ERRORS_SOURCE_CONTEXT")); err == nil {
+		SetSourceContext(n)
+	}
+}
+
+// splitList returns the non-empty elements of the comma separated list v,
+// with surrounding spaces trimmed.
+func splitList(v string) []string {
+	var elems []string
+	for _, e := range strings.Split(v, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			elems = append(elems, e)
+		}
+	}
+	return elems
+}
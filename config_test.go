@@ -0,0 +1,238 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSetStackDepth(t *testing.T) {
+	defer SetStackDepth(0)
+
+	tests := []struct {
+		depth int
+		want  int
+	}{
+		{1, 1},
+		{2, 2},
+		{0, defaultStackDepth},
+	}
+	for _, tt := range tests {
+		SetStackDepth(tt.depth)
+		if got := int(stackDepth); got != tt.want {
+			t.Errorf("SetStackDepth(%d): depth %d, want %d", tt.depth, got, tt.want)
+		}
+		if got := len(New("error").(*fundamental).StackTrace()); got > tt.want {
+			t.Errorf("SetStackDepth(%d): got %d frames", tt.depth, got)
+		}
+	}
+
+	SetStackDepth(100)
+	if got := len(deepStack(50)); got < 50 {
+		t.Errorf("SetStackDepth(100): got %d frames, want at least 50", got)
+	}
+}
+
+func deepStack(n int) StackTrace {
+	if n == 0 {
+		return New("error").(*fundamental).StackTrace()
+	}
+	return deepStack(n - 1)
+}
+
+func TestSetPathStyle(t *testing.T) {
+	defer SetPathStyle(PathFull)
+
+	err := New("error")
+	SetPathStyle(PathBase)
+	want := "error\ngithub.com/peakle/errors.TestSetPathStyle\n\tconfig_test.go:"
+	if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, want) {
+		t.Errorf("PathBase: got %q, want prefix %q", got, want)
+	}
+	SetPathStyle(PathFull)
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile("\n\t/.+/config_test.go:").MatchString(got) {
+		t.Errorf("PathFull: got %q, want full path", got)
+	}
+}
+
+func TestSetFrameFilter(t *testing.T) {
+	defer SetFrameFilter()
+
+	SetFrameFilter("testing.", "runtime.")
+	for _, f := range New("error").(*fundamental).StackTrace() {
+		if name := f.name(); strings.HasPrefix(name, "testing.") || strings.HasPrefix(name, "runtime.") {
+			t.Errorf("frame %s not filtered", name)
+		}
+	}
+}
+
+func TestSetSkipPackages(t *testing.T) {
+	defer SetSkipPackages()
+
+	tests := []struct {
+		pkgs []string
+		want string
+	}{
+		{nil, "github.com/peakle/errors"},
+		{[]string{"testing"}, "github.com/peakle/errors"},
+		{[]string{"github.com/peakle/errors/sub", "github.com/peakle/errors"}, "testing"},
+	}
+	for i, tt := range tests {
+		SetSkipPackages(tt.pkgs...)
+		st := New("error").(*fundamental).StackTrace()
+		if len(st) == 0 || st[0].Package() != tt.want {
+			t.Errorf("test %d: SetSkipPackages(%q): got stack trace %v, want one starting in %s", i+1, tt.pkgs, st, tt.want)
+		}
+		var sawTesting bool
+		for _, f := range st {
+			sawTesting = sawTesting || f.Package() == "testing"
+		}
+		if !sawTesting {
+			t.Errorf("test %d: SetSkipPackages(%q): frames of package testing dropped", i+1, tt.pkgs)
+		}
+	}
+}
+
+func TestConfigureFromEnv(t *testing.T) {
+	defer SetStackDepth(0)
+	defer EnableStackCapture()
+	defer SetPathStyle(PathFull)
+	defer SetFrameFilter()
+	defer SetSkipPackages()
+	defer SetSourceContext(0)
+
+	env := map[string]string{
+		"ERRORS_STACK_DEPTH":    "7",
+		"ERRORS_STACK_CAPTURE":  "off",
+		"ERRORS_PATH_STYLE":     "base",
+		"ERRORS_FRAME_FILTER":   "runtime., testing.,",
+		"ERRORS_SKIP_PACKAGES":  "github.com/acme/errwrap",
+		"ERRORS_SOURCE_CONTEXT": "3",
+	}
+	configureFromEnv(func(k string) string { return env[k] })
+
+	if stackDepth != 7 {
+		t.Errorf("ERRORS_STACK_DEPTH: got %d, want 7", stackDepth)
+	}
+	if StackCaptureEnabled() {
+		t.Error("ERRORS_STACK_CAPTURE: capture still enabled")
+	}
+	if got := loadPathStyle(); got != PathBase {
+		t.Errorf("ERRORS_PATH_STYLE: got %v, want PathBase", got)
+	}
+	if got := frameFilter.Load().([]string); len(got) != 2 || got[0] != "runtime." || got[1] != "testing." {
+		t.Errorf("ERRORS_FRAME_FILTER: got %q", got)
+	}
+	if got := skipPackages.Load().([]string); len(got) != 1 || got[0] != "github.com/acme/errwrap" {
+		t.Errorf("ERRORS_SKIP_PACKAGES: got %q", got)
+	}
+	if sourceContext != 3 {
+		t.Errorf("ERRORS_SOURCE_CONTEXT: got %d, want 3", sourceContext)
+	}
+
+	configureFromEnv(func(k string) string { return map[string]string{"ERRORS_STACK_DEPTH": "many"}[k] })
+	if stackDepth != 7 {
+		t.Errorf("invalid ERRORS_STACK_DEPTH changed depth to %d", stackDepth)
+	}
+}
+
+func TestSetFrameFormatter(t *testing.T) {
+	defer SetFrameFormatter(nil)
+
+	SetFrameFormatter(func(w io.Writer, f Frame, verbose bool) {
+		if verbose {
+			fmt.Fprintf(w, "%s @ %s", f.Func(), f.File())
+			return
+		}
+		io.WriteString(w, f.Func())
+	})
+	f := NewFrame("app.main", "/src/app/main.go", 7)
+	err := &withStack{stderrors.New("error"), &stack{pcs: []uintptr{uintptr(f)}}}
+	tests := []struct {
+		arg    interface{}
+		format string
+		want   string
+	}{
+		{f, "%v", "app.main"},
+		{f, "%+v", "app.main @ /src/app/main.go"},
+		{f, "%s", "main.go"},
+		{StackTrace{f, f}, "%v", "[app.main app.main]"},
+		{StackTrace{f}, "%+v", "\napp.main @ /src/app/main.go"},
+		{err, "%+v", "error\napp.main @ /src/app/main.go"},
+	}
+	for i, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.arg); got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%q, arg): got %q, want %q", i+1, tt.format, got, tt.want)
+		}
+	}
+
+	SetFrameFormatter(nil)
+	if got, want := fmt.Sprintf("%v", f), "main.go:7"; got != want {
+		t.Errorf("default formatter: got %q, want %q", got, want)
+	}
+}
+
+func TestSetFrameLimit(t *testing.T) {
+	defer SetFrameLimit(0, 0)
+
+	var st StackTrace
+	for i := 1; i <= 6; i++ {
+		st = append(st, NewFrame(fmt.Sprintf("app.f%d", i), "/src/app/app.go", i))
+	}
+	tests := []struct {
+		head, tail int
+		want       string
+	}{
+		{0, 0, "\napp.f1\n\t/src/app/app.go:1\napp.f2\n\t/src/app/app.go:2\napp.f3\n\t/src/app/app.go:3" +
+			"\napp.f4\n\t/src/app/app.go:4\napp.f5\n\t/src/app/app.go:5\napp.f6\n\t/src/app/app.go:6"},
+		{2, 1, "\napp.f1\n\t/src/app/app.go:1\napp.f2\n\t/src/app/app.go:2\n... 3 frames omitted\napp.f6\n\t/src/app/app.go:6"},
+		{1, 0, "\napp.f1\n\t/src/app/app.go:1\n... 5 frames omitted"},
+		{0, 2, "\n... 4 frames omitted\napp.f5\n\t/src/app/app.go:5\napp.f6\n\t/src/app/app.go:6"},
+		{3, 3, "\napp.f1\n\t/src/app/app.go:1\napp.f2\n\t/src/app/app.go:2\napp.f3\n\t/src/app/app.go:3" +
+			"\napp.f4\n\t/src/app/app.go:4\napp.f5\n\t/src/app/app.go:5\napp.f6\n\t/src/app/app.go:6"},
+	}
+	for i, tt := range tests {
+		SetFrameLimit(tt.head, tt.tail)
+		if got := fmt.Sprintf("%+v", st); got != tt.want {
+			t.Errorf("test %d: SetFrameLimit(%d, %d): got %q, want %q", i+1, tt.head, tt.tail, got, tt.want)
+		}
+	}
+
+	SetFrameLimit(1, 1)
+	err := New("error")
+	if got := strings.Count(fmt.Sprintf("%+v", err), "\n\t"); got > 2 {
+		t.Errorf("fmt.Sprintf(%%+v, err): got %d frames, want at most 2", got)
+	}
+	if got := len(err.(*fundamental).StackTrace()); got < 3 {
+		t.Errorf("StackTrace(): got %d frames, want the whole stack", got)
+	}
+}
+
+func TestSetHideLibraryFrames(t *testing.T) {
+	defer SetHideLibraryFrames(false)
+
+	st := StackTrace{
+		NewFrame("net/http.HandlerFunc.ServeHTTP", "/usr/local/go/src/net/http/server.go", 2136),
+		NewFrame("example.com/app.handle", "/src/app/handle.go", 12),
+		NewFrame("github.com/go-chi/chi.(*Mux).ServeHTTP", "/home/u/go/pkg/mod/github.com/go-chi/chi@v1.5.4/mux.go", 87),
+		NewFrame("github.com/lib/pq.(*conn).query", "/src/app/vendor/github.com/lib/pq/conn.go", 890),
+		NewFrame("main.main", "/src/app/main.go", 7),
+		NewFrame("runtime.main", "/usr/local/go/src/runtime/proc.go", 250),
+	}
+	all := fmt.Sprintf("%+v", st)
+
+	SetHideLibraryFrames(true)
+	want := "\nexample.com/app.handle\n\t/src/app/handle.go:12\nmain.main\n\t/src/app/main.go:7"
+	if got := fmt.Sprintf("%+v", st); got != want {
+		t.Errorf("fmt.Sprintf(%%+v, st): got %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%+#v", st); got != all {
+		t.Errorf("fmt.Sprintf(%%+#v, st): got %q, want %q", got, all)
+	}
+	if got := fmt.Sprintf("%+v", &withStack{stderrors.New("error"), &stack{pcs: []uintptr{uintptr(st[0]), uintptr(st[1])}}}); got != "error\nexample.com/app.handle\n\t/src/app/handle.go:12" {
+		t.Errorf("fmt.Sprintf(%%+v, err): got %q", got)
+	}
+}
@@ -0,0 +1,163 @@
+package errors
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type contextFieldsKey struct{}
+
+// WithContextFields returns a copy of ctx carrying fields, merged over any
+// fields already carried by ctx. Errors created by NewCtx, ErrorfCtx,
+// WrapCtx, WrapfCtx and WithStackCtx under the returned context are
+// annotated with the fields, making it the place to record ambient details
+// such as request, tenant or trace IDs.
+func WithContextFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	parent := ContextFields(ctx)
+	merged := make(map[string]interface{}, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// ContextFields returns a copy of the fields carried by ctx, or nil if
+// there are none.
+func ContextFields(ctx context.Context) map[string]interface{} {
+	fields, _ := ctx.Value(contextFieldsKey{}).(map[string]interface{})
+	if len(fields) == 0 {
+		return nil
+	}
+	c := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		c[k] = v
+	}
+	return c
+}
+
+var (
+	extractorsMu sync.Mutex   // serialises writers of extractors
+	extractors   atomic.Value // of []func(context.Context) map[string]interface{}
+)
+
+// RegisterContextExtractor registers fn to derive additional fields from the
+// context passed to NewCtx, ErrorfCtx, WrapCtx, WrapfCtx and WithStackCtx,
+// for instance the trace and span IDs of the active span. Fields set with
+// WithContextFields take precedence over extracted ones.
+//
+// RegisterContextExtractor is safe for concurrent use, but is intended to be
+// called during program initialisation.
+func RegisterContextExtractor(fn func(context.Context) map[string]interface{}) {
+	if fn == nil {
+		return
+	}
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	old, _ := extractors.Load().([]func(context.Context) map[string]interface{})
+	fns := make([]func(context.Context) map[string]interface{}, len(old), len(old)+1)
+	copy(fns, old)
+	extractors.Store(append(fns, fn))
+}
+
+// resetContextExtractors removes all registered context extractors.
+func resetContextExtractors() {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors.Store([]func(context.Context) map[string]interface{}{})
+}
+
+// withContextFields annotates err with the fields carried by, or extracted
+// from, ctx, if any.
+func withContextFields(ctx context.Context, err error) error {
+	fields, _ := ctx.Value(contextFieldsKey{}).(map[string]interface{})
+	fns, _ := extractors.Load().([]func(context.Context) map[string]interface{})
+	if len(fns) > 0 {
+		merged := make(map[string]interface{})
+		for _, fn := range fns {
+			for k, v := range fn(ctx) {
+				merged[k] = v
+			}
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+		fields = merged
+	}
+	if len(fields) == 0 {
+		return err
+	}
+	return newWithFields(err, fields)
+}
+
+// NewCtx is like New, but also annotates the error with the fields carried
+// by ctx.
+func NewCtx(ctx context.Context, message string) error {
+	f := newFundamental(message)
+	recordLabels(ctx, f.stack)
+	return created(withContextFields(ctx, f))
+}
+
+// ErrorfCtx is like Errorf, but also annotates the error with the fields
+// carried by ctx.
+func ErrorfCtx(ctx context.Context, format string, args ...interface{}) error {
+	f := newFundamental(sprintf(format, args...))
+	recordLabels(ctx, f.stack)
+	return created(withContextFields(ctx, f))
+}
+
+// WithStackCtx is like WithStack, but also annotates err with the fields
+// carried by ctx.
+// If err is nil, WithStackCtx returns nil.
+func WithStackCtx(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	w := &withStack{
+		err,
+		callersFor(err),
+	}
+	recordLabels(ctx, w.stack)
+	return created(transform(err, withContextFields(ctx, w)))
+}
+
+// WrapCtx is like Wrap, but also annotates err with the fields carried by
+// ctx.
+// If err is nil, WrapCtx returns nil.
+func WrapCtx(ctx context.Context, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	w := &withMessage{
+		cause: err,
+		msg:   message,
+	}
+	ws := &withStack{
+		w,
+		callersFor(err),
+	}
+	recordLabels(ctx, ws.stack)
+	return created(transform(err, withContextFields(ctx, ws)))
+}
+
+// WrapfCtx is like Wrapf, but also annotates err with the fields carried by
+// ctx.
+// If err is nil, WrapfCtx returns nil.
+func WrapfCtx(ctx context.Context, err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	w := &withMessage{
+		cause: err,
+		msg:   sprintf(format, args...),
+	}
+	ws := &withStack{
+		w,
+		callersFor(err),
+	}
+	recordLabels(ctx, ws.stack)
+	return created(transform(err, withContextFields(ctx, ws)))
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContextFields(t *testing.T) {
+	ctx := context.Background()
+	if got := ContextFields(ctx); got != nil {
+		t.Errorf("ContextFields(background): got %v, want nil", got)
+	}
+
+	ctx = WithContextFields(ctx, map[string]interface{}{"request_id": "r1", "tenant": "acme"})
+	ctx = WithContextFields(ctx, map[string]interface{}{"request_id": "r2"})
+	want := map[string]interface{}{"request_id": "r2", "tenant": "acme"}
+	if got := ContextFields(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("ContextFields(): got %v, want %v", got, want)
+	}
+}
+
+func TestCtxConstructors(t *testing.T) {
+	ctx := WithContextFields(context.Background(), map[string]interface{}{"request_id": "r1"})
+	want := map[string]interface{}{"request_id": "r1"}
+
+	tests := []struct {
+		err     error
+		message string
+	}{
+		{NewCtx(ctx, "error"), "error"},
+		{ErrorfCtx(ctx, "error %d", 1), "error 1"},
+		{WithStackCtx(ctx, io.EOF), "EOF"},
+		{WrapCtx(ctx, io.EOF, "read"), "read: EOF"},
+		{WrapfCtx(ctx, io.EOF, "read %d", 1), "read 1: EOF"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.message {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.message)
+		}
+		if got := Fields(tt.err); !reflect.DeepEqual(got, want) {
+			t.Errorf("test %d: Fields(): got %v, want %v", i+1, got, want)
+		}
+		if got := fmt.Sprintf("%+v", tt.err); !strings.Contains(got, "errors.TestCtxConstructors\n") {
+			t.Errorf("test %d: stack does not start at call site:\n%s", i+1, got)
+		}
+	}
+}
+
+func TestCtxConstructorsWithoutFields(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := NewCtx(ctx, "error").(*fundamental); !ok {
+		t.Error("NewCtx without fields: want plain error")
+	}
+	if got := WrapCtx(ctx, nil, "read"); got != nil {
+		t.Errorf("WrapCtx(ctx, nil, \"read\"): got %#v, expected nil", got)
+	}
+	if got := WrapfCtx(ctx, nil, "read"); got != nil {
+		t.Errorf("WrapfCtx(ctx, nil, \"read\"): got %#v, expected nil", got)
+	}
+	if got := WithStackCtx(ctx, nil); got != nil {
+		t.Errorf("WithStackCtx(ctx, nil): got %#v, expected nil", got)
+	}
+}
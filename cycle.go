@@ -0,0 +1,53 @@
+package errors
+
+import "reflect"
+
+// cycleMarker stands in for the rest of a chain that loops back on itself
+// when the chain is rendered.
+const cycleMarker = "[cycle]"
+
+// A cycle detects an error chain that loops back on itself, which no error of
+// this package can create but a type of another package wrapping one of them
+// can. Its zero value is ready to use: seen must be called with each error
+// of the chain in turn, and reports true once the chain has started to
+// repeat itself. It uses Brent's algorithm, so detection takes no memory but
+// may only happen a few rounds into the loop.
+type cycle struct {
+	mark  error // the last error recorded, compared with the following ones
+	power int
+	steps int
+}
+
+func (c *cycle) seen(err error) bool {
+	if c.mark != nil && c.mark == err {
+		return true
+	}
+	c.steps++
+	if c.steps >= c.power && isPointer(err) {
+		// Only pointers are recorded: comparing them never panics, and a
+		// chain can only loop back through a pointer.
+		c.mark = err
+		c.power = 2*c.power + 1
+		c.steps = 0
+	}
+	return false
+}
+
+// isPointer reports whether the dynamic type of err is a pointer.
+func isPointer(err error) bool {
+	return reflect.TypeOf(err).Kind() == reflect.Ptr
+}
+
+// onPath reports whether err is one of the errors in path, the errors
+// wrapping several errors that lead to err in a chain.
+func onPath(err error, path []error) bool {
+	if !isPointer(err) {
+		return false
+	}
+	for _, e := range path {
+		if e == err {
+			return true
+		}
+	}
+	return false
+}
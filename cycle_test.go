@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+// loop is an error of another package that can be made to wrap itself.
+type loop struct{ cause error }
+
+func (l *loop) Error() string { return "loop" }
+func (l *loop) Cause() error  { return l.cause }
+func (l *loop) Unwrap() error { return l.cause }
+
+var errMissing = New("missing")
+
+func TestCycle(t *testing.T) {
+	self := &loop{}
+	self.cause = self
+
+	indirect := &loop{}
+	indirect.cause = WithCode(Wrap(indirect, "wrapped"), "CODE")
+
+	joined := &loop{}
+	joined.cause = Join(New("first"), Wrap(joined, "second"))
+
+	for i, err := range []error{self, indirect, joined} {
+		Cause(err)
+		Root(err)
+		Code(err)
+		Fields(err)
+		KindOf(err)
+		SeverityOf(err)
+		HTTPStatus(err)
+		Fingerprint(err)
+		Message(err)
+		if Is(err, errMissing) {
+			t.Errorf("test %d: Is(): got true, want false", i+1)
+		}
+		var target *Validation
+		if As(err, &target) {
+			t.Errorf("test %d: As(): got true, want false", i+1)
+		}
+		if IsPanic(err) {
+			t.Errorf("test %d: IsPanic(): got true, want false", i+1)
+		}
+		PanicValue(err)
+		if n := len(Chain(err)); n > 64 {
+			t.Errorf("test %d: len(Chain()): got %d, want a truncated chain", i+1, n)
+		}
+		if got := FlattenMessages(err, " > "); !strings.HasSuffix(got, cycleMarker) {
+			t.Errorf("test %d: FlattenMessages(): got %q, want suffix %q", i+1, got, cycleMarker)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+)
+
+// maxWrapDepth is the number of layers beyond which wrapping an error
+// collapses, or zero if it never does.
+var maxWrapDepth int32
+
+// SetMaxWrapDepth sets the number of layers an error chain may grow to by
+// wrapping with this package, whether with Wrap, WithStack, WithMessage or
+// any of their variants. Once a chain is that deep, wrapping it further
+// adds no layer: the wraps are counted instead, and reported by %+v as
+// "+ N more wraps", so that a program wrapping the same error in a loop
+// holds on to a bounded amount of memory. The messages, stack traces and
+// other attachments of the collapsed wraps are dropped. A depth of zero or
+// less, the default, leaves chains unbounded.
+func SetMaxWrapDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	atomic.StoreInt32(&maxWrapDepth, int32(depth))
+}
+
+// collapse returns the error standing for cause wrapped once more if its
+// chain has reached the maximum wrap depth, or nil if it may be wrapped.
+func collapse(cause error) error {
+	max := int(atomic.LoadInt32(&maxWrapDepth))
+	if max == 0 {
+		return nil
+	}
+	if c, ok := cause.(*withCollapsed); ok {
+		return &withCollapsed{cause: c.cause, wraps: c.wraps + 1}
+	}
+	depth := 0
+	var c cycle
+	for err := cause; err != nil && !c.seen(err); err = Unwrap(err) {
+		if depth++; depth >= max {
+			return &withCollapsed{cause: cause, wraps: 1}
+		}
+	}
+	return nil
+}
+
+// withCollapsed stands for cause wrapped a number of times beyond the
+// maximum wrap depth.
+type withCollapsed struct {
+	cause error
+	wraps int
+}
+
+func (w *withCollapsed) Error() string { return redact(w.cause.Error()) }
+func (w *withCollapsed) Cause() error  { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withCollapsed) Unwrap() error { return w.cause }
+
+func (w *withCollapsed) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, w.more())
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// more returns the detail line reporting the collapsed wraps.
+func (w *withCollapsed) more() string {
+	if w.wraps == 1 {
+		return "+ 1 more wrap"
+	}
+	return "+ " + strconv.Itoa(w.wraps) + " more wraps"
+}
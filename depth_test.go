@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMaxWrapDepth(t *testing.T) {
+	SetMaxWrapDepth(3)
+	defer SetMaxWrapDepth(0)
+
+	tests := []struct {
+		wraps int
+		depth int
+		more  string
+	}{
+		{1, 2, ""},
+		{2, 3, ""},
+		{3, 4, "+ 1 more wrap"},
+		{4000, 4, "+ 3998 more wraps"},
+	}
+
+	for i, tt := range tests {
+		err := io.EOF
+		for j := 0; j < tt.wraps; j++ {
+			err = WithMessage(err, "retry")
+		}
+		depth := 0
+		for e := err; e != nil; e = Unwrap(e) {
+			depth++
+		}
+		if depth != tt.depth {
+			t.Errorf("test %d: got depth %d, want %d", i+1, depth, tt.depth)
+		}
+		if got, want := err.Error(), strings.Repeat("retry: ", min(tt.wraps, 2))+"EOF"; got != want {
+			t.Errorf("test %d: got message %q, want %q", i+1, got, want)
+		}
+		if got := fmt.Sprintf("%+v", err); tt.more != "" && !strings.HasSuffix(got, "\n"+tt.more) {
+			t.Errorf("test %d: got %q, want suffix %q", i+1, got, tt.more)
+		}
+	}
+
+	SetMaxWrapDepth(0)
+	err := io.EOF
+	for j := 0; j < 10; j++ {
+		err = Wrap(err, "retry")
+	}
+	if got, want := err.Error(), strings.Repeat("retry: ", 10)+"EOF"; got != want {
+		t.Errorf("unbounded: got message %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A StackDiff describes how two stack traces differ. Frames are compared by
+// function, file and line, so two captures made at the same place compare
+// equal.
+type StackDiff struct {
+	// Prefix holds the innermost frames the stack traces share.
+	Prefix StackTrace
+	// Suffix holds the outermost frames the stack traces share, beyond
+	// Prefix.
+	Suffix StackTrace
+	// A and B hold the frames found only in the first and second stack
+	// traces respectively, between Prefix and Suffix.
+	A, B StackTrace
+}
+
+// Diff compares st with other. It is useful to check in tests that wrapping
+// an error preserved the point where its stack trace was recorded, or to
+// find where two captures of the same error diverge.
+func (st StackTrace) Diff(other StackTrace) StackDiff {
+	n := 0
+	for n < len(st) && n < len(other) && sameFrame(st[n], other[n]) {
+		n++
+	}
+	m := 0
+	for m < len(st)-n && m < len(other)-n && sameFrame(st[len(st)-1-m], other[len(other)-1-m]) {
+		m++
+	}
+	return StackDiff{
+		Prefix: st[:n:n],
+		Suffix: st[len(st)-m:],
+		A:      st[n : len(st)-m : len(st)-m],
+		B:      other[n : len(other)-m : len(other)-m],
+	}
+}
+
+// Equal reports whether the compared stack traces have the same frames.
+func (d StackDiff) Equal() bool { return len(d.A) == 0 && len(d.B) == 0 }
+
+// String describes d: the number of frames shared at either end, and the
+// differing frames in between, prefixed with "-" for the first stack trace
+// and "+" for the second.
+func (d StackDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d common innermost frames", len(d.Prefix))
+	for _, f := range d.A {
+		fmt.Fprintf(&b, "\n- %+v", f)
+	}
+	for _, f := range d.B {
+		fmt.Fprintf(&b, "\n+ %+v", f)
+	}
+	fmt.Fprintf(&b, "\n%d common outermost frames", len(d.Suffix))
+	return b.String()
+}
+
+// sameFrame reports whether f and g refer to the same source location.
+func sameFrame(f, g Frame) bool {
+	if f == g {
+		return true
+	}
+	fname, ffile, fline := f.resolve()
+	gname, gfile, gline := g.resolve()
+	return fline == gline && ffile == gfile && fname == gname
+}
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStackTraceDiff(t *testing.T) {
+	inner := NewFrame("app.inner", "/app/inner.go", 1)
+	a := NewFrame("app.a", "/app/a.go", 2)
+	b := NewFrame("app.b", "/app/b.go", 3)
+	outer := NewFrame("app.main", "/app/main.go", 4)
+
+	tests := []struct {
+		st, other StackTrace
+		want      StackDiff
+	}{{
+		nil, nil,
+		StackDiff{},
+	}, {
+		StackTrace{inner, a, outer}, StackTrace{inner, a, outer},
+		StackDiff{Prefix: StackTrace{inner, a, outer}, Suffix: StackTrace{}, A: StackTrace{}, B: StackTrace{}},
+	}, {
+		StackTrace{inner, a, outer}, StackTrace{inner, b, outer},
+		StackDiff{Prefix: StackTrace{inner}, Suffix: StackTrace{outer}, A: StackTrace{a}, B: StackTrace{b}},
+	}, {
+		StackTrace{a, outer}, StackTrace{inner, b, outer},
+		StackDiff{Prefix: StackTrace{}, Suffix: StackTrace{outer}, A: StackTrace{a}, B: StackTrace{inner, b}},
+	}, {
+		StackTrace{inner, outer}, StackTrace{inner, a, outer},
+		StackDiff{Prefix: StackTrace{inner}, Suffix: StackTrace{outer}, A: StackTrace{}, B: StackTrace{a}},
+	}}
+
+	for i, tt := range tests {
+		got := tt.st.Diff(tt.other)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: Diff(): got %#v, want %#v", i+1, got, tt.want)
+		}
+		if got.Equal() != (len(tt.want.A) == 0 && len(tt.want.B) == 0) {
+			t.Errorf("test %d: Equal(): got %v", i+1, got.Equal())
+		}
+	}
+
+	want := "1 common innermost frames\n" +
+		"- app.a\n\t/app/a.go:2\n" +
+		"+ app.b\n\t/app/b.go:3\n" +
+		"1 common outermost frames"
+	if got := (StackTrace{inner, a, outer}).Diff(StackTrace{inner, b, outer}).String(); got != want {
+		t.Errorf("String(): got %q, want %q", got, want)
+	}
+}
+
+func TestStackTraceDiffWrap(t *testing.T) {
+	err := New("origin")
+	wrapped := WithStack(err)
+	st := err.(interface{ StackTrace() StackTrace }).StackTrace()
+	other := wrapped.(interface{ StackTrace() StackTrace }).StackTrace()
+	if d := st.Diff(other); d.Equal() || len(d.Suffix) == 0 {
+		t.Errorf("Diff() of captures on different lines: got %v", d)
+	}
+}
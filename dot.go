@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ToDOT returns a description of the structure of err's chain in the DOT
+// language of Graphviz, with one node per error and an edge from each error
+// to each error it wraps. Every node is labelled with the type of the error,
+// its own message and, when the error carries them itself, its code and the
+// innermost frame of its stack trace:
+//
+//	errors.ToDOT(err) // render with: dot -Tsvg
+//
+// An error wrapped by several errors, or wrapping one of the errors that
+// wrap it, is drawn once. ToDOT returns an empty graph if err is nil.
+func ToDOT(err error) string {
+	g := &dotGraph{ids: make(map[error]string)}
+	g.b.WriteString("digraph errors {\n\tnode [shape=box];\n")
+	if err != nil {
+		g.node(err)
+	}
+	g.b.WriteString("}\n")
+	return g.b.String()
+}
+
+// dotGraph accumulates the DOT description of an error chain.
+type dotGraph struct {
+	b   strings.Builder
+	ids map[error]string // of the errors drawn, by error for pointers
+	n   int
+}
+
+// node draws err and the errors it wraps, and returns the id of its node.
+func (g *dotGraph) node(err error) string {
+	if isPointer(err) {
+		if id, ok := g.ids[err]; ok {
+			return id
+		}
+	}
+	id := "n" + strconv.Itoa(g.n)
+	g.n++
+	if isPointer(err) {
+		g.ids[err] = id
+	}
+	fmt.Fprintf(&g.b, "\t%s [label=%s];\n", id, dotQuote(dotLabel(err)))
+
+	var wrapped []error
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		wrapped = u.Unwrap()
+	case interface{ Unwrap() error }:
+		if e := u.Unwrap(); e != nil {
+			wrapped = []error{e}
+		}
+	}
+	for _, e := range wrapped {
+		fmt.Fprintf(&g.b, "\t%s -> %s;\n", id, g.node(e))
+	}
+	return id
+}
+
+// dotLabel returns the label of the node of err.
+func dotLabel(err error) string {
+	lines := []string{fmt.Sprintf("%T", err)}
+	if _, ok := err.(interface{ Unwrap() []error }); !ok {
+		if msg := ownMessage(err); msg != "" {
+			lines = append(lines, msg)
+		}
+	}
+	if c, ok := err.(interface{ Code() string }); ok && c.Code() != "" {
+		lines = append(lines, "code: "+c.Code())
+	}
+	if s, ok := err.(interface{ StackTrace() StackTrace }); ok {
+		if st := s.StackTrace(); len(st) > 0 {
+			lines = append(lines, fmt.Sprintf("at %s %v", st[0].name(), st[0]))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dotQuote returns s as a double-quoted DOT string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestToDOT(t *testing.T) {
+	if got, want := ToDOT(nil), "digraph errors {\n\tnode [shape=box];\n}\n"; got != want {
+		t.Errorf("ToDOT(nil): got %q, want %q", got, want)
+	}
+
+	f := NewFrame("app.load", "/src/app/load.go", 3)
+	shared := &withStack{io.EOF, &stack{pcs: []uintptr{uintptr(f)}}}
+	err := Join(WithCode(WithMessage(shared, `read "a"`), "READ"), shared)
+	want := `digraph errors {
+	node [shape=box];
+	n0 [label="*errors.joinError"];
+	n1 [label="*errors.withCode\ncode: READ"];
+	n2 [label="*errors.withMessage\nread \"a\""];
+	n3 [label="*errors.withStack\nat app.load load.go:3"];
+	n4 [label="*errors.errorString\nEOF"];
+	n3 -> n4;
+	n2 -> n3;
+	n1 -> n2;
+	n0 -> n1;
+	n0 -> n3;
+}
+`
+	if got := ToDOT(err); got != want {
+		t.Errorf("ToDOT():\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestToDOTCycle(t *testing.T) {
+	l := &loop{}
+	l.cause = Wrap(l, "again")
+	if got := ToDOT(l); len(got) == 0 {
+		t.Error("ToDOT() of a cycle: got empty output")
+	}
+}
@@ -0,0 +1,216 @@
+package errors
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Goroutine describes a goroutine of a dump parsed by ParseDump.
+type Goroutine struct {
+	ID int64
+
+	// State is the state reported in the header of the goroutine, such as
+	// "running" or "chan receive, 2 minutes".
+	State string
+
+	// Stack is the stack of the goroutine, innermost frame first. The frames
+	// hold the function, file and line of the dump, but no Frame or program
+	// counter, as they may not belong to this program.
+	Stack []FrameInfo
+
+	// CreatedBy is the frame which started the goroutine, or the zero
+	// FrameInfo if the dump does not report it.
+	CreatedBy FrameInfo
+}
+
+// ParseDump parses text written by the Go runtime when a program panics or
+// crashes, or by runtime.Stack, such as the standard error of a child
+// process which crashed, into an error, so that the crash can be logged and
+// reported as errors of this package are:
+//
+//	if err := cmd.Run(); err != nil {
+//		if crash := errors.ParseDump(stderr.String()); crash != nil {
+//			err = errors.Wrap(crash, "worker crashed")
+//		}
+//	}
+//
+// The message of the error is the "panic: " or "fatal error: " line of the
+// text, or the header of the first goroutine if there is none. The error
+// prints the stack of the first goroutine, which is the goroutine that
+// panicked, when formatted with %+v, and GoroutineID returns its ID. As the
+// frames of the text may not belong to this program, the error holds them
+// as text rather than as a StackTrace. Goroutines returns all the
+// goroutines of the text.
+// ParseDump returns nil if text holds no goroutine.
+func ParseDump(text string) error {
+	var (
+		msg string
+		gs  []Goroutine
+		g   *Goroutine
+		fn  string // function of the frame whose location is expected next
+	)
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Buffer(nil, maxGoroutineDump)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		switch {
+		case strings.HasPrefix(line, "goroutine "):
+			if id, state, ok := parseGoroutineHeader(line); ok {
+				gs = append(gs, Goroutine{ID: id, State: state})
+				g, fn = &gs[len(gs)-1], ""
+				continue
+			}
+		case g == nil:
+			if msg == "" && (strings.HasPrefix(line, "panic: ") || strings.HasPrefix(line, "fatal error: ")) {
+				msg = line
+			}
+			continue
+		}
+		switch {
+		case line == "":
+			g, fn = nil, ""
+		case strings.HasPrefix(line, "\t"):
+			if fn == "" {
+				continue
+			}
+			file, n := parseDumpLocation(line)
+			f := FrameInfo{Function: strings.TrimPrefix(fn, "created by "), File: file, Line: n}
+			if strings.HasPrefix(fn, "created by ") {
+				g.CreatedBy = f
+			} else {
+				g.Stack = append(g.Stack, f)
+			}
+			fn = ""
+		case strings.HasPrefix(line, "created by "):
+			// Since Go 1.21: "created by main.main in goroutine 1".
+			if i := strings.Index(line, " in goroutine "); i > 0 {
+				line = line[:i]
+			}
+			fn = line
+		case strings.HasPrefix(line, "..."):
+			// "...additional frames elided..."
+		default:
+			fn = trimDumpArgs(line)
+		}
+	}
+	if len(gs) == 0 {
+		return nil
+	}
+	return created(&dumpError{msg: msg, goroutines: gs})
+}
+
+// Goroutines returns the goroutines of the dump err was parsed from by
+// ParseDump, or nil if there is no such dump in err's chain.
+func Goroutines(err error) []Goroutine {
+	var d *dumpError
+	if !As(err, &d) {
+		return nil
+	}
+	return append([]Goroutine(nil), d.goroutines...)
+}
+
+// parseGoroutineHeader parses a line such as "goroutine 7 [chan receive]:"
+// into the ID and state of the goroutine.
+func parseGoroutineHeader(line string) (id int64, state string, ok bool) {
+	rest := strings.TrimPrefix(line, "goroutine ")
+	i := strings.IndexByte(rest, ' ')
+	if i < 0 {
+		return 0, "", false
+	}
+	id, err := strconv.ParseInt(rest[:i], 10, 64)
+	open, end := strings.IndexByte(rest, '['), strings.LastIndex(rest, "]:")
+	if err != nil || open < 0 || end < open {
+		return 0, "", false
+	}
+	return id, rest[open+1 : end], true
+}
+
+// parseDumpLocation parses a line such as "\t/src/app/main.go:7 +0x25" into
+// a file and line number.
+func parseDumpLocation(s string) (file string, line int) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, " +0x"); i > 0 {
+		s = s[:i]
+	}
+	if i := strings.LastIndexByte(s, ':'); i > 0 {
+		if n, err := strconv.Atoi(s[i+1:]); err == nil {
+			return s[:i], n
+		}
+	}
+	return s, 0
+}
+
+// trimDumpArgs removes the argument list a dump prints after the name of the
+// function of a frame, as in "main.(*T).run(0xc000012345, {0x1, 0x2})".
+func trimDumpArgs(s string) string {
+	if !strings.HasSuffix(s, ")") {
+		return s
+	}
+	depth := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		switch s[i] {
+		case ')':
+			depth++
+		case '(':
+			if depth--; depth == 0 {
+				return s[:i]
+			}
+		}
+	}
+	return s
+}
+
+// dumpError is an error parsed from a goroutine dump by ParseDump.
+type dumpError struct {
+	msg        string
+	goroutines []Goroutine
+}
+
+func (d *dumpError) Error() string {
+	if d.msg == "" {
+		g := d.goroutines[0]
+		return redact("goroutine " + strconv.FormatInt(g.ID, 10) + " [" + g.State + "]")
+	}
+	return redact(d.msg)
+}
+
+// goroutine returns the first goroutine of the dump, whose ID GoroutineID
+// reports.
+func (d *dumpError) goroutine() *goroutineInfo {
+	return &goroutineInfo{id: d.goroutines[0].ID}
+}
+
+// stackLines returns the stack of the first goroutine of the dump, two lines
+// per frame, as %+v prints a StackTrace: the function, then the file and
+// line preceded by a tab.
+func (d *dumpError) stackLines() []string {
+	var lines []string
+	for _, f := range d.goroutines[0].Stack {
+		lines = append(lines, f.Function, "\t"+f.File+":"+strconv.Itoa(f.Line))
+	}
+	return lines
+}
+
+func (d *dumpError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, d) {
+				return
+			}
+			io.WriteString(s, d.Error())
+			for _, line := range d.stackLines() {
+				io.WriteString(s, "\n"+line)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, d.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", d.Error())
+	}
+}
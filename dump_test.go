@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const testDump = `some earlier output
+panic: runtime error: index out of range [3] with length 3
+
+goroutine 7 [running]:
+example.com/app.(*Server).handle(0xc000010000, {0xc000020000, 0x3})
+	/src/app/server.go:42 +0x1d
+example.com/app.Map[...](...)
+	/src/app/map.go:9
+created by example.com/app.main in goroutine 1
+	/src/app/main.go:12 +0x5e
+
+goroutine 1 [chan receive, 2 minutes]:
+main.main()
+	/src/app/main.go:15 +0x8c
+exit status 2
+`
+
+func TestParseDump(t *testing.T) {
+	err := ParseDump(testDump)
+	if err == nil {
+		t.Fatal("ParseDump(): got nil")
+	}
+	if got, want := err.Error(), "panic: runtime error: index out of range [3] with length 3"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if id, ok := GoroutineID(err); id != 7 || !ok {
+		t.Errorf("GoroutineID(): got %d, %t, want 7, true", id, ok)
+	}
+	want := "panic: runtime error: index out of range [3] with length 3" +
+		"\nexample.com/app.(*Server).handle\n\t/src/app/server.go:42" +
+		"\nexample.com/app.Map[...]\n\t/src/app/map.go:9"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("fmt.Sprintf(%%+v, err): got %q, want %q", got, want)
+	}
+
+	gs := Goroutines(Wrap(err, "worker crashed"))
+	if len(gs) != 2 {
+		t.Fatalf("Goroutines(): got %d goroutines, want 2", len(gs))
+	}
+	tests := []struct {
+		g         Goroutine
+		id        int64
+		state     string
+		frames    int
+		createdBy string
+	}{
+		{gs[0], 7, "running", 2, "example.com/app.main"},
+		{gs[1], 1, "chan receive, 2 minutes", 1, ""},
+	}
+	for i, tt := range tests {
+		if tt.g.ID != tt.id || tt.g.State != tt.state || len(tt.g.Stack) != tt.frames {
+			t.Errorf("test %d: got goroutine %d [%s] with %d frames, want %d [%s] with %d", i+1,
+				tt.g.ID, tt.g.State, len(tt.g.Stack), tt.id, tt.state, tt.frames)
+		}
+		if tt.g.CreatedBy.Function != tt.createdBy {
+			t.Errorf("test %d: CreatedBy: got %q, want %q", i+1, tt.g.CreatedBy.Function, tt.createdBy)
+		}
+	}
+	if got, want := gs[1].Stack[0], (FrameInfo{Function: "main.main", File: "/src/app/main.go", Line: 15}); got != want {
+		t.Errorf("frame: got %+v, want %+v", got, want)
+	}
+
+	if err := ParseDump("exit status 1\n"); err != nil {
+		t.Errorf("ParseDump(no goroutines): got %v, want nil", err)
+	}
+	if Goroutines(New("error")) != nil {
+		t.Error("Goroutines(New()): got goroutines")
+	}
+}
+
+func TestParseDumpRuntimeStack(t *testing.T) {
+	buf := make([]byte, 1<<16)
+	err := ParseDump(string(buf[:runtime.Stack(buf, false)]))
+	if err == nil || !strings.HasSuffix(err.Error(), "[running]") {
+		t.Fatalf("ParseDump(): got %v", err)
+	}
+	st := Goroutines(err)[0].Stack
+	if len(st) == 0 || st[0].Function != "github.com/peakle/errors.TestParseDumpRuntimeStack" || !strings.HasSuffix(st[0].File, "dump_test.go") {
+		t.Errorf("ParseDump(): got stack %+v", st)
+	}
+}
@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToECS returns err as the error fields of the Elastic Common Schema, keyed
+// by their dotted names, so that it is mapped without a custom ingest
+// pipeline when logged as part of an ECS document:
+//
+//	error.message      the message of err
+//	error.type         the Go type of the cause of err, as returned by Cause
+//	error.code         the code of err
+//	error.stack_trace  the stack trace recorded closest to the root cause
+//
+// The fields err does not carry are left out. ToECS returns nil if err is
+// nil.
+func ToECS(err error) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+	m := map[string]interface{}{
+		"error.message": err.Error(),
+		"error.type":    fmt.Sprintf("%T", Cause(err)),
+	}
+	if code := Code(err); code != "" {
+		m["error.code"] = code
+	}
+	if st := originStack(err); len(st) > 0 {
+		m["error.stack_trace"] = strings.TrimPrefix(fmt.Sprintf("%+v", st), "\n")
+	}
+	return m
+}
@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestToECS(t *testing.T) {
+	if got := ToECS(nil); got != nil {
+		t.Errorf("ToECS(nil): got %v, want nil", got)
+	}
+
+	_, err := os.Open("/nonexistent")
+	err = WithCode(Wrap(err, "load config"), "CONFIG")
+	got := ToECS(err)
+
+	want := map[string]string{
+		"error.message": "load config: open /nonexistent: no such file or directory",
+		"error.type":    "*fs.PathError",
+		"error.code":    "CONFIG",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("ToECS()[%q]: got %v, want %q", k, got[k], v)
+		}
+	}
+	st, _ := got["error.stack_trace"].(string)
+	if !strings.HasPrefix(st, "github.com/peakle/errors.TestToECS\n\t") {
+		t.Errorf("ToECS()[%q]: got %q, want the stack trace of TestToECS", "error.stack_trace", st)
+	}
+
+	got = ToECS(&fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist})
+	if _, ok := got["error.code"]; ok {
+		t.Errorf("ToECS(): got error.code %v, want none", got["error.code"])
+	}
+	if _, ok := got["error.stack_trace"]; ok {
+		t.Errorf("ToECS(): got error.stack_trace, want none")
+	}
+}
@@ -0,0 +1,28 @@
+package errors
+
+import "reflect"
+
+// EqualIgnoreStack reports whether a and b carry the same information,
+// ignoring the stack traces they record: the same message for every layer
+// of their chains, and the same code, kind, severity, HTTP status and
+// fields. It lets table-driven tests compare rich errors deterministically:
+//
+//	want := errors.WithCode(errors.New("user not found"), "USER_NOT_FOUND")
+//	if !errors.EqualIgnoreStack(err, want) {
+//		t.Errorf("got %+v, want %+v", err, want)
+//	}
+//
+// EqualIgnoreStack reports true if a and b are both nil.
+func EqualIgnoreStack(a, b error) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	const sep = "\x00"
+	return a.Error() == b.Error() &&
+		FlattenMessages(a, sep) == FlattenMessages(b, sep) &&
+		Code(a) == Code(b) &&
+		KindOf(a) == KindOf(b) &&
+		SeverityOf(a) == SeverityOf(b) &&
+		HTTPStatus(a) == HTTPStatus(b) &&
+		reflect.DeepEqual(Fields(a), Fields(b))
+}
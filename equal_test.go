@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestEqualIgnoreStack(t *testing.T) {
+	newErr := func() error {
+		return WithField(WithCode(Wrap(New("not found"), "get user"), "NOT_FOUND"), "id", 42)
+	}
+
+	tests := []struct {
+		a, b error
+		want bool
+	}{
+		{nil, nil, true},
+		{nil, io.EOF, false},
+		{io.EOF, nil, false},
+		{io.EOF, io.EOF, true},
+		{New("x"), New("x"), true},
+		{New("x"), Errorf("x"), true},
+		{New("x"), New("y"), false},
+		{newErr(), newErr(), true},
+		{newErr(), WithCode(Wrap(New("not found"), "get user"), "NOT_FOUND"), false},
+		{newErr(), WithField(WithCode(Wrap(New("not found"), "get user"), "OTHER"), "id", 42), false},
+		{newErr(), WithField(WithCode(Wrap(New("not found"), "get user"), "NOT_FOUND"), "id", 43), false},
+		{WithKind(io.EOF, NotFound), WithKind(io.EOF, Internal), false},
+		{WithSeverity(io.EOF, SeverityWarning), WithSeverity(io.EOF, SeverityCritical), false},
+		{WithHTTPStatus(io.EOF, 404), WithHTTPStatus(io.EOF, 410), false},
+		{Wrap(New("a: b"), "c"), Wrap(Wrap(New("b"), "a"), "c"), false}, // same text, different layers
+	}
+
+	for i, tt := range tests {
+		if got := EqualIgnoreStack(tt.a, tt.b); got != tt.want {
+			t.Errorf("test %d: EqualIgnoreStack(%v, %v): got %v, want %v", i+1, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
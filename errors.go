@@ -100,20 +100,34 @@ import (
 // New returns an error with the supplied message.
 // New also records the stack trace at the point it was called.
 func New(message string) error {
-	return &fundamental{
-		msg:   message,
-		stack: callers(),
-	}
+	return created(newFundamental(message))
 }
 
 // Errorf formats according to a format specifier and returns the string
 // as a value that satisfies error.
 // Errorf also records the stack trace at the point it was called.
 func Errorf(format string, args ...interface{}) error {
-	return &fundamental{
-		msg:   fmt.Sprintf(format, args...),
-		stack: callers(),
-	}
+	return created(newFundamental(sprintf(format, args...)))
+}
+
+// newFundamental returns an error with the supplied message, recording the
+// stack trace of the caller of its caller. The stack trace is recorded into
+// the error itself, so that unless the stack depth exceeds the default, the
+// error takes a single allocation.
+func newFundamental(msg string) *fundamental {
+	f := &inlineFundamental{fundamental: fundamental{msg: msg}}
+	f.st.pcs = captureStack(4, f.pcs[:])
+	f.stack = &f.st
+	recordGoroutine(&f.st)
+	return &f.fundamental
+}
+
+// inlineFundamental is a fundamental allocated together with the storage
+// for its stack trace.
+type inlineFundamental struct {
+	fundamental
+	st  stack
+	pcs [defaultStackDepth]uintptr
 }
 
 // fundamental is an error that has a message and a stack, but no caller.
@@ -122,21 +136,24 @@ type fundamental struct {
 	*stack
 }
 
-func (f *fundamental) Error() string { return f.msg }
+func (f *fundamental) Error() string { return redact(f.msg) }
 
 func (f *fundamental) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if s.Flag('+') {
-			io.WriteString(s, f.msg)
+			if formatStyled(s, f) {
+				return
+			}
+			io.WriteString(s, f.Error())
 			f.stack.Format(s, verb)
 			return
 		}
 		fallthrough
 	case 's':
-		io.WriteString(s, f.msg)
+		io.WriteString(s, f.Error())
 	case 'q':
-		fmt.Fprintf(s, "%q", f.msg)
+		fmt.Fprintf(s, "%q", f.Error())
 	}
 }
 
@@ -146,10 +163,37 @@ func WithStack(err error) error {
 	if err == nil {
 		return nil
 	}
-	return &withStack{
+	return created(transform(err, &withStack{
 		err,
-		callers(),
+		callersFor(err),
+	}))
+}
+
+// WithStackTrace annotates err with st, a stack trace captured elsewhere,
+// such as the stack trace of another error or of a recovered panic, in place
+// of the stack trace at the point WithStackTrace was called.
+// If err is nil, WithStackTrace returns nil.
+func WithStackTrace(err error, st StackTrace) error {
+	if err == nil {
+		return nil
 	}
+	s := stack{pcs: make([]uintptr, len(st))}
+	for i, f := range st {
+		s.pcs[i] = uintptr(f)
+	}
+	return created(transform(err, &withStack{err, &s}))
+}
+
+// WithCallers annotates err with the stack trace made of the program
+// counters pcs, as filled in by runtime.Callers, in place of the stack trace
+// at the point WithCallers was called.
+// If err is nil, WithCallers returns nil.
+func WithCallers(err error, pcs []uintptr) error {
+	if err == nil {
+		return nil
+	}
+	s := stack{pcs: append([]uintptr(nil), pcs...)}
+	return created(transform(err, &withStack{err, &s}))
 }
 
 type withStack struct {
@@ -157,7 +201,8 @@ type withStack struct {
 	*stack
 }
 
-func (w *withStack) Cause() error { return w.error }
+func (w *withStack) Error() string { return redact(w.error.Error()) }
+func (w *withStack) Cause() error  { return w.error }
 
 // Unwrap provides compatibility for Go 1.13 error chains.
 func (w *withStack) Unwrap() error { return w.error }
@@ -166,7 +211,10 @@ func (w *withStack) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if s.Flag('+') {
-			fmt.Fprintf(s, "%+v", w.Cause())
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v", w.Cause()))
 			w.stack.Format(s, verb)
 			return
 		}
@@ -185,14 +233,14 @@ func Wrap(err error, message string) error {
 	if err == nil {
 		return nil
 	}
-	err = &withMessage{
+	w := &withMessage{
 		cause: err,
 		msg:   message,
 	}
-	return &withStack{
-		err,
-		callers(),
-	}
+	return created(transform(err, &withStack{
+		w,
+		callersFor(err),
+	}))
 }
 
 // Wrapf returns an error annotating err with a stack trace
@@ -202,14 +250,31 @@ func Wrapf(err error, format string, args ...interface{}) error {
 	if err == nil {
 		return nil
 	}
-	err = &withMessage{
+	w := &withMessage{
 		cause: err,
-		msg:   fmt.Sprintf(format, args...),
-	}
-	return &withStack{
-		err,
-		callers(),
+		msg:   sprintf(format, args...),
 	}
+	return created(transform(err, &withStack{
+		w,
+		callersFor(err),
+	}))
+}
+
+// WrapNoStack returns an error annotating err with the supplied message,
+// like Wrap, but without recording a stack trace, making it suitable for
+// inner loops and hot paths where an outer layer records the trace. It is
+// equivalent to WithMessage; structured context can be added with
+// WithField, which does not record a stack trace either.
+// If err is nil, WrapNoStack returns nil.
+func WrapNoStack(err error, message string) error {
+	return WithMessage(err, message)
+}
+
+// WrapfNoStack is like Wrapf but does not record a stack trace.
+// It is equivalent to WithMessagef.
+// If err is nil, WrapfNoStack returns nil.
+func WrapfNoStack(err error, format string, args ...interface{}) error {
+	return WithMessagef(err, format, args...)
 }
 
 // WithMessage annotates err with a new message.
@@ -218,10 +283,10 @@ func WithMessage(err error, message string) error {
 	if err == nil {
 		return nil
 	}
-	return &withMessage{
+	return created(transform(err, &withMessage{
 		cause: err,
 		msg:   message,
-	}
+	}))
 }
 
 // WithMessagef annotates err with the format specifier.
@@ -230,10 +295,10 @@ func WithMessagef(err error, format string, args ...interface{}) error {
 	if err == nil {
 		return nil
 	}
-	return &withMessage{
+	return created(transform(err, &withMessage{
 		cause: err,
-		msg:   fmt.Sprintf(format, args...),
-	}
+		msg:   sprintf(format, args...),
+	}))
 }
 
 type withMessage struct {
@@ -241,7 +306,7 @@ type withMessage struct {
 	msg   string
 }
 
-func (w *withMessage) Error() string { return w.msg + ": " + w.cause.Error() }
+func (w *withMessage) Error() string { return redact(prefixMessage(w.msg, w.cause.Error())) }
 func (w *withMessage) Cause() error  { return w.cause }
 
 // Unwrap provides compatibility for Go 1.13 error chains.
@@ -251,8 +316,11 @@ func (w *withMessage) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if s.Flag('+') {
-			fmt.Fprintf(s, "%+v\n", w.Cause())
-			io.WriteString(s, w.msg)
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, redact(w.msg))
 			return
 		}
 		fallthrough
@@ -277,7 +345,8 @@ func Cause(err error) error {
 		Cause() error
 	}
 
-	for err != nil {
+	var c cycle
+	for err != nil && !c.seen(err) {
 		cause, ok := err.(causer)
 		if !ok {
 			break
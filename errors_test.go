@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"runtime"
 	"testing"
 )
 
@@ -249,3 +250,62 @@ func TestErrorEquality(t *testing.T) {
 		}
 	}
 }
+
+func TestWrapNoStack(t *testing.T) {
+	if got := WrapNoStack(nil, "no error"); got != nil {
+		t.Errorf("WrapNoStack(nil, \"no error\"): got %#v, expected nil", got)
+	}
+	if got := WrapfNoStack(nil, "no error"); got != nil {
+		t.Errorf("WrapfNoStack(nil, \"no error\"): got %#v, expected nil", got)
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{WrapNoStack(io.EOF, "read error"), "read error: EOF"},
+		{WrapfNoStack(io.EOF, "read error %d", 1), "read error 1: EOF"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+		if got := fmt.Sprintf("%+v", tt.err); got != "EOF\n"+tt.want[:len(tt.want)-len(": EOF")] {
+			t.Errorf("fmt.Sprintf(%%+v, err): got %q, want no stack trace", got)
+		}
+	}
+}
+
+func TestWithStackTrace(t *testing.T) {
+	if got := WithStackTrace(nil, nil); got != nil {
+		t.Errorf("WithStackTrace(nil, nil): got %#v, expected nil", got)
+	}
+	if got := WithCallers(nil, nil); got != nil {
+		t.Errorf("WithCallers(nil, nil): got %#v, expected nil", got)
+	}
+
+	f := NewFrame("app.worker", "/src/app/worker.go", 21)
+	st := StackTrace{f}
+	err := WithStackTrace(io.EOF, st)
+	st[0] = 0
+	if got, want := fmt.Sprintf("%+v", err), "EOF\napp.worker\n\t/src/app/worker.go:21"; got != want {
+		t.Errorf("fmt.Sprintf(%%+v, WithStackTrace()): got %q, want %q", got, want)
+	}
+
+	pcs := make([]uintptr, 8)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+	err = WithCallers(io.EOF, pcs)
+	got := err.(interface{ StackTrace() StackTrace }).StackTrace()
+	if len(got) != len(pcs) || got[0].Func() != "github.com/peakle/errors.TestWithStackTrace" {
+		t.Errorf("WithCallers(): got stack trace %v, want %d frames starting in TestWithStackTrace", got, len(pcs))
+	}
+}
+
+func TestNewAllocs(t *testing.T) {
+	if n := testing.AllocsPerRun(100, func() { GlobalE = New("error") }); n > 1 {
+		t.Errorf("New(): got %v allocations, want at most 1", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { GlobalE = Errorf("error") }); n > 1 {
+		t.Errorf("Errorf(): got %v allocations, want at most 1", n)
+	}
+}
@@ -0,0 +1,116 @@
+// Package errortest provides assertions about errors for use in tests.
+//
+// Each assertion reports a failure with t.Errorf, followed by a dump of
+// every layer of the error's chain, and returns whether it held:
+//
+//	func TestGetUser(t *testing.T) {
+//		_, err := store.GetUser(ctx, "missing")
+//		errortest.AssertIs(t, err, store.ErrNotFound)
+//		errortest.AssertCode(t, err, "USER_NOT_FOUND")
+//		errortest.AssertStackContains(t, err, "store.(*Store).GetUser")
+//	}
+package errortest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/peakle/errors"
+)
+
+// AssertIs asserts that errors.Is(err, target) reports true.
+func AssertIs(t testing.TB, err, target error) bool {
+	t.Helper()
+	if errors.Is(err, target) {
+		return true
+	}
+	t.Errorf("error does not match target %v\n%s", target, Dump(err))
+	return false
+}
+
+// AssertCode asserts that errors.Code(err) returns code.
+func AssertCode(t testing.TB, err error, code string) bool {
+	t.Helper()
+	if got := errors.Code(err); got != code {
+		t.Errorf("error code: got %q, want %q\n%s", got, code, Dump(err))
+		return false
+	}
+	return true
+}
+
+// AssertField asserts that err carries a field with the supplied key, as
+// reported by errors.Fields, whose value is deeply equal to value.
+func AssertField(t testing.TB, err error, key string, value interface{}) bool {
+	t.Helper()
+	got, ok := errors.Fields(err)[key]
+	switch {
+	case !ok:
+		t.Errorf("error field %q: missing, want %#v\n%s", key, value, Dump(err))
+	case !reflect.DeepEqual(got, value):
+		t.Errorf("error field %q: got %#v, want %#v\n%s", key, got, value, Dump(err))
+	default:
+		return true
+	}
+	return false
+}
+
+// AssertStackContains asserts that a stack trace recorded in err's chain
+// contains a frame of the function fn. fn is either the package
+// path-qualified name of the function, or its trailing part, such as
+// "mypkg.MyFunc" or "mypkg.(*T).Method".
+func AssertStackContains(t testing.TB, err error, fn string) bool {
+	t.Helper()
+	type stackTracer interface {
+		StackTrace() errors.StackTrace
+	}
+
+	found := false
+	errors.Walk(err, func(err error) bool {
+		if st, ok := err.(stackTracer); ok {
+			for _, f := range st.StackTrace() {
+				if name := f.Func(); name == fn || strings.HasSuffix(name, "/"+fn) || strings.HasSuffix(name, "."+fn) {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	if !found {
+		t.Errorf("error stack trace does not contain %s\n%s", fn, Dump(err))
+	}
+	return found
+}
+
+// Dump returns a description of every layer of err's chain, one per line,
+// as printed by the assertions when they fail.
+func Dump(err error) string {
+	if err == nil {
+		return "error chain: <nil>"
+	}
+	var b strings.Builder
+	b.WriteString("error chain:")
+	for i, e := range errors.Chain(err) {
+		fmt.Fprintf(&b, "\n\t%d: %T: %q", i, e, e.Error())
+	}
+	if code := errors.Code(err); code != "" {
+		fmt.Fprintf(&b, "\n\tcode: %s", code)
+	}
+	if kind := errors.KindOf(err); kind != errors.Unknown {
+		fmt.Fprintf(&b, "\n\tkind: %s", kind)
+	}
+	if fields := errors.Fields(err); len(fields) > 0 {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("\n\tfields:")
+		for _, k := range keys {
+			fmt.Fprintf(&b, " %s=%v", k, fields[k])
+		}
+	}
+	return b.String()
+}
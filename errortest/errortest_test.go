@@ -0,0 +1,81 @@
+package errortest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/peakle/errors"
+)
+
+// recorder is a testing.TB that records failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func newErr() error {
+	return errors.WithField(errors.WithCode(errors.Wrap(io.EOF, "read"), "READ"), "tenant", "acme")
+}
+
+func TestAssertions(t *testing.T) {
+	err := newErr()
+	tests := []struct {
+		assert func(t testing.TB) bool
+		want   bool
+	}{
+		{func(t testing.TB) bool { return AssertIs(t, err, io.EOF) }, true},
+		{func(t testing.TB) bool { return AssertIs(t, err, io.ErrUnexpectedEOF) }, false},
+		{func(t testing.TB) bool { return AssertCode(t, err, "READ") }, true},
+		{func(t testing.TB) bool { return AssertCode(t, err, "WRITE") }, false},
+		{func(t testing.TB) bool { return AssertField(t, err, "tenant", "acme") }, true},
+		{func(t testing.TB) bool { return AssertField(t, err, "tenant", "other") }, false},
+		{func(t testing.TB) bool { return AssertField(t, err, "user", "acme") }, false},
+		{func(t testing.TB) bool { return AssertStackContains(t, err, "errortest.newErr") }, true},
+		{func(t testing.TB) bool { return AssertStackContains(t, err, "newErr") }, true},
+		{func(t testing.TB) bool {
+			return AssertStackContains(t, err, "github.com/peakle/errors/errortest.newErr")
+		}, true},
+		{func(t testing.TB) bool { return AssertStackContains(t, err, "errortest.missing") }, false},
+		{func(t testing.TB) bool { return AssertStackContains(t, io.EOF, "errortest.newErr") }, false},
+	}
+
+	for i, tt := range tests {
+		r := &recorder{TB: t}
+		if got := tt.assert(r); got != tt.want {
+			t.Errorf("test %d: got %v, want %v", i+1, got, tt.want)
+		}
+		if failed := len(r.failures) > 0; failed == tt.want {
+			t.Errorf("test %d: reported failures %q, want failure %v", i+1, r.failures, !tt.want)
+		}
+		for _, f := range r.failures {
+			if !strings.Contains(f, "error chain:") {
+				t.Errorf("test %d: failure %q does not dump the error chain", i+1, f)
+			}
+		}
+	}
+}
+
+func TestDump(t *testing.T) {
+	want := "error chain:\n" +
+		"\t0: *errors.withFields: \"read: EOF\"\n" +
+		"\t1: *errors.withCode: \"read: EOF\"\n" +
+		"\t2: *errors.withStack: \"read: EOF\"\n" +
+		"\t3: *errors.withMessage: \"read: EOF\"\n" +
+		"\t4: *errors.errorString: \"EOF\"\n" +
+		"\tcode: READ\n" +
+		"\tfields: tenant=acme"
+	if got := Dump(newErr()); got != want {
+		t.Errorf("Dump(): got %q, want %q", got, want)
+	}
+	if got, want := Dump(nil), "error chain: <nil>"; got != want {
+		t.Errorf("Dump(nil): got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// maxExecStderr is the number of trailing bytes of the standard error of a
+// command kept by WrapExec.
+const maxExecStderr = 4 << 10
+
+// WrapExec returns an error annotating err, returned by running cmd, with
+// the message "exec <program>", a stack trace at the point WrapExec is
+// called, and the fields:
+//
+//	cmd        the command line of cmd, redacted by the redactor
+//	exit_code  the exit code of the process, if it exited
+//	stderr     the last 4 KiB of stderr, the standard error of the process
+//
+// If stderr is nil, the standard error captured by cmd.Output, if any, is
+// used. Fields which are not known are left out. If err is nil, WrapExec
+// returns nil.
+func WrapExec(err error, cmd *exec.Cmd, stderr []byte) error {
+	if err == nil {
+		return nil
+	}
+	msg := "exec"
+	var fields []field
+	if cmd != nil && len(cmd.Args) > 0 {
+		msg += " " + cmd.Args[0]
+		fields = append(fields, field{key: "cmd", value: redact(commandLine(cmd.Args))})
+	}
+	var exit *exec.ExitError
+	if As(err, &exit) {
+		if code := exit.ExitCode(); code >= 0 {
+			fields = append(fields, field{key: "exit_code", value: code})
+		}
+		if stderr == nil {
+			stderr = exit.Stderr
+		}
+	}
+	if len(stderr) > 0 {
+		fields = append(fields, field{key: "stderr", value: tail(string(stderr), maxExecStderr)})
+	}
+
+	var w error = &withStack{&withMessage{cause: err, msg: msg}, callers()}
+	if len(fields) > 0 {
+		w = &withFields{cause: w, fields: fields}
+	}
+	return created(transform(err, w))
+}
+
+// commandLine returns args as a command line, quoting the arguments which
+// are empty or hold spaces or quotes.
+func commandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// tail returns the last n bytes of s, preceded by "..." if s is longer,
+// trimmed of surrounding whitespace.
+func tail(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"io"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapExecNil(t *testing.T) {
+	if got := WrapExec(nil, exec.Command("true"), nil); got != nil {
+		t.Errorf("WrapExec(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestWrapExec(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	cmd := exec.Command(sh, "-c", "echo oops >&2; exit 3")
+	_, runErr := cmd.Output()
+
+	err = WrapExec(runErr, cmd, nil)
+	if got, want := err.Error(), sh+": exit status 3"; !strings.HasSuffix(got, want) || !strings.HasPrefix(got, "exec ") {
+		t.Errorf("WrapExec(): got %q, want %q", got, "exec "+want)
+	}
+	want := map[string]interface{}{
+		"cmd":       sh + ` -c "echo oops >&2; exit 3"`,
+		"exit_code": 3,
+		"stderr":    "oops",
+	}
+	if got := Fields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields(): got %v, want %v", got, want)
+	}
+	var exit *exec.ExitError
+	if !As(err, &exit) {
+		t.Errorf("As(err, *exec.ExitError): got false, want true")
+	}
+}
+
+func TestWrapExecRedacted(t *testing.T) {
+	SetRedactor(func(s string) string { return strings.Replace(s, "hunter2", "***", -1) })
+	defer SetRedactor(nil)
+
+	cmd := exec.Command("login", "--password", "hunter2")
+	err := WrapExec(io.EOF, cmd, []byte(strings.Repeat("x", 5000)))
+	fields := Fields(err)
+	if got, want := fields["cmd"], "login --password ***"; got != want {
+		t.Errorf("Fields()[cmd]: got %q, want %q", got, want)
+	}
+	if _, ok := fields["exit_code"]; ok {
+		t.Errorf("Fields()[exit_code]: got %v, want none", fields["exit_code"])
+	}
+	if got := fields["stderr"].(string); len(got) != maxExecStderr+3 || !strings.HasPrefix(got, "...") {
+		t.Errorf("Fields()[stderr]: got %d bytes, want the last %d", len(got), maxExecStderr)
+	}
+}
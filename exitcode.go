@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// WithExitCode annotates err with the exit status a program failing with it
+// should exit with, as returned by ExitCode.
+// If err is nil, WithExitCode returns nil.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withExitCode{
+		cause: err,
+		code:  code,
+	}))
+}
+
+// ExitCode returns the exit status for a program failing with err: the
+// outermost exit code in err's chain, or 1 if there is none. ExitCode
+// returns 0 if err is nil. An error value carries an exit code if it
+// implements the following interface and returns a code of zero or more,
+// as do the errors of WithExitCode and *exec.ExitError:
+//
+//	type exitCoder interface {
+//	       ExitCode() int
+//	}
+func ExitCode(err error) int {
+	type exitCoder interface {
+		ExitCode() int
+	}
+
+	if err == nil {
+		return 0
+	}
+	var c cycle
+	for e := err; e != nil && !c.seen(e); e = Unwrap(e) {
+		if x, ok := e.(exitCoder); ok && x.ExitCode() >= 0 {
+			return x.ExitCode()
+		}
+	}
+	return 1
+}
+
+// exit and stderr are replaced by tests of HandleMain.
+var (
+	exit             = os.Exit
+	stderr io.Writer = os.Stderr
+)
+
+// HandleMain ends a command line program failing with err: it prints err
+// to the standard error, in detail as by %+v if verbose is true, and exits
+// with the status returned by ExitCode. If err is nil, HandleMain returns
+// without doing anything, leaving main to return normally:
+//
+//	func main() {
+//		verbose := flag.Bool("v", false, "print errors in detail")
+//		flag.Parse()
+//		errors.HandleMain(run(), *verbose)
+//	}
+func HandleMain(err error, verbose bool) {
+	if err == nil {
+		return
+	}
+	if verbose {
+		fmt.Fprintf(stderr, "%+v\n", err)
+	} else {
+		fmt.Fprintf(stderr, "%v\n", err)
+	}
+	exit(ExitCode(err))
+}
+
+type withExitCode struct {
+	cause error
+	code  int
+}
+
+func (w *withExitCode) Error() string { return redact(w.cause.Error()) }
+func (w *withExitCode) Cause() error  { return w.cause }
+func (w *withExitCode) ExitCode() int { return w.code }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withExitCode) Unwrap() error { return w.cause }
+
+func (w *withExitCode) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, "exit_code="+strconv.Itoa(w.code))
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
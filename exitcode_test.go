@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestWithExitCodeNil(t *testing.T) {
+	if got := WithExitCode(nil, 2); got != nil {
+		t.Errorf("WithExitCode(nil, 2): got %#v, expected nil", got)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{io.EOF, 1},
+		{WithExitCode(io.EOF, 2), 2},
+		{Wrap(WithExitCode(io.EOF, 64), "parse flags"), 64},
+		{WithExitCode(WithExitCode(io.EOF, 3), 4), 4},
+		{WithExitCode(io.EOF, -1), 1},
+	}
+
+	for i, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("test %d: ExitCode(%v): got %d, want %d", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if sh, err := exec.LookPath("sh"); err == nil {
+		err := Wrap(exec.Command(sh, "-c", "exit 7").Run(), "run hook")
+		if got := ExitCode(err); got != 7 {
+			t.Errorf("ExitCode(*exec.ExitError): got %d, want 7", got)
+		}
+	}
+}
+
+func TestHandleMain(t *testing.T) {
+	var out bytes.Buffer
+	code := -1
+	oldStderr, oldExit := stderr, exit
+	stderr, exit = &out, func(c int) { code = c }
+	defer func() { stderr, exit = oldStderr, oldExit }()
+
+	HandleMain(nil, true)
+	if out.Len() > 0 || code != -1 {
+		t.Errorf("HandleMain(nil): got output %q and exit %d, want none", out.String(), code)
+	}
+
+	HandleMain(WithExitCode(Wrap(io.EOF, "read config"), 3), false)
+	if got := out.String(); got != "read config: EOF\n" || code != 3 {
+		t.Errorf("HandleMain(): got output %q and exit %d, want %q and 3", got, code, "read config: EOF\n")
+	}
+
+	out.Reset()
+	HandleMain(Wrap(io.EOF, "read config"), true)
+	if got := out.String(); !strings.Contains(got, "errors.TestHandleMain\n") || code != 1 {
+		t.Errorf("HandleMain() verbose: got output %q and exit %d, want the stack trace and 1", got, code)
+	}
+}
@@ -0,0 +1,197 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// WithField annotates err with a key/value pair of structured context.
+// If err is nil, WithField returns nil.
+func WithField(err error, key string, value interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withFields{
+		cause:  err,
+		fields: []field{{key: key, value: value}},
+	}))
+}
+
+// WithFields annotates err with the supplied key/value pairs.
+// If err is nil, WithFields returns nil.
+func WithFields(err error, fields map[string]interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, newWithFields(err, fields)))
+}
+
+// newWithFields returns err annotated with fields, in key order.
+func newWithFields(err error, fields map[string]interface{}) *withFields {
+	w := &withFields{
+		cause:  err,
+		fields: make([]field, 0, len(fields)),
+	}
+	for k, v := range fields {
+		w.fields = append(w.fields, field{key: k, value: v})
+	}
+	sort.Slice(w.fields, func(i, j int) bool { return w.fields[i].key < w.fields[j].key })
+	return w
+}
+
+// WithSensitiveField annotates err with a key/value pair whose value must
+// never be rendered. The value is replaced by "[REDACTED]" wherever the
+// field is formatted or returned by Fields.
+// If err is nil, WithSensitiveField returns nil.
+func WithSensitiveField(err error, key string, value interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withFields{
+		cause:  err,
+		fields: []field{{key: key, value: value, sensitive: true}},
+	}))
+}
+
+// Fields returns the fields attached to every error in err's chain.
+// When the same key is attached more than once, the outermost value wins.
+// Fields returns nil if err carries no fields.
+func Fields(err error) map[string]interface{} {
+	type fielder interface {
+		fieldList() []field
+	}
+
+	var fields map[string]interface{}
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if f, ok := err.(fielder); ok {
+			for _, fl := range f.fieldList() {
+				if fields == nil {
+					fields = make(map[string]interface{})
+				}
+				if _, ok := fields[fl.key]; !ok {
+					fields[fl.key] = fl.display()
+				}
+			}
+		}
+		err = Unwrap(err)
+	}
+	return fields
+}
+
+var reporterInfo atomic.Value // of map[string]interface{}
+
+// SetReporterInfo sets fields merged into the fields of every error where
+// errors are exported to central systems, by the JSON style, Logfmt, the
+// handler returned by NewSlogHandler and WriteReport, to identify the
+// process reporting them without annotating errors at every call site:
+//
+//	host, _ := os.Hostname()
+//	errors.SetReporterInfo("billing", map[string]string{
+//		"host": host,
+//		"pid":  strconv.Itoa(os.Getpid()),
+//	})
+//
+// service is set as the "service" field, unless empty. Fields attached to an
+// error take precedence over those of the same key set by SetReporterInfo.
+// The fields are not attached to errors: Fields does not return them, and
+// ToRPCError does not send them. Calling SetReporterInfo with an empty service and no extra fields removes
+// them.
+func SetReporterInfo(service string, extra map[string]string) {
+	info := make(map[string]interface{}, len(extra)+1)
+	for k, v := range extra {
+		info[k] = v
+	}
+	if service != "" {
+		info["service"] = service
+	}
+	reporterInfo.Store(info)
+}
+
+func loadReporterInfo() map[string]interface{} {
+	info, _ := reporterInfo.Load().(map[string]interface{})
+	return info
+}
+
+// exportFields returns the fields of err, as returned by Fields, merged over
+// those set by SetReporterInfo. It returns nil if err is nil or there are no
+// fields.
+func exportFields(err error) map[string]interface{} {
+	fields := Fields(err)
+	info := loadReporterInfo()
+	if err == nil || len(info) == 0 {
+		return fields
+	}
+	merged := make(map[string]interface{}, len(info)+len(fields))
+	for k, v := range info {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// field is a single key/value pair attached to an error.
+type field struct {
+	key       string
+	value     interface{}
+	sensitive bool
+}
+
+// display returns the value of the field as it may be shown to the outside
+// world.
+func (f field) display() interface{} {
+	if _, ok := f.value.(secret); ok || f.sensitive {
+		return redactedValue
+	}
+	return f.value
+}
+
+// String returns the field rendered as key=value.
+func (f field) String() string {
+	return f.key + "=" + redact(fmt.Sprint(f.display()))
+}
+
+type withFields struct {
+	cause  error
+	fields []field
+}
+
+func (w *withFields) Error() string      { return redact(w.cause.Error()) }
+func (w *withFields) Cause() error       { return w.cause }
+func (w *withFields) fieldList() []field { return w.fields }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withFields) Unwrap() error { return w.cause }
+
+func (w *withFields) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, w.fieldString())
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// fieldString renders the fields of w as space separated key=value pairs.
+func (w *withFields) fieldString() string {
+	parts := make([]string, len(w.fields))
+	for i, f := range w.fields {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, " ")
+}
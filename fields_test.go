@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestWithFieldNil(t *testing.T) {
+	if got := WithField(nil, "key", "value"); got != nil {
+		t.Errorf("WithField(nil, \"key\", \"value\"): got %#v, expected nil", got)
+	}
+	if got := WithFields(nil, map[string]interface{}{"key": "value"}); got != nil {
+		t.Errorf("WithFields(nil, ...): got %#v, expected nil", got)
+	}
+	if got := WithSensitiveField(nil, "key", "value"); got != nil {
+		t.Errorf("WithSensitiveField(nil, \"key\", \"value\"): got %#v, expected nil", got)
+	}
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		err  error
+		want map[string]interface{}
+	}{
+		{nil, nil},
+		{io.EOF, nil},
+		{WithField(io.EOF, "a", 1), map[string]interface{}{"a": 1}},
+		{WithField(WithField(io.EOF, "a", 1), "a", 2), map[string]interface{}{"a": 2}},
+		{Wrap(WithFields(io.EOF, map[string]interface{}{"a": 1, "b": "x"}), "read"), map[string]interface{}{"a": 1, "b": "x"}},
+		{WithSensitiveField(io.EOF, "token", "secret"), map[string]interface{}{"token": "[REDACTED]"}},
+	}
+
+	for i, tt := range tests {
+		got := Fields(tt.err)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: Fields(): got %#v, want %#v", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestFormatWithFields(t *testing.T) {
+	tests := []struct {
+		error
+		format string
+		want   string
+	}{{
+		WithField(io.EOF, "a", 1),
+		"%s",
+		"EOF",
+	}, {
+		WithField(io.EOF, "a", 1),
+		"%v",
+		"EOF",
+	}, {
+		WithFields(io.EOF, map[string]interface{}{"b": 2, "a": 1}),
+		"%+v",
+		"EOF\na=1 b=2",
+	}, {
+		WithSensitiveField(io.EOF, "token", "secret"),
+		"%+v",
+		"EOF\ntoken=[REDACTED]",
+	}, {
+		WithField(io.EOF, "a", 1),
+		"%q",
+		`"EOF"`,
+	}}
+
+	for i, tt := range tests {
+		got := fmt.Sprintf(tt.format, tt.error)
+		if got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%q, err): got: %q, want: %q", i+1, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestSetReporterInfo(t *testing.T) {
+	defer SetReporterInfo("", nil)
+	SetReporterInfo("billing", map[string]string{"host": "web-1", "region": "eu"})
+
+	tests := []struct {
+		err  error
+		want map[string]interface{}
+	}{
+		{nil, nil},
+		{io.EOF, map[string]interface{}{"service": "billing", "host": "web-1", "region": "eu"}},
+		{WithField(io.EOF, "region", "us"), map[string]interface{}{"service": "billing", "host": "web-1", "region": "us"}},
+		{WithField(WithField(io.EOF, "user", "a"), "user", "b"), map[string]interface{}{"service": "billing", "host": "web-1", "region": "eu", "user": "b"}},
+	}
+	for i, tt := range tests {
+		if got := exportFields(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: exportFields(): got %v, want %v", i+1, got, tt.want)
+		}
+	}
+	if got := Fields(io.EOF); got != nil {
+		t.Errorf("Fields(io.EOF): got %v, want nil", got)
+	}
+	if got := Logfmt(io.EOF); got != "msg=EOF host=web-1 region=eu service=billing" {
+		t.Errorf("Logfmt(io.EOF): got %q", got)
+	}
+
+	SetReporterInfo("", nil)
+	if got := exportFields(io.EOF); got != nil {
+		t.Errorf("exportFields(io.EOF) after reset: got %v, want nil", got)
+	}
+}
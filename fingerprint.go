@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// fingerprintFrames is the number of application frames of the originating
+// stack trace that contribute to a fingerprint.
+const fingerprintFrames = 3
+
+// volatile matches the parts of an error message that are likely to vary
+// between occurrences of the same failure: quoted strings, UUIDs,
+// hexadecimal and decimal numbers.
+var volatile = regexp.MustCompile(`"[^"]*"|'[^']*'|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|0[xX][0-9a-fA-F]+|\d+`)
+
+// Fingerprint returns a stable identifier for the failure err represents,
+// suitable for grouping identical failures across processes and hosts.
+//
+// The fingerprint is derived from the message of the root cause with
+// volatile data such as numbers, identifiers and quoted strings removed, the
+// error code, if any, and the names of the top application functions of the
+// stack trace recorded closest to the root cause. Line numbers do not
+// contribute, so a fingerprint survives unrelated edits to the source file.
+// Fingerprint returns the empty string if err is nil.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	h := sha256.New()
+	io.WriteString(h, normalizedMessage(err))
+	io.WriteString(h, "\x00")
+	io.WriteString(h, Code(err))
+	for _, name := range topFunctions(err) {
+		io.WriteString(h, "\x00")
+		io.WriteString(h, name)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// normalizedMessage returns the message of the root cause of err with its
+// volatile parts replaced by "?".
+func normalizedMessage(err error) string {
+	return volatile.ReplaceAllString(Root(err).Error(), "?")
+}
+
+// topFunctions returns the names of the top application functions of the
+// stack trace recorded closest to the root cause of err, which contribute to
+// its fingerprint.
+func topFunctions(err error) []string {
+	var names []string
+	for _, f := range originStack(err) {
+		if len(names) == fingerprintFrames {
+			break
+		}
+		if name := f.name(); !isStdlib(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// originStack returns the stack trace recorded closest to the root cause of
+// err, or nil if err's chain carries no stack trace.
+func originStack(err error) StackTrace {
+	type stackTracer interface {
+		StackTrace() StackTrace
+	}
+
+	var st StackTrace
+	var c cycle
+	for ; err != nil && !c.seen(err); err = Unwrap(err) {
+		if s, ok := err.(stackTracer); ok {
+			st = s.StackTrace()
+		}
+	}
+	return st
+}
+
+// isStdlib reports whether the function name belongs to the standard library,
+// whose import paths, unlike those of modules, have no dot in their first
+// element.
+func isStdlib(name string) bool {
+	if name == "unknown" {
+		return true
+	}
+	path := pkgPath(name)
+	if path == "main" {
+		return false
+	}
+	if i := strings.Index(path, "/"); i >= 0 {
+		path = path[:i]
+	}
+	return !strings.Contains(path, ".")
+}
+
+// pkgPath returns the import path of the package of the function name
+// reported by func.Name().
+func pkgPath(name string) string {
+	i := strings.LastIndex(name, "/")
+	j := strings.Index(name[i+1:], ".")
+	if j < 0 {
+		return name
+	}
+	return name[:i+1+j]
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func fingerprintSite(id int) error {
+	return Errorf("user %d not found", id)
+}
+
+func TestFingerprint(t *testing.T) {
+	if got := Fingerprint(nil); got != "" {
+		t.Errorf("Fingerprint(nil): got %q, want \"\"", got)
+	}
+
+	a := Fingerprint(fingerprintSite(1))
+	if len(a) != 16 {
+		t.Errorf("Fingerprint(): got %q, want 16 hex digits", a)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		same bool
+	}{
+		{"different argument", fingerprintSite(42), true},
+		{"wrapped", Wrap(fingerprintSite(7), "load user"), true},
+		{"wrapped with std", fmt.Errorf("load: %w", fingerprintSite(7)), true},
+		{"different code", WithCode(fingerprintSite(1), "NOT_FOUND"), false},
+		{"different site", Errorf("user %d not found", 1), false},
+		{"different message", io.EOF, false},
+	}
+
+	for _, tt := range tests {
+		if got := Fingerprint(tt.err) == a; got != tt.same {
+			t.Errorf("%s: Fingerprint() equal: got %v, want %v", tt.name, got, tt.same)
+		}
+	}
+}
+
+func TestIsStdlib(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"runtime.goexit", true},
+		{"testing.tRunner", true},
+		{"net/http.(*conn).serve", true},
+		{"unknown", true},
+		{"main.main", false},
+		{"github.com/peakle/errors.TestIsStdlib", false},
+		{"github.com/peakle/errors.(*withStack).Format", false},
+		{"example.com/app.v2.Run", false},
+	}
+
+	for _, tt := range tests {
+		if got := isStdlib(tt.name); got != tt.want {
+			t.Errorf("isStdlib(%q): got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package errors
+
+import "sync/atomic"
+
+// A FirstError records the first non-nil error set by any of the goroutines
+// sharing it, as the workers of a pool do when a single failure decides the
+// outcome of the whole task:
+//
+//	var first errors.FirstError
+//	for _, item := range items {
+//		go func(item Item) {
+//			defer wg.Done()
+//			first.Set(process(item))
+//		}(item)
+//	}
+//	wg.Wait()
+//	return first.Err()
+//
+// The error is kept as is, with the stack trace it carries. A zero
+// FirstError is ready to use and holds no error, as does a nil *FirstError,
+// which records nothing. A FirstError must not be copied after first use.
+type FirstError struct {
+	p atomic.Pointer[firstError]
+}
+
+// firstError boxes the error recorded by a FirstError.
+type firstError struct {
+	err error
+}
+
+// Set records err if it is the first non-nil error set on f, and reports
+// whether it did. It is safe for concurrent use, and does not block.
+func (f *FirstError) Set(err error) bool {
+	if err == nil || f == nil {
+		return false
+	}
+	return f.p.CompareAndSwap(nil, &firstError{err})
+}
+
+// Err returns the first non-nil error set on f, or nil if none has been.
+func (f *FirstError) Err() error {
+	if f == nil {
+		return nil
+	}
+	if b := f.p.Load(); b != nil {
+		return b.err
+	}
+	return nil
+}
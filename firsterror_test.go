@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestFirstError(t *testing.T) {
+	var f FirstError
+	if err := f.Err(); err != nil {
+		t.Errorf("zero FirstError: got %v, want nil", err)
+	}
+	if f.Set(nil) || f.Err() != nil {
+		t.Errorf("Set(nil): recorded nil")
+	}
+	if !f.Set(io.EOF) || f.Err() != io.EOF {
+		t.Errorf("Set(io.EOF): got %v, want io.EOF", f.Err())
+	}
+	if f.Set(io.ErrUnexpectedEOF) || f.Err() != io.EOF {
+		t.Errorf("second Set: got %v, want io.EOF", f.Err())
+	}
+}
+
+func TestFirstErrorConcurrent(t *testing.T) {
+	var f FirstError
+	var wg sync.WaitGroup
+	errs := make([]error, 64)
+	var set [64]bool
+	for i := range errs {
+		errs[i] = New("error")
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			set[i] = f.Set(errs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	n := 0
+	for i, ok := range set {
+		if ok {
+			n++
+			if f.Err() != errs[i] {
+				t.Errorf("Err(): got %v, want the error whose Set reported true", f.Err())
+			}
+		}
+	}
+	if n != 1 {
+		t.Errorf("Set reported true %d times, want once", n)
+	}
+}
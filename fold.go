@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultFoldSeparator is the separator Fold replaces line breaks with
+// unless set otherwise by SetFoldSeparator: a backslash followed by n, as
+// in a Go string literal.
+const DefaultFoldSeparator = `\n`
+
+var foldSeparator atomic.Value // of string
+
+// SetFoldSeparator sets the separator Fold replaces line breaks with. An
+// empty separator restores DefaultFoldSeparator.
+func SetFoldSeparator(sep string) { foldSeparator.Store(sep) }
+
+func loadFoldSeparator() string {
+	if sep, _ := foldSeparator.Load().(string); sep != "" {
+		return sep
+	}
+	return DefaultFoldSeparator
+}
+
+// Fold returns the detailed form of err, as printed by %+v with its whole
+// chain and stack traces, folded into a single line for transports such as
+// syslog and journald that split records on line breaks:
+//
+//	EOF\nread config\ngithub.com/app.loadConfig\n /src/app/config.go:42\n...
+//
+// Line breaks are replaced by the separator set with SetFoldSeparator, and
+// the tabs indenting the file of each frame by a space. The separator is
+// escaped with a backslash wherever the text itself contains it, so that the
+// lines can be told apart. Fold returns "" if err is nil.
+func Fold(err error) string {
+	if err == nil {
+		return ""
+	}
+	return fold(fmt.Sprintf("%+v", err), loadFoldSeparator())
+}
+
+// fold returns text with its line breaks replaced by sep.
+func fold(text, sep string) string {
+	text = strings.TrimRight(text, "\r\n")
+	var b strings.Builder
+	b.Grow(len(text))
+	for len(text) > 0 {
+		switch {
+		case strings.HasPrefix(text, sep):
+			b.WriteByte('\\')
+			b.WriteString(sep)
+			text = text[len(sep):]
+			continue
+		case strings.HasPrefix(text, "\r\n"):
+			b.WriteString(sep)
+			text = text[2:]
+			continue
+		case text[0] == '\n' || text[0] == '\r':
+			b.WriteString(sep)
+		case text[0] == '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(text[0])
+		}
+		text = text[1:]
+	}
+	return b.String()
+}
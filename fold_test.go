@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		text, sep, want string
+	}{
+		{"", `\n`, ""},
+		{"EOF", `\n`, "EOF"},
+		{"EOF\nread\n", `\n`, `EOF\nread`},
+		{"EOF\r\nread", `\n`, `EOF\nread`},
+		{"pkg.Fn\n\tfile.go:42", `\n`, `pkg.Fn\n file.go:42`},
+		{`quoted "a\nb"` + "\nread", `\n`, `quoted "a\\nb"\nread`},
+		{"a | b\nc", " | ", `a\ | b | c`},
+	}
+
+	for i, tt := range tests {
+		if got := fold(tt.text, tt.sep); got != tt.want {
+			t.Errorf("test %d: fold(%q, %q): got %q, want %q", i+1, tt.text, tt.sep, got, tt.want)
+		}
+	}
+}
+
+func TestFoldError(t *testing.T) {
+	if got := Fold(nil); got != "" {
+		t.Errorf("Fold(nil): got %q, want %q", got, "")
+	}
+
+	err := Wrap(io.EOF, "read config")
+	got := Fold(err)
+	if strings.ContainsAny(got, "\r\n") {
+		t.Errorf("Fold(): got %q, want a single line", got)
+	}
+	if want := `EOF\nread config\ngithub.com/peakle/errors.TestFoldError\n `; !strings.HasPrefix(got, want) {
+		t.Errorf("Fold(): got %q, want prefix %q", got, want)
+	}
+
+	SetFoldSeparator(" ¶ ")
+	defer SetFoldSeparator("")
+	if got, want := Fold(err), "EOF ¶ read config ¶ "; !strings.HasPrefix(got, want) {
+		t.Errorf("Fold() with separator: got %q, want prefix %q", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package errors
+
+import "sync"
+
+// IndexField is the field key under which ForEach records the index of the
+// item whose processing failed.
+const IndexField = "index"
+
+// ForEach calls fn for every item of items, running at most concurrency calls
+// at once; a concurrency of zero or less runs every call at once. A panic in
+// fn is recovered and treated as an error, as with Recover.
+//
+// ForEach waits for every call to return, then returns the errors they
+// produced joined as by Join, in the order of the items, each annotated with
+// the item's index as the field IndexField. It returns nil if every call
+// succeeded.
+func ForEach[T any](items []T, concurrency int, fn func(T) error) error {
+	if concurrency <= 0 || concurrency > len(items) {
+		concurrency = len(items)
+	}
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, item T) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := call(func() error { return fn(item) }); err != nil {
+				errs[i] = WithField(err, IndexField, i)
+			}
+		}(i, item)
+	}
+	wg.Wait()
+	return Join(errs...)
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEach(t *testing.T) {
+	if err := ForEach([]int(nil), 2, func(int) error { return nil }); err != nil {
+		t.Errorf("ForEach(nil): got %v, want nil", err)
+	}
+
+	items := []int{0, 1, 2, 3, 4, 5}
+	err := ForEach(items, 2, func(i int) error {
+		switch i {
+		case 1:
+			return fmt.Errorf("item %d failed", i)
+		case 4:
+			panic("boom")
+		}
+		return nil
+	})
+
+	errs := err.(*joinError).errs
+	if len(errs) != 2 {
+		t.Fatalf("ForEach(): got %d errors, want 2: %v", len(errs), err)
+	}
+	tests := []struct {
+		index int
+		msg   string
+	}{
+		{1, "item 1 failed"},
+		{4, "panic: boom"},
+	}
+	for i, tt := range tests {
+		if got := errs[i].Error(); got != tt.msg {
+			t.Errorf("error %d: got %q, want %q", i+1, got, tt.msg)
+		}
+		if got := Fields(errs[i])[IndexField]; got != tt.index {
+			t.Errorf("error %d: index field: got %v, want %d", i+1, got, tt.index)
+		}
+	}
+}
+
+func TestForEachConcurrency(t *testing.T) {
+	var active, peak int32
+	items := make([]int, 20)
+	ForEach(items, 3, func(int) error {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&active, -1)
+		return nil
+	})
+	if peak > 3 {
+		t.Errorf("ForEach(concurrency 3): %d calls ran at once", peak)
+	}
+}
@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// A Formatter writes the details of an error to w, for the %+v verb.
+type Formatter func(w io.Writer, err error)
+
+// WithFormatter annotates err with f, which renders details of err under
+// the %+v verb, such as a table of the fields that failed validation. The
+// rest of the chain, and the stack traces it records, are printed first as
+// usual, followed by the output of f on the next line:
+//
+//	err = errors.WithFormatter(err, func(w io.Writer, err error) {
+//		for i, v := range violations {
+//			if i > 0 {
+//				io.WriteString(w, "\n")
+//			}
+//			fmt.Fprintf(w, "\t%-10s %s", v.Field, v.Reason)
+//		}
+//	})
+//
+// The output of f is redacted like any other output of this package. The %s,
+// %v and %q verbs are not affected.
+// If err is nil, WithFormatter returns nil. If f is nil, WithFormatter
+// returns err unchanged.
+func WithFormatter(err error, f Formatter) error {
+	if err == nil || f == nil {
+		return err
+	}
+	return created(transform(err, &withFormatter{
+		cause:     err,
+		formatter: f,
+	}))
+}
+
+type withFormatter struct {
+	cause     error
+	formatter Formatter
+}
+
+func (w *withFormatter) Error() string { return redact(w.cause.Error()) }
+func (w *withFormatter) Cause() error  { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withFormatter) Unwrap() error { return w.cause }
+
+func (w *withFormatter) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			var b bytes.Buffer
+			w.formatter(&b, w.cause)
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, redact(b.String()))
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
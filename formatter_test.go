@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestWithFormatterNil(t *testing.T) {
+	if got := WithFormatter(nil, func(io.Writer, error) {}); got != nil {
+		t.Errorf("WithFormatter(nil, f): got %#v, expected nil", got)
+	}
+	err := New("error")
+	if got := WithFormatter(err, nil); got != err {
+		t.Errorf("WithFormatter(err, nil): got %#v, expected err unchanged", got)
+	}
+}
+
+func TestFormatWithFormatter(t *testing.T) {
+	f := NewFrame("app.validate", "/src/app/validate.go", 9)
+	err := WithFormatter(&withStack{fmt.Errorf("invalid user"), &stack{pcs: []uintptr{uintptr(f)}}}, func(w io.Writer, err error) {
+		fmt.Fprintf(w, "\tname   required\n\temail  malformed (cause %q)", err.Error())
+	})
+
+	for format, want := range map[string]string{
+		"%s": "invalid user",
+		"%v": "invalid user",
+		"%q": `"invalid user"`,
+		"%+v": "invalid user\napp.validate\n\t/src/app/validate.go:9\n" +
+			"\tname   required\n\temail  malformed (cause \"invalid user\")",
+	} {
+		if got := fmt.Sprintf(format, err); got != want {
+			t.Errorf("fmt.Sprintf(%q, err): got %q, want %q", format, got, want)
+		}
+	}
+	if Cause(err).Error() != "invalid user" {
+		t.Errorf("Cause(err): got %v", Cause(err))
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/peakle/errors
 
-go 1.17
+go 1.21
+
+require golang.org/x/xerrors v0.0.0-20240903120638-7835f813f4da
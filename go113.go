@@ -4,6 +4,7 @@ package errors
 
 import (
 	stderrors "errors"
+	"reflect"
 )
 
 // Is reports whether any error in err's chain matches target.
@@ -13,7 +14,30 @@ import (
 //
 // An error is considered to match a target if it is equal to that target or if
 // it implements a method Is(error) bool such that Is(target) returns true.
-func Is(err, target error) bool { return stderrors.Is(err, target) }
+//
+// Unlike the function of the standard library, Is stops at the end of a
+// chain which loops back on itself, as Walk does.
+//
+// Repeated calls with the same error can be sped up by SetChainIndex.
+func Is(err, target error) bool {
+	if err == nil || target == nil {
+		return err == target
+	}
+	if x := chainIndexFor(err); x != nil {
+		return x.is(target)
+	}
+	comparable := reflect.TypeOf(target).Comparable()
+	found := false
+	Walk(err, func(err error) bool {
+		if comparable && err == target {
+			found = true
+		} else if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
 
 // As finds the first error in err's chain that matches target, and if so, sets
 // target to that error value and returns true.
@@ -28,7 +52,44 @@ func Is(err, target error) bool { return stderrors.Is(err, target) }
 //
 // As will panic if target is not a non-nil pointer to either a type that implements
 // error, or to any interface type. As returns false if err is nil.
-func As(err error, target interface{}) bool { return stderrors.As(err, target) }
+//
+// Unlike the function of the standard library, As stops at the end of a
+// chain which loops back on itself, as Walk does.
+//
+// Repeated calls with the same error can be sped up by SetChainIndex.
+func As(err error, target interface{}) bool {
+	if err == nil {
+		return false
+	}
+	if target == nil {
+		panic("errors: target cannot be nil")
+	}
+	val := reflect.ValueOf(target)
+	typ := val.Type()
+	if typ.Kind() != reflect.Ptr || val.IsNil() {
+		panic("errors: target must be a non-nil pointer")
+	}
+	targetType := typ.Elem()
+	if targetType.Kind() != reflect.Interface && !targetType.Implements(errorType) {
+		panic("errors: *target must be interface or implement error")
+	}
+	if indexableTarget(target) {
+		if x := chainIndexFor(err); x != nil {
+			return x.as(target)
+		}
+	}
+	found := false
+	Walk(err, func(err error) bool {
+		if reflect.TypeOf(err).AssignableTo(targetType) {
+			val.Elem().Set(reflect.ValueOf(err))
+			found = true
+		} else if x, ok := err.(interface{ As(interface{}) bool }); ok && x.As(target) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
 
 // Unwrap returns the result of calling the Unwrap method on err, if err's
 // type contains an Unwrap method returning error.
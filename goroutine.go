@@ -0,0 +1,253 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+// Go runs fn in a new goroutine and returns a channel on which the error fn
+// returns is delivered once, after which the channel is closed. A panic in
+// fn is recovered and delivered as an error carrying the stack trace of the
+// panic, as with Recover.
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		ch <- call(fn)
+	}()
+	return ch
+}
+
+// GoHandle runs fn in a new goroutine, without waiting for it to complete.
+// If fn returns a non-nil error or panics, handle is called with the error
+// on that goroutine. A panic is converted to an error as with Recover.
+func GoHandle(fn func() error, handle func(error)) {
+	go func() {
+		if err := call(fn); err != nil {
+			handle(err)
+		}
+	}()
+}
+
+// call calls fn, converting a panic into an error.
+func call(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+// captureGoroutine is non-zero while goroutine IDs are recorded.
+var captureGoroutine int32
+
+// SetGoroutineCapture sets whether errors record, along with their stack
+// trace, the ID of the goroutine that captured it. It is disabled by
+// default, as finding the ID of the current goroutine costs about as much
+// as capturing a stack trace. When enabled, the ID is printed before the
+// frames of the stack trace by %+v, included in the JSON style, and returned
+// by GoroutineID, so that errors from concurrent code can be correlated with
+// execution traces and goroutine dumps.
+//
+// Errors created by NewCtx, ErrorfCtx, WrapCtx, WrapfCtx and WithStackCtx
+// also record the pprof labels carried by their context, as set by
+// pprof.Do, which are printed and included along with the ID.
+func SetGoroutineCapture(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&captureGoroutine, v)
+}
+
+// GoroutineID returns the ID of the goroutine which recorded the stack trace
+// closest to the root cause of err, and whether it is known. The ID is only
+// recorded while enabled by SetGoroutineCapture.
+func GoroutineID(err error) (id int64, ok bool) {
+	if g := goroutineOf(err); g != nil {
+		return g.id, true
+	}
+	return 0, false
+}
+
+// goroutineOf returns the goroutine which recorded the stack trace closest
+// to the root cause of err, or nil if it is not known.
+func goroutineOf(err error) *goroutineInfo {
+	type stackTracer interface {
+		StackTrace() StackTrace
+	}
+
+	var (
+		g *goroutineInfo
+		c cycle
+	)
+	for ; err != nil && !c.seen(err); err = Unwrap(err) {
+		switch e := err.(type) {
+		case interface{ goroutine() *goroutineInfo }:
+			g = e.goroutine()
+		case stackTracer:
+			g = nil
+		}
+	}
+	return g
+}
+
+// goroutineInfo describes the goroutine which recorded a stack trace.
+type goroutineInfo struct {
+	id     int64
+	labels []string // pprof labels, as key, value pairs sorted by key
+}
+
+// writeTo writes g as %+v prints it before the frames of a stack trace, as
+// in "\ngoroutine 7 [labels: handler=login worker=3]".
+func (g *goroutineInfo) writeTo(w io.Writer) {
+	io.WriteString(w, "\ngoroutine "+strconv.FormatInt(g.id, 10))
+	if len(g.labels) == 0 {
+		return
+	}
+	io.WriteString(w, " [labels:")
+	for i := 0; i < len(g.labels); i += 2 {
+		io.WriteString(w, " "+g.labels[i]+"="+g.labels[i+1])
+	}
+	io.WriteString(w, "]")
+}
+
+// labelMap returns the labels of g as a map, or nil if there are none.
+func (g *goroutineInfo) labelMap() map[string]string {
+	if len(g.labels) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(g.labels)/2)
+	for i := 0; i < len(g.labels); i += 2 {
+		m[g.labels[i]] = g.labels[i+1]
+	}
+	return m
+}
+
+// recordGoroutine records the ID of the current goroutine with s, if
+// enabled by SetGoroutineCapture.
+func recordGoroutine(s *stack) {
+	if atomic.LoadInt32(&captureGoroutine) == 0 || len(s.pcs) == 0 {
+		return
+	}
+	if id, ok := currentGoroutine(); ok {
+		s.g = &goroutineInfo{id: id}
+	}
+}
+
+// recordLabels records with the goroutine of s, if recorded, the pprof
+// labels carried by ctx, as set by pprof.WithLabels and pprof.Do. The labels
+// of a goroutine are only readable from the context they were set on, so
+// they are recorded by the constructors taking one.
+func recordLabels(ctx context.Context, s *stack) {
+	if s == nil || s.g == nil {
+		return
+	}
+	var keys []string
+	values := map[string]string{}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		keys = append(keys, key)
+		values[key] = value
+		return true
+	})
+	sort.Strings(keys)
+	for _, k := range keys {
+		s.g.labels = append(s.g.labels, k, values[k])
+	}
+}
+
+// goroutine returns the goroutine which captured s, or nil if not recorded.
+func (s *stack) goroutine() *goroutineInfo {
+	if s == nil {
+		return nil
+	}
+	return s.g
+}
+
+// currentGoroutine returns the ID of the calling goroutine, parsed from the
+// "goroutine 7 [running]:" header of its trace.
+func currentGoroutine() (int64, bool) {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseInt(string(b), 10, 64)
+	return id, err == nil
+}
+
+// maxGoroutineDump is the maximum size in bytes of the goroutine dump
+// recorded by WithAllGoroutines.
+const maxGoroutineDump = 1 << 20
+
+// WithAllGoroutines annotates err with a dump of the stacks of all the
+// goroutines of the process, as written by runtime.Stack, for errors such as
+// deadlocks and timeouts whose cause lies in goroutines other than the one
+// returning the error. The dump is printed by %+v and is limited to 1 MiB;
+// a longer dump is truncated. Taking the dump briefly stops the world.
+// If err is nil, WithAllGoroutines returns nil.
+func WithAllGoroutines(err error) error {
+	if err == nil {
+		return nil
+	}
+	dump, truncated := goroutineDump()
+	return created(transform(err, &withGoroutines{
+		cause:     err,
+		dump:      dump,
+		truncated: truncated,
+	}))
+}
+
+// goroutineDump returns the stacks of all the goroutines of the process, as
+// written by runtime.Stack, limited to maxGoroutineDump bytes, and whether
+// they were truncated to that limit.
+func goroutineDump() (dump []byte, truncated bool) {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxGoroutineDump {
+			return buf[:n], n >= maxGoroutineDump
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+type withGoroutines struct {
+	cause     error
+	dump      []byte
+	truncated bool
+}
+
+func (w *withGoroutines) Error() string { return redact(w.cause.Error()) }
+func (w *withGoroutines) Cause() error  { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withGoroutines) Unwrap() error { return w.cause }
+
+func (w *withGoroutines) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, "goroutines:\n")
+			s.Write(bytes.TrimRight(w.dump, "\n"))
+			if w.truncated {
+				io.WriteString(s, "\n... truncated")
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
@@ -0,0 +1,133 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+func TestGo(t *testing.T) {
+	tests := []struct {
+		fn   func() error
+		want string
+	}{
+		{func() error { return nil }, "<nil>"},
+		{func() error { return io.EOF }, "EOF"},
+		{func() error { panic("boom") }, "panic: boom"},
+	}
+
+	for i, tt := range tests {
+		ch := Go(tt.fn)
+		if got := fmt.Sprint(<-ch); got != tt.want {
+			t.Errorf("test %d: Go(): got %q, want %q", i+1, got, tt.want)
+		}
+		if _, ok := <-ch; ok {
+			t.Errorf("test %d: Go(): channel not closed after result", i+1)
+		}
+	}
+}
+
+func TestGoHandle(t *testing.T) {
+	errs := make(chan error, 1)
+	GoHandle(func() error { panic("boom") }, func(err error) { errs <- err })
+	err := <-errs
+	if got := err.Error(); got != "panic: boom" {
+		t.Errorf("GoHandle(): got %q, want %q", got, "panic: boom")
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "errors.TestGoHandle.func1\n") {
+		t.Errorf("GoHandle(): stack does not start at the panic:\n%s", got)
+	}
+
+	done := make(chan struct{})
+	GoHandle(func() error {
+		defer close(done)
+		return nil
+	}, func(err error) { t.Errorf("GoHandle(): handler called with %v", err) })
+	<-done
+}
+
+func TestGoroutineID(t *testing.T) {
+	if _, ok := GoroutineID(New("error")); ok {
+		t.Errorf("GoroutineID(): got an ID with goroutine capture disabled")
+	}
+
+	SetGoroutineCapture(true)
+	defer SetGoroutineCapture(false)
+
+	want, _ := currentGoroutine()
+	tests := []error{
+		New("error"),
+		WithMessage(Wrap(io.EOF, "read"), "load"),
+	}
+	for i, err := range tests {
+		id, ok := GoroutineID(err)
+		if !ok || id != want {
+			t.Errorf("test %d: GoroutineID(): got %d, %t, want %d, true", i+1, id, ok, want)
+		}
+		if got := fmt.Sprintf("%+v", err); !strings.Contains(got, fmt.Sprintf("\ngoroutine %d\n", want)) {
+			t.Errorf("test %d: fmt.Sprintf(%%+v): goroutine %d missing:\n%s", i+1, want, got)
+		}
+	}
+
+	err := <-Go(func() error { panic("boom") })
+	if id, ok := GoroutineID(err); !ok || id == want {
+		t.Errorf("GoroutineID(): got %d, %t, want the ID of the panicking goroutine", id, ok)
+	}
+	if _, ok := GoroutineID(io.EOF); ok {
+		t.Errorf("GoroutineID(io.EOF): got an ID, want none")
+	}
+}
+
+func TestWithAllGoroutines(t *testing.T) {
+	if got := WithAllGoroutines(nil); got != nil {
+		t.Errorf("WithAllGoroutines(nil): got %#v, expected nil", got)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	go func() { <-release }()
+
+	err := WithAllGoroutines(Wrap(io.EOF, "timeout"))
+	if got := err.Error(); got != "timeout: EOF" {
+		t.Errorf("WithAllGoroutines(): got %q, want %q", got, "timeout: EOF")
+	}
+	got := fmt.Sprintf("%+v", err)
+	for _, want := range []string{
+		"\ngoroutines:\ngoroutine ",
+		"errors.TestWithAllGoroutines(",
+		"errors.TestWithAllGoroutines.func1(",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("fmt.Sprintf(%%+v): %q missing:\n%s", want, got)
+		}
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(err, io.EOF): got false, want true")
+	}
+}
+
+func TestGoroutineLabels(t *testing.T) {
+	SetGoroutineCapture(true)
+	defer SetGoroutineCapture(false)
+
+	var err error
+	pprof.Do(context.Background(), pprof.Labels("worker", "3", "handler", "login"), func(ctx context.Context) {
+		err = WrapCtx(ctx, io.EOF, "read")
+	})
+	id, _ := currentGoroutine()
+	want := fmt.Sprintf("\ngoroutine %d [labels: handler=login worker=3]\n", id)
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, want) {
+		t.Errorf("fmt.Sprintf(%%+v): got %q, want it to contain %q", got, want)
+	}
+	rec := jsonError(err)
+	if rec.Goroutine != id || rec.Labels["handler"] != "login" || rec.Labels["worker"] != "3" {
+		t.Errorf("jsonError(): got goroutine %d, labels %v", rec.Goroutine, rec.Labels)
+	}
+
+	if got := fmt.Sprintf("%+v", NewCtx(context.Background(), "error")); strings.Contains(got, "labels") {
+		t.Errorf("fmt.Sprintf(%%+v) without labels: got %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// A Group is a collection of goroutines working on subtasks of a common task,
+// in the manner of golang.org/x/sync/errgroup. Unlike errgroup, a Group
+// records every failure, annotated with the stack trace of the call to Go
+// which started the failing goroutine, and converts panics into errors.
+//
+// A zero Group is valid, has no limit on the number of active goroutines and
+// does not cancel on error. A nil *Group runs nothing: Go does nothing, and
+// Wait and WaitAll return nil.
+type Group struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// GroupWithContext returns a new Group and an associated Context derived
+// from ctx. The derived Context is canceled the first time a function passed
+// to Go returns a non-nil error or panics, or the first time Wait or WaitAll
+// returns, whichever occurs first.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls fn in a new goroutine. If fn returns a non-nil error or panics,
+// the error is recorded, annotated with the stack trace of the call to Go.
+func (g *Group) Go(fn func() error) {
+	if g == nil {
+		return
+	}
+	st := callers()
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := call(fn); err != nil {
+			g.fail(&withStack{err, st})
+		}
+	}()
+}
+
+// fail records err, canceling the Group's context on the first failure.
+func (g *Group) fail(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.errs = append(g.errs, err)
+	if len(g.errs) == 1 && g.cancel != nil {
+		g.cancel()
+	}
+}
+
+// Wait blocks until every goroutine started with Go has returned, then
+// returns the first error recorded, if any.
+func (g *Group) Wait() error {
+	if g == nil {
+		return nil
+	}
+	g.wait()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	return g.errs[0]
+}
+
+// WaitAll blocks until every goroutine started with Go has returned, then
+// returns every error recorded, in the order they occurred, joined as by
+// Join.
+func (g *Group) WaitAll() error {
+	if g == nil {
+		return nil
+	}
+	g.wait()
+	return Join(g.errs...)
+}
+
+func (g *Group) wait() {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	var g Group
+	if err := g.Wait(); err != nil {
+		t.Errorf("Wait() on empty Group: got %v, want nil", err)
+	}
+
+	g.Go(func() error { return nil })
+	g.Go(func() error { return io.EOF })
+	g.Go(func() error { panic("boom") })
+
+	err := g.WaitAll()
+	got := strings.Split(err.Error(), "\n")
+	if len(got) != 2 {
+		t.Fatalf("WaitAll(): got %q, want two errors", got)
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(WaitAll(), io.EOF): got false, want true")
+	}
+	if first := g.Wait(); first.Error() != got[0] {
+		t.Errorf("Wait(): got %q, want %q", first, got[0])
+	}
+	if s := fmt.Sprintf("%+v", err); !strings.Contains(s, "errors.TestGroup\n") {
+		t.Errorf("WaitAll(): errors do not carry the stack of the call to Go:\n%s", s)
+	}
+}
+
+func TestGroupWithContext(t *testing.T) {
+	g, ctx := GroupWithContext(context.Background())
+	g.Go(func() error { return io.EOF })
+	g.Go(func() error {
+		<-ctx.Done()
+		return nil
+	})
+
+	if err := g.Wait(); !Is(err, io.EOF) {
+		t.Errorf("Wait(): got %v, want %v", err, io.EOF)
+	}
+	if ctx.Err() == nil {
+		t.Error("context not canceled after Wait")
+	}
+}
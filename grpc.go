@@ -0,0 +1,37 @@
+package errors
+
+// kindGRPCCode maps kinds to the numeric gRPC status codes, as defined by
+// google.golang.org/grpc/codes, conventionally used for them.
+var kindGRPCCode = map[Kind]uint32{
+	Canceled:         1,
+	InvalidArgument:  3,
+	DeadlineExceeded: 4,
+	NotFound:         5,
+	AlreadyExists:    6,
+	PermissionDenied: 7,
+	Conflict:         10, // Aborted
+	Internal:         13,
+	Unavailable:      14,
+	Unauthenticated:  16,
+}
+
+// grpcUnknown is the gRPC status code Unknown.
+const grpcUnknown = 2
+
+// GRPCCode returns the numeric gRPC status code conventionally used for the
+// Kind of err, or 2 (Unknown) if err has no kind with a gRPC equivalent.
+// GRPCCode returns 0 (OK) if err is nil. The result converts directly to a
+// codes.Code of package google.golang.org/grpc/codes.
+func GRPCCode(err error) uint32 {
+	if err == nil {
+		return 0
+	}
+	return kindToGRPCCode(KindOf(err))
+}
+
+func kindToGRPCCode(kind Kind) uint32 {
+	if code, ok := kindGRPCCode[kind]; ok {
+		return code
+	}
+	return grpcUnknown
+}
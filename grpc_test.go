@@ -0,0 +1,25 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestGRPCCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want uint32
+	}{
+		{nil, 0},
+		{io.EOF, 2},
+		{WithKind(io.EOF, NotFound), 5},
+		{Wrap(WithKind(io.EOF, Unavailable), "dial"), 14},
+		{WithKind(io.EOF, Kind("custom")), 2},
+	}
+
+	for i, tt := range tests {
+		if got := GRPCCode(tt.err); got != tt.want {
+			t.Errorf("test %d: GRPCCode(): got %d, want %d", i+1, got, tt.want)
+		}
+	}
+}
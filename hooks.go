@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	hooksMu sync.Mutex   // serialises writers of hooks
+	hooks   atomic.Value // of []*hook
+)
+
+// hook is a registered hook, compared by address when it is removed.
+type hook struct {
+	fn func(error)
+}
+
+// RegisterHook registers fn to be called with every error this package
+// constructs or wraps, after construction is complete. Hooks are called
+// synchronously, in registration order, on the goroutine creating the error,
+// so they should be cheap; a hook that needs to do real work, such as
+// forwarding the error to a telemetry system, should hand it off.
+//
+// RegisterHook is safe for concurrent use, but is intended to be called
+// during program initialisation.
+func RegisterHook(fn func(error)) {
+	if fn == nil {
+		return
+	}
+	addHook(fn)
+}
+
+// FirstStack reports whether err carries a stack trace and none of the
+// errors it wraps do, that is whether err is the error of its chain which
+// recorded where the failure it represents was first observed. A hook
+// registered by RegisterHook can use it to handle each failure once, when
+// it is first created, however many times its error is then wrapped.
+func FirstStack(err error) bool {
+	type stackTracer interface {
+		StackTrace() StackTrace
+	}
+
+	if _, ok := err.(stackTracer); !ok {
+		return false
+	}
+	first := true
+	Walk(Unwrap(err), func(err error) bool {
+		_, ok := err.(stackTracer)
+		first = !ok
+		return first
+	})
+	return first
+}
+
+// addHook registers fn as a hook and returns a function removing it.
+func addHook(fn func(error)) (remove func()) {
+	h := &hook{fn}
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	old, _ := hooks.Load().([]*hook)
+	hs := make([]*hook, len(old), len(old)+1)
+	copy(hs, old)
+	hooks.Store(append(hs, h))
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+		old, _ := hooks.Load().([]*hook)
+		hs := make([]*hook, 0, len(old))
+		for _, o := range old {
+			if o != h {
+				hs = append(hs, o)
+			}
+		}
+		hooks.Store(hs)
+	}
+}
+
+// resetHooks removes all registered hooks.
+func resetHooks() {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks.Store([]*hook{})
+}
+
+// created notifies the registered hooks that err has been constructed,
+// logs it to the execution trace if enabled, and returns err unchanged.
+func created(err error) error {
+	traceCreated(err)
+	hs, _ := hooks.Load().([]*hook)
+	for _, h := range hs {
+		h.fn(err)
+	}
+	return err
+}
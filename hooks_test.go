@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestRegisterHook(t *testing.T) {
+	var got []error
+	RegisterHook(func(err error) { got = append(got, err) })
+	RegisterHook(nil)
+	defer resetHooks()
+
+	errs := []error{
+		New("error"),
+		Errorf("error %d", 1),
+		WithStack(io.EOF),
+		Wrap(io.EOF, "error"),
+		Wrapf(io.EOF, "error %d", 1),
+		WithMessage(io.EOF, "error"),
+		WithMessagef(io.EOF, "error %d", 1),
+		WithField(io.EOF, "key", "value"),
+		WithFields(io.EOF, map[string]interface{}{"key": "value"}),
+		WithSensitiveField(io.EOF, "key", "value"),
+	}
+	_ = Wrap(nil, "nil errors are not reported")
+
+	if len(got) != len(errs) {
+		t.Fatalf("hook called %d times, want %d", len(got), len(errs))
+	}
+	for i := range errs {
+		if got[i] != errs[i] {
+			t.Errorf("test %d: hook got %#v, want %#v", i+1, got[i], errs[i])
+		}
+	}
+}
+
+func TestRegisterHookOrder(t *testing.T) {
+	var order []int
+	RegisterHook(func(error) { order = append(order, 1) })
+	RegisterHook(func(error) { order = append(order, 2) })
+	defer resetHooks()
+
+	_ = New("error")
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("hooks called in order %v, want [1 2]", order)
+	}
+}
+
+func TestFirstStack(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{io.EOF, false},
+		{New("error"), true},
+		{WithStack(io.EOF), true},
+		{Wrap(io.EOF, "read"), true},
+		{Wrap(New("error"), "read"), false},
+		{WithCode(New("error"), "CODE"), false},
+		{WithMessage(io.EOF, "read"), false},
+		{Join(io.EOF, New("error")), false},
+	}
+	for i, tt := range tests {
+		if got := FirstStack(tt.err); got != tt.want {
+			t.Errorf("test %d: FirstStack(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
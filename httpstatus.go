@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// WithHTTPStatus annotates err with the HTTP status code a server should
+// respond with when err reaches it.
+// If err is nil, WithHTTPStatus returns nil.
+func WithHTTPStatus(err error, status int) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withHTTPStatus{
+		cause:  err,
+		status: status,
+	}))
+}
+
+// kindStatus maps kinds to the HTTP status codes conventionally used for
+// them.
+var kindStatus = map[Kind]int{
+	Internal:         http.StatusInternalServerError,
+	InvalidArgument:  http.StatusBadRequest,
+	NotFound:         http.StatusNotFound,
+	AlreadyExists:    http.StatusConflict,
+	Conflict:         http.StatusConflict,
+	PermissionDenied: http.StatusForbidden,
+	Unauthenticated:  http.StatusUnauthorized,
+	Unavailable:      http.StatusServiceUnavailable,
+	DeadlineExceeded: http.StatusGatewayTimeout,
+	Canceled:         499, // Client Closed Request, as used by nginx.
+}
+
+// HTTPStatus returns the HTTP status code for err: the outermost status
+// attached with WithHTTPStatus if any, otherwise the status conventionally
+// used for its Kind, otherwise 500 Internal Server Error. HTTPStatus returns
+// 200 OK if err is nil.
+func HTTPStatus(err error) int {
+	type statuser interface {
+		HTTPStatus() int
+	}
+
+	if err == nil {
+		return http.StatusOK
+	}
+	var c cycle
+	for e := err; e != nil && !c.seen(e); e = Unwrap(e) {
+		if s, ok := e.(statuser); ok && s.HTTPStatus() != 0 {
+			return s.HTTPStatus()
+		}
+	}
+	if status, ok := kindStatus[KindOf(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+type withHTTPStatus struct {
+	cause  error
+	status int
+}
+
+func (w *withHTTPStatus) Error() string   { return redact(w.cause.Error()) }
+func (w *withHTTPStatus) Cause() error    { return w.cause }
+func (w *withHTTPStatus) HTTPStatus() int { return w.status }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withHTTPStatus) Unwrap() error { return w.cause }
+
+func (w *withHTTPStatus) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, "http_status="+strconv.Itoa(w.status))
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
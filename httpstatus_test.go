@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 200},
+		{io.EOF, 500},
+		{WithKind(io.EOF, NotFound), 404},
+		{WithKind(io.EOF, Kind("custom")), 500},
+		{WithHTTPStatus(io.EOF, 402), 402},
+		{Wrap(WithHTTPStatus(WithKind(io.EOF, NotFound), 410), "lookup"), 410},
+	}
+
+	for i, tt := range tests {
+		if got := HTTPStatus(tt.err); got != tt.want {
+			t.Errorf("test %d: HTTPStatus(): got %d, want %d", i+1, got, tt.want)
+		}
+	}
+
+	if got := WithHTTPStatus(nil, 402); got != nil {
+		t.Errorf("WithHTTPStatus(nil, 402): got %#v, expected nil", got)
+	}
+	if got := fmt.Sprintf("%+v", WithHTTPStatus(io.EOF, 402)); got != "EOF\nhttp_status=402" {
+		t.Errorf("fmt.Sprintf(%%+v, err): got %q", got)
+	}
+}
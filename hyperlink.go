@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var hyperlinkFormat atomic.Value // of string
+
+// SetHyperlinks turns the source location of each frame printed by %+v into
+// an OSC 8 terminal hyperlink, which terminals such as those of VS Code,
+// GoLand and iTerm2 open when it is clicked. The location is still printed
+// as "file:line", which IDEs recognize even without hyperlinks.
+//
+// The target of the hyperlinks is built from urlFormat by replacing "{file}"
+// with the escaped path of the source file and "{line}" with the line
+// number, for example:
+//
+//	errors.SetHyperlinks("file://{file}")
+//	errors.SetHyperlinks("vscode://file{file}:{line}")
+//	errors.SetHyperlinks("goland://open?file={file}&line={line}")
+//
+// Passing "" turns hyperlinks off, which is the default.
+func SetHyperlinks(urlFormat string) { hyperlinkFormat.Store(urlFormat) }
+
+// hyperlink returns the target of the hyperlink to the source location of
+// f, or "" if hyperlinks are off.
+func hyperlink(f Frame) string {
+	format, _ := hyperlinkFormat.Load().(string)
+	if format == "" {
+		return ""
+	}
+	_, file, line := f.resolve()
+	return strings.NewReplacer(
+		"{file}", (&url.URL{Path: file}).EscapedPath(),
+		"{line}", strconv.Itoa(line),
+	).Replace(format)
+}
+
+// osc8 returns text as an OSC 8 terminal hyperlink to target.
+func osc8(target, text string) string {
+	return "\x1b]8;;" + target + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetHyperlinks(t *testing.T) {
+	defer SetHyperlinks("")
+	defer SetPathStyle(PathFull)
+
+	f := NewFrame("app.main", "/src/my app/main.go", 7)
+	tests := []struct {
+		format string
+		style  PathStyle
+		want   string
+	}{
+		{"", PathFull, "app.main\n\t/src/my app/main.go:7"},
+		{"file://{file}", PathFull, "app.main\n\t\x1b]8;;file:///src/my%20app/main.go\x1b\\/src/my app/main.go:7\x1b]8;;\x1b\\"},
+		{"vscode://file{file}:{line}", PathBase, "app.main\n\t\x1b]8;;vscode://file/src/my%20app/main.go:7\x1b\\main.go:7\x1b]8;;\x1b\\"},
+	}
+
+	for i, tt := range tests {
+		SetHyperlinks(tt.format)
+		SetPathStyle(tt.style)
+		if got := fmt.Sprintf("%+v", f); got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%%+v, frame): got %q, want %q", i+1, got, tt.want)
+		}
+		if got := fmt.Sprintf("%v", f); got != "main.go:7" {
+			t.Errorf("test %d: fmt.Sprintf(%%v, frame): got %q, want %q", i+1, got, "main.go:7")
+		}
+	}
+}
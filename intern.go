@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sprintf formats a message as fmt.Sprintf does, but returns format itself,
+// without allocating, when there is nothing to format, as with constant
+// messages passed to Errorf, Wrapf and message templates.
+func sprintf(format string, args ...interface{}) string {
+	if len(args) == 0 && strings.IndexByte(format, '%') < 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// frameTexts caches the text returned by Frame.text, by frame. A program
+// has a bounded number of distinct frames, so the cache is bounded too.
+var frameTexts sync.Map // of Frame to string
+
+// text returns the frame as "function file:line", resolving it once: the
+// text is shared by every stack trace holding f.
+func (f Frame) text() string {
+	if s, ok := frameTexts.Load(f); ok {
+		return s.(string)
+	}
+	name, file, line := f.resolve()
+	if name == "unknown" {
+		return name
+	}
+	s := name + " " + file + ":" + strconv.Itoa(line)
+	frameTexts.Store(f, s)
+	return s
+}
+
+// resolvedFrame holds the components of a Frame cached by resolveCached.
+type resolvedFrame struct {
+	name  string // package path-qualified function name
+	short string // function name as returned by funcname
+	file  string
+	line  int
+}
+
+// resolvedFrames caches the components of frames formatted with %n, %s and
+// %v, by frame, so that formatting a stack trace repeatedly, as with %+v,
+// resolves and trims each function name once.
+var resolvedFrames sync.Map // of Frame to *resolvedFrame
+
+// resolveCached returns the components of f, resolving them once.
+func (f Frame) resolveCached() *resolvedFrame {
+	if r, ok := resolvedFrames.Load(f); ok {
+		return r.(*resolvedFrame)
+	}
+	name, file, line := f.resolve()
+	r := &resolvedFrame{name: name, short: funcname(name), file: file, line: line}
+	if name == "unknown" {
+		return r
+	}
+	resolvedFrames.Store(f, r)
+	return r
+}
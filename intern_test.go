@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSprintf(t *testing.T) {
+	tests := []struct {
+		format string
+		args   []interface{}
+		want   string
+	}{
+		{"error", nil, "error"},
+		{"100%% done", nil, "100% done"},
+		{"user %s", []interface{}{"acme"}, "user acme"},
+		{"no verbs", []interface{}{1}, "no verbs%!(EXTRA int=1)"},
+	}
+
+	for i, tt := range tests {
+		if got := sprintf(tt.format, tt.args...); got != tt.want {
+			t.Errorf("test %d: sprintf(%q): got %q, want %q", i+1, tt.format, got, tt.want)
+		}
+	}
+	if n := testing.AllocsPerRun(100, func() { sprintf("error") }); n != 0 {
+		t.Errorf("sprintf(%q): got %v allocations, want 0", "error", n)
+	}
+}
+
+func TestFrameText(t *testing.T) {
+	f := NewFrame("app.main", "/src/app/main.go", 7)
+	if got, want := f.text(), "app.main /src/app/main.go:7"; got != want {
+		t.Errorf("text(): got %q, want %q", got, want)
+	}
+	if got := Frame(0).text(); got != "unknown" {
+		t.Errorf("text(): got %q, want %q", got, "unknown")
+	}
+	if n := testing.AllocsPerRun(100, func() { f.text() }); n != 0 {
+		t.Errorf("text(): got %v allocations once cached, want 0", n)
+	}
+}
+
+func TestFrameResolveCached(t *testing.T) {
+	f := NewFrame("example.com/app.(*Server).handle", "/src/app/server.go", 12)
+	for _, tt := range []struct {
+		format string
+		want   string
+	}{
+		{"%n", "(*Server).handle"},
+		{"%s", "server.go"},
+		{"%+v", "example.com/app.(*Server).handle\n\t/src/app/server.go:12"},
+	} {
+		if got := fmt.Sprintf(tt.format, f); got != tt.want {
+			t.Errorf("Sprintf(%q): got %q, want %q", tt.format, got, tt.want)
+		}
+	}
+	if a, b := f.resolveCached(), f.resolveCached(); a != b {
+		t.Errorf("resolveCached(): got distinct results for the same frame")
+	}
+}
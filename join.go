@@ -0,0 +1,262 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Join returns an error that wraps the given errors, discarding nils.
+// Join returns nil if every value in errs is nil.
+//
+// The error formats as the concatenation of the messages of the wrapped
+// errors, separated by newlines; with %+v the wrapped errors are printed in
+// detail as a tree, each under its own bullet. Is and As consider every
+// wrapped error.
+func Join(errs ...error) error {
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	j := &joinError{errs: make([]error, 0, n)}
+	for _, err := range errs {
+		if err != nil {
+			j.errs = append(j.errs, err)
+		}
+	}
+	return j
+}
+
+// Append appends errs to err, discarding nils. If err was returned by Join
+// or Append, the errors are added alongside the ones it already wraps rather
+// than nesting a new level.
+// Append returns nil if err and every value in errs are nil.
+func Append(err error, errs ...error) error {
+	j, ok := err.(*joinError)
+	if !ok {
+		return Join(append([]error{err}, errs...)...)
+	}
+	all := make([]error, 0, len(j.errs)+len(errs))
+	all = append(all, j.errs...)
+	return Join(append(all, errs...)...)
+}
+
+// WrapAll wraps each non-nil error of errs, as by Wrapf, with the format
+// specifier followed by the index of the error in errs, and joins them as by
+// Join, as when validating or processing the items of a slice:
+//
+//	errs := make([]error, len(items))
+//	for i, item := range items {
+//		errs[i] = validate(item)
+//	}
+//	return errors.WrapAll(errs, "validate item") // "validate item [2]: ..."
+//
+// The wrapped errors share the stack trace recorded at the point WrapAll is
+// called. WrapAll returns nil if every value in errs is nil.
+func WrapAll(errs []error, format string, args ...interface{}) error {
+	var st *stack
+	var wrapped []error
+	msg := sprintf(format, args...)
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if st == nil {
+			st = callers()
+		}
+		wrapped = append(wrapped, created(transform(err, &withStack{
+			&withMessage{
+				cause: err,
+				msg:   msg + " [" + strconv.Itoa(i) + "]",
+			},
+			st,
+		})))
+	}
+	return Join(wrapped...)
+}
+
+// Filter returns the errors joined in err, as by Join or Append, for which
+// keep reports true, joined as by Join, so that ignorable errors can be
+// dropped from a batch before deciding whether it failed:
+//
+//	err = errors.Filter(err, func(err error) bool {
+//		return errors.KindOf(err) != errors.NotFound
+//	})
+//
+// Errors joined within joined errors are filtered in turn. An error that
+// does not join several errors, including one wrapping a joined error, is
+// kept or dropped as a whole. Filter returns nil if no error is kept. A nil
+// keep keeps every error.
+func Filter(err error, keep func(error) bool) error {
+	matched, _ := Partition(err, keep)
+	return matched
+}
+
+// Partition splits the errors joined in err, as by Join or Append, into
+// those for which pred reports true and the rest, each joined as by Join,
+// as Filter does. Either is nil if it holds no error. A nil pred reports
+// true for every error.
+func Partition(err error, pred func(error) bool) (matched, rest error) {
+	if err == nil {
+		return nil, nil
+	}
+	if pred == nil {
+		return err, nil
+	}
+	u, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		if pred(err) {
+			return err, nil
+		}
+		return nil, err
+	}
+	var m, r []error
+	for _, e := range u.Unwrap() {
+		em, er := Partition(e, pred)
+		if em != nil {
+			m = append(m, em)
+		}
+		if er != nil {
+			r = append(r, er)
+		}
+	}
+	return Join(m...), Join(r...)
+}
+
+// MostSevere returns the error joined in err, as by Join or Append, of the
+// highest severity, as returned by SeverityOf, so that the branch that
+// matters most can be logged or propagated. Of several errors of the same
+// severity, the first is returned. Errors joined within joined errors are
+// considered in turn; an error that does not join several errors is
+// returned as is. MostSevere returns nil if err is nil.
+func MostSevere(err error) error {
+	return MostSevereBy(err, func(err error) int { return int(SeverityOf(err)) })
+}
+
+// MostSevereBy is like MostSevere, but ranks errors by rank instead of by
+// severity: the error of the highest rank is returned. RankKinds returns a
+// ranking by kind:
+//
+//	err = errors.MostSevereBy(err, errors.RankKinds(errors.Internal, errors.Unavailable))
+//
+// If rank is nil, errors are ranked by severity, as by MostSevere.
+func MostSevereBy(err error, rank func(error) int) error {
+	if rank == nil {
+		return MostSevere(err)
+	}
+	u, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return err
+	}
+	var most error
+	var mostRank int
+	for _, e := range u.Unwrap() {
+		if e = MostSevereBy(e, rank); e == nil {
+			continue
+		}
+		if r := rank(e); most == nil || r > mostRank {
+			most, mostRank = e, r
+		}
+	}
+	return most
+}
+
+// RankKinds returns a ranking for MostSevereBy ranking errors by their
+// kind, as returned by KindOf, from the most severe of kinds to the least.
+// Errors of other kinds rank below all of kinds.
+func RankKinds(kinds ...Kind) func(error) int {
+	return func(err error) int {
+		k := KindOf(err)
+		for i, kind := range kinds {
+			if k == kind {
+				return len(kinds) - i
+			}
+		}
+		return 0
+	}
+}
+
+// FromChannel receives from ch until it is closed and returns the non-nil
+// errors received, joined as by Join in the order they arrived. Each error is
+// kept as is, so errors created by this package retain their own stack trace.
+// FromChannel returns nil if no non-nil error was received.
+func FromChannel(ch <-chan error) error {
+	var errs []error
+	for err := range ch {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return Join(errs...)
+}
+
+type joinError struct {
+	errs []error
+}
+
+func (j *joinError) Error() string {
+	msgs := make([]string, len(j.errs))
+	for i, err := range j.errs {
+		msgs[i] = err.Error()
+	}
+	return redact(strings.Join(msgs, "\n"))
+}
+
+// Unwrap returns the joined errors, for compatibility with Go 1.20 error
+// trees.
+func (j *joinError) Unwrap() []error { return j.errs }
+
+// header returns the line introducing the joined errors, such as
+// "2 errors occurred:".
+func (j *joinError) header() string {
+	if len(j.errs) == 1 {
+		return "1 error occurred:"
+	}
+	return strconv.Itoa(len(j.errs)) + " errors occurred:"
+}
+
+// formatTree writes the joined errors to w as a tree: each error is printed
+// in detail under its own bullet, indented so that the errors joined by a
+// joined error are nested under it.
+//
+//	2 errors occurred:
+//	* first
+//	  github.com/pkg/app.run
+//	  	/src/app/run.go:12
+//	* EOF
+func (j *joinError) formatTree(w io.Writer) {
+	io.WriteString(w, j.header())
+	for _, err := range j.errs {
+		for i, line := range strings.Split(redactf("%+v", err), "\n") {
+			if i == 0 {
+				io.WriteString(w, "\n* "+line)
+			} else {
+				io.WriteString(w, "\n  "+line)
+			}
+		}
+	}
+}
+
+func (j *joinError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, j) {
+				return
+			}
+			j.formatTree(s)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, j.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", j.Error())
+	}
+}
@@ -0,0 +1,225 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Join(), ""},
+		{Join(nil, nil), ""},
+		{Join(io.EOF), "EOF"},
+		{Join(io.EOF, nil, io.ErrUnexpectedEOF), "EOF\nunexpected EOF"},
+		{Append(nil), ""},
+		{Append(nil, io.EOF), "EOF"},
+		{Append(io.EOF, io.ErrClosedPipe), "EOF\nio: read/write on closed pipe"},
+		{Append(Join(io.EOF, io.ErrUnexpectedEOF), io.ErrClosedPipe), "EOF\nunexpected EOF\nio: read/write on closed pipe"},
+	}
+
+	for i, tt := range tests {
+		got := ""
+		if tt.err != nil {
+			got = tt.err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	if got := len(Append(Join(io.EOF, io.ErrUnexpectedEOF), io.ErrClosedPipe).(*joinError).errs); got != 3 {
+		t.Errorf("Append(Join(...), ...): got %d errors, want 3 at a single level", got)
+	}
+}
+
+func TestJoinIs(t *testing.T) {
+	err := Wrap(Join(io.EOF, WithStack(io.ErrUnexpectedEOF)), "read")
+	for _, target := range []error{io.EOF, io.ErrUnexpectedEOF} {
+		if !Is(err, target) {
+			t.Errorf("Is(err, %v): got false, want true", target)
+		}
+	}
+	if Is(err, io.ErrClosedPipe) {
+		t.Errorf("Is(err, %v): got true, want false", io.ErrClosedPipe)
+	}
+}
+
+func TestFormatJoin(t *testing.T) {
+	err := Join(New("first"), io.EOF)
+	want := "^2 errors occurred:\n" +
+		"\\* first\n" +
+		"  github.com/peakle/errors.TestFormatJoin\n" +
+		"  \t.+/join_test.go:\\d+\n" +
+		"(?s:.*)\n\\* EOF$"
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+	if got := fmt.Sprintf("%q", err); got != `"first\nEOF"` {
+		t.Errorf("fmt.Sprintf(%%q, err): got %s", got)
+	}
+}
+
+func TestFromChannel(t *testing.T) {
+	ch := make(chan error, 4)
+	ch <- nil
+	ch <- New("first")
+	ch <- nil
+	ch <- io.EOF
+	close(ch)
+
+	err := FromChannel(ch)
+	if got := err.Error(); got != "first\nEOF" {
+		t.Errorf("FromChannel(): got %q, want %q", got, "first\nEOF")
+	}
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(`errors\.TestFromChannel\n`).MatchString(got) {
+		t.Errorf("FromChannel(): stack of contributor lost:\n%s", got)
+	}
+
+	empty := make(chan error, 1)
+	empty <- nil
+	close(empty)
+	if err := FromChannel(empty); err != nil {
+		t.Errorf("FromChannel() of nils: got %v, want nil", err)
+	}
+}
+
+func TestFormatJoinTree(t *testing.T) {
+	f := NewFrame("app.run", "/src/app/run.go", 12)
+	err := Join(
+		&withStack{io.EOF, &stack{pcs: []uintptr{uintptr(f)}}},
+		Join(io.ErrUnexpectedEOF, WithMessage(io.ErrClosedPipe, "write")),
+	)
+	want := "2 errors occurred:\n" +
+		"* EOF\n" +
+		"  app.run\n" +
+		"  \t/src/app/run.go:12\n" +
+		"* 2 errors occurred:\n" +
+		"  * unexpected EOF\n" +
+		"  * io: read/write on closed pipe\n" +
+		"    write"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestWrapAll(t *testing.T) {
+	tests := []struct {
+		errs []error
+		want string
+	}{
+		{nil, ""},
+		{[]error{nil, nil}, ""},
+		{[]error{nil, io.EOF}, "process item 7 [1]: EOF"},
+		{[]error{io.EOF, nil, io.ErrUnexpectedEOF}, "process item 7 [0]: EOF\nprocess item 7 [2]: unexpected EOF"},
+	}
+
+	for i, tt := range tests {
+		err := WrapAll(tt.errs, "process item %d", 7)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("test %d: WrapAll(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	err := WrapAll([]error{io.EOF, io.ErrUnexpectedEOF}, "read")
+	if !Is(err, io.EOF) || !Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("WrapAll(): Is reports false for a wrapped error")
+	}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		st := e.(*withStack).StackTrace()
+		if len(st) == 0 || st[0].name() != "github.com/peakle/errors.TestWrapAll" {
+			t.Errorf("WrapAll(): got stack trace %v, want one starting in TestWrapAll", st)
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	notFound := WithKind(New("no user"), NotFound)
+	isNotFound := func(err error) bool { return KindOf(err) == NotFound }
+	msg := func(err error) string {
+		if err == nil {
+			return "<nil>"
+		}
+		return err.Error()
+	}
+
+	tests := []struct {
+		err           error
+		matched, rest string
+	}{
+		{nil, "<nil>", "<nil>"},
+		{io.EOF, "<nil>", "EOF"},
+		{notFound, "no user", "<nil>"},
+		{Join(io.EOF, notFound), "no user", "EOF"},
+		{Join(notFound, notFound), "no user\nno user", "<nil>"},
+		{Append(Join(io.EOF, notFound), Join(notFound, io.ErrUnexpectedEOF)), "no user\nno user", "EOF\nunexpected EOF"},
+		{Wrap(Join(io.EOF, notFound), "batch"), "<nil>", "batch: EOF\nno user"},
+	}
+
+	for i, tt := range tests {
+		matched, rest := Partition(tt.err, isNotFound)
+		if msg(matched) != tt.matched || msg(rest) != tt.rest {
+			t.Errorf("test %d: Partition(%q): got %q, %q, want %q, %q", i+1, msg(tt.err), msg(matched), msg(rest), tt.matched, tt.rest)
+		}
+		if got := msg(Filter(tt.err, isNotFound)); got != tt.matched {
+			t.Errorf("test %d: Filter(%q): got %q, want %q", i+1, msg(tt.err), got, tt.matched)
+		}
+	}
+}
+
+func TestMostSevere(t *testing.T) {
+	warning := WithSeverity(New("warning"), SeverityWarning)
+	critical := WithSeverity(New("critical"), SeverityCritical)
+	msg := func(err error) string {
+		if err == nil {
+			return "<nil>"
+		}
+		return err.Error()
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, "<nil>"},
+		{io.EOF, "EOF"},
+		{Join(io.EOF, io.ErrUnexpectedEOF), "EOF"},
+		{Join(io.EOF, warning), "warning"},
+		{Join(warning, io.EOF, Join(io.EOF, critical)), "critical"},
+		{Wrap(Join(io.EOF, critical), "batch"), "batch: EOF\ncritical"},
+	}
+
+	for i, tt := range tests {
+		if got := msg(MostSevere(tt.err)); got != tt.want {
+			t.Errorf("test %d: MostSevere(%q): got %q, want %q", i+1, msg(tt.err), got, tt.want)
+		}
+	}
+
+	rank := RankKinds(Internal, Unavailable)
+	err := Join(io.EOF, WithKind(New("unavailable"), Unavailable), WithKind(New("not found"), NotFound), WithKind(New("internal"), Internal))
+	if got := msg(MostSevereBy(err, rank)); got != "internal" {
+		t.Errorf("MostSevereBy(RankKinds): got %q, want %q", got, "internal")
+	}
+	err = Join(io.EOF, WithKind(New("not found"), NotFound))
+	if got := msg(MostSevereBy(err, rank)); got != "EOF" {
+		t.Errorf("MostSevereBy(RankKinds) without ranked kinds: got %q, want %q", got, "EOF")
+	}
+}
+
+func TestFormatJoinSingle(t *testing.T) {
+	want := "1 error occurred:\n* EOF"
+	if got := fmt.Sprintf("%+v", Join(io.EOF)); got != want {
+		t.Errorf("fmt.Sprintf(%%+v, Join(io.EOF)): got %q, want %q", got, want)
+	}
+	if got := sectionHeader(Join(io.EOF)); got != "1 error occurred:" {
+		t.Errorf("sectionHeader(Join(io.EOF)): got %q, want %q", got, "1 error occurred:")
+	}
+}
@@ -0,0 +1,142 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// The error codes reserved by the JSON-RPC 2.0 specification that ToRPCError
+// maps errors to.
+const (
+	RPCInvalidParams = -32602
+	RPCInternalError = -32603
+	RPCServerError   = -32000
+)
+
+// RPCError is a JSON-RPC 2.0 error object, as found in the error member of a
+// response. It marshals to and from its JSON form with encoding/json.
+type RPCError struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    *RPCErrorData `json:"data,omitempty"`
+}
+
+// RPCErrorData is the data member of the error objects returned by
+// ToRPCError, carrying what the message and numeric code of the error
+// leave out.
+type RPCErrorData struct {
+	Code   string                 `json:"code,omitempty"`
+	Kind   string                 `json:"kind,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Stack  []string               `json:"stack,omitempty"`
+}
+
+// ToRPCError returns err as a JSON-RPC 2.0 error object, for services
+// exposing JSON-RPC APIs. The numeric code is the outermost one carried by
+// err, as by an error returned by FromRPCError, otherwise -32602 (Invalid
+// params) for errors of kind InvalidArgument, -32000 (Server error) for
+// errors of any other kind and -32603 (Internal error) for errors of no
+// kind. The data member carries the code, kind and fields of err, and the
+// stack trace recorded closest to its root cause, as in the JSON style;
+// errors returned to untrusted clients should be masked first, with Mask.
+// ToRPCError returns nil if err is nil.
+func ToRPCError(err error) *RPCError {
+	type rpcCoder interface {
+		RPCCode() int
+	}
+
+	if err == nil {
+		return nil
+	}
+	e := &RPCError{Code: RPCInternalError, Message: err.Error()}
+	kind := KindOf(err)
+	switch kind {
+	case Unknown, Internal:
+	case InvalidArgument:
+		e.Code = RPCInvalidParams
+	default:
+		e.Code = RPCServerError
+	}
+	var c cycle
+	for l := err; l != nil && !c.seen(l); l = Unwrap(l) {
+		if r, ok := l.(rpcCoder); ok {
+			e.Code = r.RPCCode()
+			break
+		}
+	}
+
+	data := RPCErrorData{
+		Code:   Code(err),
+		Fields: Fields(err),
+		Stack:  stackTexts(originStack(err)),
+	}
+	if kind != Unknown {
+		data.Kind = string(kind)
+	}
+	for k, v := range data.Fields {
+		data.Fields[k] = redact(fmt.Sprint(v))
+	}
+	if data.Code != "" || data.Kind != "" || data.Fields != nil || data.Stack != nil {
+		e.Data = &data
+	}
+	return e
+}
+
+// FromRPCError returns the error described by the JSON-RPC 2.0 error object
+// e, as received from a remote service, so that it can be handled like the
+// errors of this package: its message is that of e, and the code, kind and
+// fields carried by its data member, if any, are returned by Code, KindOf
+// and Fields. ToRPCError maps it back to an object of the same numeric code.
+// FromRPCError returns nil if e is nil.
+func FromRPCError(e *RPCError) error {
+	if e == nil {
+		return nil
+	}
+	r := &rpcError{code: e.Code, msg: e.Message}
+	if d := e.Data; d != nil {
+		r.errCode, r.kind = d.Code, Kind(d.Kind)
+		for _, k := range sortedKeys(d.Fields) {
+			r.fields = append(r.fields, field{key: k, value: d.Fields[k]})
+		}
+		r.stack = d.Stack
+	}
+	return created(r)
+}
+
+// rpcError is an error received as a JSON-RPC 2.0 error object.
+type rpcError struct {
+	code    int
+	msg     string
+	errCode string
+	kind    Kind
+	fields  []field
+	stack   []string // remote stack trace
+}
+
+func (e *rpcError) Error() string      { return redact(e.msg) }
+func (e *rpcError) RPCCode() int       { return e.code }
+func (e *rpcError) Code() string       { return e.errCode }
+func (e *rpcError) Kind() Kind         { return e.kind }
+func (e *rpcError) fieldList() []field { return e.fields }
+
+func (e *rpcError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, e) {
+				return
+			}
+			io.WriteString(s, e.Error())
+			fmt.Fprintf(s, "\njsonrpc_code=%d", e.code)
+			for _, frame := range e.stack {
+				io.WriteString(s, "\n\t"+frame)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
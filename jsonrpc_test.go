@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToRPCError(t *testing.T) {
+	if got := ToRPCError(nil); got != nil {
+		t.Errorf("ToRPCError(nil): got %+v, want nil", got)
+	}
+
+	tests := []struct {
+		err  error
+		code int
+		data *RPCErrorData
+	}{
+		{io.EOF, RPCInternalError, nil},
+		{WithKind(io.EOF, InvalidArgument), RPCInvalidParams, &RPCErrorData{Kind: "invalid_argument"}},
+		{WithKind(io.EOF, NotFound), RPCServerError, &RPCErrorData{Kind: "not_found"}},
+		{
+			WithCode(WithField(io.EOF, "id", 7), "EOF"),
+			RPCInternalError,
+			&RPCErrorData{Code: "EOF", Fields: map[string]interface{}{"id": "7"}},
+		},
+		{FromRPCError(&RPCError{Code: -32001, Message: "locked"}), -32001, nil},
+	}
+
+	for i, tt := range tests {
+		got := ToRPCError(tt.err)
+		if got.Code != tt.code || got.Message != tt.err.Error() || !reflect.DeepEqual(got.Data, tt.data) {
+			t.Errorf("test %d: ToRPCError(%v): got %+v, want code %d and data %+v", i+1, tt.err, got, tt.code, tt.data)
+		}
+	}
+
+	got := ToRPCError(Wrap(io.EOF, "read"))
+	if got.Data == nil || len(got.Data.Stack) == 0 || !strings.HasPrefix(got.Data.Stack[0], "github.com/peakle/errors.TestToRPCError ") {
+		t.Errorf("ToRPCError(): got data %+v, want the stack trace of TestToRPCError", got.Data)
+	}
+}
+
+func TestFromRPCError(t *testing.T) {
+	if got := FromRPCError(nil); got != nil {
+		t.Errorf("FromRPCError(nil): got %v, want nil", got)
+	}
+
+	b, _ := json.Marshal(ToRPCError(WithKind(WithCode(WithField(Wrap(io.EOF, "read"), "id", 7), "EOF"), NotFound)))
+	var obj RPCError
+	if err := json.Unmarshal(b, &obj); err != nil {
+		t.Fatal(err)
+	}
+	err := Wrap(FromRPCError(&obj), "call")
+
+	if got, want := err.Error(), "call: read: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got := Code(err); got != "EOF" {
+		t.Errorf("Code(): got %q, want %q", got, "EOF")
+	}
+	if got := KindOf(err); got != NotFound {
+		t.Errorf("KindOf(): got %q, want %q", got, NotFound)
+	}
+	if got := Fields(err); got["id"] != "7" {
+		t.Errorf("Fields(): got %v, want id=7", got)
+	}
+	if got := ToRPCError(err).Code; got != RPCServerError {
+		t.Errorf("ToRPCError().Code: got %d, want %d", got, RPCServerError)
+	}
+	if got := fmt.Sprintf("%+v", FromRPCError(&obj)); !strings.HasPrefix(got, "read: EOF\njsonrpc_code=-32000\n\tgithub.com/peakle/errors.TestFromRPCError ") {
+		t.Errorf("%%+v: got %q", got)
+	}
+}
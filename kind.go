@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Kind classifies an error by the broad category of failure it represents,
+// independently of its message or code.
+type Kind string
+
+// The kinds of error known to this package. Applications are free to define
+// their own kinds in addition to these.
+const (
+	Unknown          Kind = ""
+	Internal         Kind = "internal"
+	InvalidArgument  Kind = "invalid_argument"
+	NotFound         Kind = "not_found"
+	AlreadyExists    Kind = "already_exists"
+	Conflict         Kind = "conflict"
+	PermissionDenied Kind = "permission_denied"
+	Unauthenticated  Kind = "unauthenticated"
+	Unavailable      Kind = "unavailable"
+	DeadlineExceeded Kind = "deadline_exceeded"
+	Canceled         Kind = "canceled"
+)
+
+// String returns the kind as a string, "unknown" for the zero Kind.
+func (k Kind) String() string {
+	if k == Unknown {
+		return "unknown"
+	}
+	return string(k)
+}
+
+// WithKind annotates err with kind.
+// If err is nil, WithKind returns nil.
+func WithKind(err error, kind Kind) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withKind{
+		cause: err,
+		kind:  kind,
+	}))
+}
+
+// KindOf returns the outermost Kind attached to err's chain, or Unknown if
+// there is none. An error value carries a kind if it implements the
+// following interface and returns a kind other than Unknown:
+//
+//	type kinder interface {
+//	       Kind() errors.Kind
+//	}
+func KindOf(err error) Kind {
+	type kinder interface {
+		Kind() Kind
+	}
+
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if k, ok := err.(kinder); ok && k.Kind() != Unknown {
+			return k.Kind()
+		}
+		err = Unwrap(err)
+	}
+	return Unknown
+}
+
+type withKind struct {
+	cause error
+	kind  Kind
+}
+
+func (w *withKind) Error() string { return redact(w.cause.Error()) }
+func (w *withKind) Cause() error  { return w.cause }
+func (w *withKind) Kind() Kind    { return w.kind }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withKind) Unwrap() error { return w.cause }
+
+func (w *withKind) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, "kind="+w.kind.String())
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestWithKindNil(t *testing.T) {
+	if got := WithKind(nil, NotFound); got != nil {
+		t.Errorf("WithKind(nil, NotFound): got %#v, expected nil", got)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Kind
+	}{
+		{nil, Unknown},
+		{io.EOF, Unknown},
+		{WithKind(io.EOF, NotFound), NotFound},
+		{Wrap(WithKind(io.EOF, NotFound), "lookup"), NotFound},
+		{WithKind(WithKind(io.EOF, NotFound), Internal), Internal},
+	}
+
+	for i, tt := range tests {
+		if got := KindOf(tt.err); got != tt.want {
+			t.Errorf("test %d: KindOf(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestFormatWithKind(t *testing.T) {
+	tests := []struct {
+		err    error
+		format string
+		want   string
+	}{
+		{WithKind(io.EOF, NotFound), "%s", "EOF"},
+		{WithKind(io.EOF, NotFound), "%+v", "EOF\nkind=not_found"},
+		{WithKind(io.EOF, Unknown), "%+v", "EOF\nkind=unknown"},
+	}
+
+	for i, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.err); got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%q, err): got %q, want %q", i+1, tt.format, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// WrapLazy is like Wrap, but the message is produced by calling message the
+// first time the error is formatted, rather than when it is wrapped. It
+// suits messages that are expensive to build, such as dumps of large values,
+// which would otherwise be computed even if the error is then discarded.
+// A nil message adds no message. If err is nil, WrapLazy returns nil and
+// message is never called.
+func WrapLazy(err error, message func() string) error {
+	if err == nil {
+		return nil
+	}
+	w := &withLazyMessage{
+		cause: err,
+		fn:    message,
+	}
+	return created(transform(err, &withStack{
+		w,
+		callers(),
+	}))
+}
+
+// WrapfLazy is like Wrapf, but args is called to produce the arguments for
+// format the first time the error is formatted. See WrapLazy.
+// If err is nil, WrapfLazy returns nil and args is never called.
+func WrapfLazy(err error, format string, args func() []interface{}) error {
+	if err == nil {
+		return nil
+	}
+	w := &withLazyMessage{
+		cause: err,
+		fn:    func() string { return fmt.Sprintf(format, args()...) },
+	}
+	return created(transform(err, &withStack{
+		w,
+		callers(),
+	}))
+}
+
+// withLazyMessage is a withMessage whose message is computed on demand.
+type withLazyMessage struct {
+	cause error
+	fn    func() string
+	once  sync.Once
+	msg   string
+}
+
+// message returns the message, computing it on first use.
+func (w *withLazyMessage) message() string {
+	w.once.Do(func() {
+		if w.fn != nil {
+			w.msg = w.fn()
+		}
+		w.fn = nil
+	})
+	return w.msg
+}
+
+func (w *withLazyMessage) Error() string {
+	if w.message() == "" {
+		return redact(w.cause.Error())
+	}
+	return redact(prefixMessage(w.message(), w.cause.Error()))
+}
+
+func (w *withLazyMessage) Cause() error { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withLazyMessage) Unwrap() error { return w.cause }
+
+func (w *withLazyMessage) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, redact(w.message()))
+			return
+		}
+		fallthrough
+	case 's', 'q':
+		io.WriteString(s, w.Error())
+	}
+}
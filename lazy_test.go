@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestWrapLazy(t *testing.T) {
+	calls := 0
+	message := func() string {
+		calls++
+		return "expensive"
+	}
+
+	if err := WrapLazy(nil, message); err != nil {
+		t.Errorf("WrapLazy(nil): got %v, want nil", err)
+	}
+	err := WrapLazy(io.EOF, message)
+	if calls != 0 {
+		t.Fatalf("WrapLazy(): message computed %d times before formatting", calls)
+	}
+	for i := 0; i < 2; i++ {
+		if got, want := err.Error(), "expensive: EOF"; got != want {
+			t.Errorf("Error(): got %q, want %q", got, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("WrapLazy(): message computed %d times, want 1", calls)
+	}
+	want := "^EOF\nexpensive\ngithub.com/peakle/errors.TestWrapLazy\n\t.+/lazy_test.go:\\d+\n"
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestWrapfLazy(t *testing.T) {
+	calls := 0
+	args := func() []interface{} {
+		calls++
+		return []interface{}{42}
+	}
+
+	if err := WrapfLazy(nil, "item %d", args); err != nil {
+		t.Errorf("WrapfLazy(nil): got %v, want nil", err)
+	}
+	err := WrapfLazy(io.EOF, "item %d", args)
+	if calls != 0 {
+		t.Fatalf("WrapfLazy(): arguments computed %d times before formatting", calls)
+	}
+	if got, want := fmt.Sprint(err), "item 42: EOF"; got != want {
+		t.Errorf("fmt.Sprint(err): got %q, want %q", got, want)
+	}
+	if !Is(err, io.EOF) {
+		t.Error("Is(WrapfLazy(), io.EOF): got false, want true")
+	}
+}
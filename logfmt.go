@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Logfmt returns err as a single line of logfmt key=value pairs, for log
+// systems that require single line records:
+//
+//	msg="read config: EOF" code=CONFIG path=/etc/app.conf stack="app.loadConfig config.go:42 > main.main main.go:12"
+//
+// The pairs are the message of err, its code and kind, its fields in key
+// order and the stack trace recorded closest to its root cause, from the
+// innermost frame out, followed by the build information enabled by
+// SetBuildInfo; those err does not carry are left out. Logfmt
+// returns "" if err is nil.
+func Logfmt(err error) string {
+	if err == nil {
+		return ""
+	}
+	var b strings.Builder
+	writeLogfmt(&b, "msg", err.Error())
+	if code := Code(err); code != "" {
+		writeLogfmt(&b, "code", code)
+	}
+	if kind := KindOf(err); kind != Unknown {
+		writeLogfmt(&b, "kind", kind.String())
+	}
+	fields := exportFields(err)
+	for _, k := range sortedKeys(fields) {
+		writeLogfmt(&b, k, redact(fmt.Sprint(fields[k])))
+	}
+	if st := originStack(err); len(st) > 0 && offlineSymbolization() {
+		writeLogfmt(&b, "stack_encoded", EncodeStack(st))
+	} else if len(st) > 0 {
+		var frames []string
+		for _, r := range frameRuns(len(st), func(i int) Frame { return st[i] }) {
+			frame := r.frame.name() + " " + r.frame.String()
+			if r.count > 1 {
+				frame += " (× " + strconv.Itoa(r.count) + ")"
+			}
+			frames = append(frames, frame)
+		}
+		writeLogfmt(&b, "stack", strings.Join(frames, " > "))
+	}
+	if build := currentBuild(); build != nil {
+		if build.Module != "" {
+			writeLogfmt(&b, "build.module", build.Module)
+		}
+		if build.Version != "" {
+			writeLogfmt(&b, "build.version", build.Version)
+		}
+		if build.Revision != "" {
+			writeLogfmt(&b, "build.revision", build.Revision)
+		}
+		if build.Dirty {
+			writeLogfmt(&b, "build.dirty", "true")
+		}
+	}
+	return b.String()
+}
+
+// writeLogfmt writes the key=value pair to b, preceded by a space unless it
+// is the first pair, quoting value if needed.
+func writeLogfmt(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	if value == "" || strings.IndexFunc(value, needsLogfmtQuote) >= 0 {
+		value = strconv.Quote(value)
+	}
+	b.WriteString(value)
+}
+
+func needsLogfmtQuote(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar || !unicode.IsPrint(r)
+}
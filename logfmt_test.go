@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestLogfmt(t *testing.T) {
+	f := NewFrame("app.loadConfig", "/src/app/config.go", 42)
+	g := NewFrame("main.main", "/src/app/main.go", 12)
+	withFrames := func(err error, frames ...Frame) error {
+		st := stack{pcs: make([]uintptr, len(frames))}
+		for i, f := range frames {
+			st.pcs[i] = uintptr(f)
+		}
+		return &withStack{err, &st}
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, "msg=EOF"},
+		{WithMessage(io.EOF, ""), `msg=": EOF"`},
+		{
+			WithKind(WithCode(WithFields(WithMessage(withFrames(io.EOF, f, g), "read config"), map[string]interface{}{
+				"path": "/etc/app.conf",
+				"user": `J. "Doe"`,
+				"n":    3,
+			}), "CONFIG"), NotFound),
+			`msg="read config: EOF" code=CONFIG kind=not_found n=3 path=/etc/app.conf user="J. \"Doe\"" ` +
+				`stack="app.loadConfig config.go:42 > main.main main.go:12"`,
+		},
+		{withFrames(Join(io.EOF, io.ErrUnexpectedEOF), f, f, g), `msg="EOF\nunexpected EOF" stack="app.loadConfig config.go:42 (× 2) > main.main main.go:12"`},
+		{WithField(io.EOF, "empty", ""), `msg=EOF empty=""`},
+	}
+
+	for i, tt := range tests {
+		if got := Logfmt(tt.err); got != tt.want {
+			t.Errorf("test %d: Logfmt():\n got: %s\nwant: %s", i+1, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package errors
+
+// Map rebuilds err's chain applying fn to each of its layers, from the
+// innermost out, as when redacting, translating or normalizing the errors
+// received from other components. The innermost error is replaced by the
+// result of fn. Each layer added by this package, such as by Wrap or
+// WithCode, is then rebuilt around the error replacing the one it wraps,
+// keeping its own message, stack trace and attachments, and replaced in
+// turn by the result of fn. The errors joined by Join are mapped one by one.
+// A layer added by another package cannot be rebuilt, so it is passed to fn
+// as the innermost error, and the errors it wraps are left as they are.
+// If fn returns nil, the layer it was given is kept.
+//
+// Map returns nil if err is nil, and err unchanged if fn is nil.
+func Map(err error, fn func(error) error) error {
+	if err == nil || fn == nil {
+		return err
+	}
+	if j, ok := err.(*joinError); ok {
+		errs := make([]error, len(j.errs))
+		for i, e := range j.errs {
+			errs[i] = Map(e, fn)
+		}
+		err = Join(errs...)
+	} else if cause, rewrap := layer(err); rewrap != nil {
+		err = rewrap(Map(cause, fn))
+	}
+	if e := fn(err); e != nil {
+		return e
+	}
+	return err
+}
+
+// layer returns the error wrapped by err and a function rebuilding err
+// around another error, if err is a layer added by this package, or a nil
+// function otherwise.
+func layer(err error) (cause error, rewrap func(error) error) {
+	switch e := err.(type) {
+	case *withStack:
+		return e.error, func(c error) error { return &withStack{c, e.stack} }
+	case *withMessage:
+		return e.cause, func(c error) error { return &withMessage{cause: c, msg: e.msg} }
+	case *withLazyMessage:
+		return e.cause, func(c error) error { return &withLazyMessage{cause: c, fn: e.message} }
+	case *withFields:
+		return e.cause, func(c error) error { return &withFields{cause: c, fields: e.fields} }
+	case *withCode:
+		return e.cause, func(c error) error { return &withCode{cause: c, code: e.code} }
+	case *withKind:
+		return e.cause, func(c error) error { return &withKind{cause: c, kind: e.kind} }
+	case *withSeverity:
+		return e.cause, func(c error) error { return &withSeverity{cause: c, severity: e.severity} }
+	case *withHTTPStatus:
+		return e.cause, func(c error) error { return &withHTTPStatus{cause: c, status: e.status} }
+	case *templateError:
+		if e.cause == nil {
+			return nil, nil
+		}
+		return e.cause, func(c error) error { return &templateError{tmpl: e.tmpl, msg: e.msg, cause: c, stack: e.stack} }
+	case *withCaller:
+		return e.cause, func(c error) error { return &withCaller{cause: c, frame: e.frame} }
+	case *withFormatter:
+		return e.cause, func(c error) error { return &withFormatter{cause: c, formatter: e.formatter} }
+	case *withStackChain:
+		return e.cause, func(c error) error { return &withStackChain{cause: c, stack: e.stack} }
+	case *withGoroutines:
+		return e.cause, func(c error) error { return &withGoroutines{cause: c, dump: e.dump, truncated: e.truncated} }
+	case *withTime:
+		return e.cause, func(c error) error { return &withTime{cause: c, time: e.time} }
+	case *translated:
+		return e.cause, func(c error) error { return &translated{domain: e.domain, cause: c} }
+	case *withRetryable:
+		return e.cause, func(c error) error { return &withRetryable{cause: c, retryable: e.retryable} }
+	case *withExitCode:
+		return e.cause, func(c error) error { return &withExitCode{cause: c, code: e.code} }
+	case *withCollapsed:
+		return e.cause, func(c error) error { return &withCollapsed{cause: c, wraps: e.wraps} }
+	case typedCoder:
+		return e.Cause(), e.withCause
+	}
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	if got := Map(nil, func(err error) error { return err }); got != nil {
+		t.Errorf("Map(nil): got %v, want nil", got)
+	}
+
+	translate := func(err error) error {
+		if err == io.EOF {
+			return fmt.Errorf("end of file")
+		}
+		return nil
+	}
+	lower := func(err error) error {
+		if code := Code(err); code != strings.ToLower(code) {
+			if _, ok := err.(*withCode); ok {
+				return WithCode(err, strings.ToLower(code))
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		err  error
+		fn   func(error) error
+		msg  string
+		code string
+	}{
+		{io.EOF, translate, "end of file", ""},
+		{Wrap(WithCode(io.EOF, "EOF"), "read"), translate, "read: end of file", "EOF"},
+		{WithMessage(WrapLazy(io.EOF, func() string { return "lazy" }), "outer"), translate, "outer: lazy: end of file", ""},
+		{Join(io.EOF, WithMessage(io.EOF, "b")), translate, "end of file\nb: end of file", ""},
+		{fmt.Errorf("foreign: %w", io.EOF), translate, "foreign: EOF", ""},
+		{Wrap(WithCode(io.EOF, "EOF"), "read"), lower, "read: EOF", "eof"},
+	}
+
+	for i, tt := range tests {
+		got := Map(tt.err, tt.fn)
+		if got.Error() != tt.msg || Code(got) != tt.code {
+			t.Errorf("test %d: Map(%q): got %q with code %q, want %q with code %q", i+1, tt.err, got, Code(got), tt.msg, tt.code)
+		}
+	}
+
+	err := Wrap(io.EOF, "read")
+	got := Map(err, translate)
+	if got.(*withStack).stack != err.(*withStack).stack {
+		t.Errorf("Map(): the stack trace of a rebuilt layer was not kept")
+	}
+	if Is(got, io.EOF) {
+		t.Errorf("Map(): got a chain still holding the replaced error")
+	}
+}
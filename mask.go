@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// defaultMaskMessage is the message of errors masked by Mask.
+const defaultMaskMessage = "internal error"
+
+// Mask returns an error that hides err from callers, for use at trust
+// boundaries. The returned error's message is "internal error" and it does
+// not unwrap to err, so neither Error, Cause, Unwrap, Is nor As expose the
+// masked chain; it is only printed, in full, by the %+v verb, so that it
+// still reaches the logs. Mask records a stack trace at the point it was
+// called. If err is nil, Mask returns nil.
+func Mask(err error) error {
+	if err == nil {
+		return nil
+	}
+	return created(&masked{
+		msg:    defaultMaskMessage,
+		hidden: err,
+		stack:  callers(),
+	})
+}
+
+// MaskWith is like Mask, but the returned error's message is publicMsg.
+// If err is nil, MaskWith returns nil.
+func MaskWith(err error, publicMsg string) error {
+	if err == nil {
+		return nil
+	}
+	return created(&masked{
+		msg:    publicMsg,
+		hidden: err,
+		stack:  callers(),
+	})
+}
+
+// masked is an error hiding another.
+type masked struct {
+	msg    string
+	hidden error
+	*stack
+}
+
+func (m *masked) Error() string { return redact(m.msg) }
+
+func (m *masked) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, m) {
+				return
+			}
+			io.WriteString(s, m.Error())
+			m.stack.Format(s, verb)
+			io.WriteString(s, "\nmasked: ")
+			io.WriteString(s, redactf("%+v", m.hidden))
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, m.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", m.Error())
+	}
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	if err := Mask(nil); err != nil {
+		t.Errorf("Mask(nil): got %v, want nil", err)
+	}
+	if err := MaskWith(nil, "unavailable"); err != nil {
+		t.Errorf("MaskWith(nil): got %v, want nil", err)
+	}
+
+	secret := Wrap(WithCode(io.EOF, "DB_EOF"), "query users table")
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Mask(secret), "internal error"},
+		{MaskWith(secret, "service unavailable"), "service unavailable"},
+		{Wrap(Mask(secret), "handler"), "handler: internal error"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if Is(tt.err, io.EOF) {
+			t.Errorf("test %d: Is(err, io.EOF): got true, want false", i+1)
+		}
+		if got := Code(tt.err); got != "" {
+			t.Errorf("test %d: Code(): got %q, want masked", i+1, got)
+		}
+		if got := Cause(tt.err); got == io.EOF {
+			t.Errorf("test %d: Cause(): exposes masked error", i+1)
+		}
+	}
+
+	want := "^internal error\ngithub.com/peakle/errors.TestMask\n(?s:.*)\nmasked: EOF\ncode=DB_EOF\nquery users table\n"
+	if got := fmt.Sprintf("%+v", Mask(secret)); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+}
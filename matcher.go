@@ -0,0 +1,88 @@
+package errors
+
+// A Matcher is a predicate over errors. Matchers are composed with All, Any
+// and Not to express rules such as routing or alerting decisions
+// declaratively:
+//
+//	page := errors.All(
+//		errors.SeverityAtLeast(errors.SeverityCritical),
+//		errors.Not(errors.IsKind(errors.Canceled)),
+//	)
+//	if errors.Match(err, page) {
+//		...
+//	}
+type Matcher func(error) bool
+
+// Match reports whether err satisfies m. A nil Matcher is satisfied by no
+// error.
+func Match(err error, m Matcher) bool { return m != nil && m(err) }
+
+// All returns a Matcher satisfied by errors satisfying every one of ms.
+// All() is satisfied by every error.
+func All(ms ...Matcher) Matcher {
+	return func(err error) bool {
+		for _, m := range ms {
+			if !Match(err, m) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any returns a Matcher satisfied by errors satisfying at least one of ms.
+// Any() is satisfied by no error.
+func Any(ms ...Matcher) Matcher {
+	return func(err error) bool {
+		for _, m := range ms {
+			if Match(err, m) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a Matcher satisfied by errors not satisfying m.
+func Not(m Matcher) Matcher {
+	return func(err error) bool { return !Match(err, m) }
+}
+
+// Wraps returns a Matcher satisfied by errors for which Is(err, target)
+// reports true.
+func Wraps(target error) Matcher {
+	return func(err error) bool { return Is(err, target) }
+}
+
+// IsKind returns a Matcher satisfied by errors of the supplied kind, as
+// reported by KindOf.
+func IsKind(kind Kind) Matcher {
+	return func(err error) bool { return err != nil && KindOf(err) == kind }
+}
+
+// HasCode returns a Matcher satisfied by errors with the supplied code, as
+// reported by Code.
+func HasCode(code string) Matcher {
+	return func(err error) bool { return err != nil && Code(err) == code }
+}
+
+// HasField returns a Matcher satisfied by errors carrying a field with the
+// supplied key, as reported by Fields.
+func HasField(key string) Matcher {
+	return func(err error) bool {
+		_, ok := Fields(err)[key]
+		return ok
+	}
+}
+
+// SeverityAtLeast returns a Matcher satisfied by errors whose severity, as
+// reported by SeverityOf, is at least s.
+func SeverityAtLeast(s Severity) Matcher {
+	return func(err error) bool { return err != nil && SeverityOf(err) >= s }
+}
+
+// MessageContains returns a Matcher satisfied by errors for which
+// HasMessage(err, substr) reports true.
+func MessageContains(substr string) Matcher {
+	return func(err error) bool { return HasMessage(err, substr) }
+}
@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	err := WithField(WithKind(WithCode(Wrap(io.EOF, "read"), "READ"), NotFound), "tenant", "acme")
+	critical := WithSeverity(New("disk full"), SeverityCritical)
+
+	tests := []struct {
+		err  error
+		m    Matcher
+		want bool
+	}{
+		{err, All(IsKind(NotFound), HasField("tenant")), true},
+		{err, All(IsKind(NotFound), HasField("user")), false},
+		{err, All(), true},
+		{err, Any(), false},
+		{err, Any(IsKind(Internal), HasCode("READ")), true},
+		{err, Any(IsKind(Internal), HasCode("WRITE")), false},
+		{err, Not(IsKind(Internal)), true},
+		{err, Wraps(io.EOF), true},
+		{err, Wraps(io.ErrUnexpectedEOF), false},
+		{err, MessageContains("read"), true},
+		{err, SeverityAtLeast(SeverityWarning), false},
+		{critical, SeverityAtLeast(SeverityWarning), true},
+		{critical, All(SeverityAtLeast(SeverityCritical), Not(IsKind(Canceled))), true},
+		{nil, IsKind(Unknown), false},
+		{nil, HasCode(""), false},
+		{nil, HasField("tenant"), false},
+		{nil, Not(SeverityAtLeast(SeverityUnknown)), true},
+	}
+
+	for i, tt := range tests {
+		if got := Match(tt.err, tt.m); got != tt.want {
+			t.Errorf("test %d: Match(): got %v, want %v", i+1, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// FlattenMessages returns the messages of the layers of err's chain, from
+// outermost to innermost, separated by sep. Each layer contributes only its
+// own message, without the text of the error it wraps, and layers that add
+// no message of their own, such as those added by WithStack, are skipped:
+//
+//	err := errors.Wrap(errors.Wrap(os.ErrPermission, "reading file"), "loading config")
+//	errors.FlattenMessages(err, " > ") // "loading config > reading file > permission denied"
+//
+// When a layer wraps several errors, as those returned by Join do, the
+// flattened messages of each of them are separated by "; ". A chain that
+// loops back on itself is truncated with "[cycle]" once the loop is detected.
+// FlattenMessages returns "" if err is nil.
+func FlattenMessages(err error, sep string) string {
+	return flattenMessages(err, sep, nil)
+}
+
+// flattenMessages implements FlattenMessages. The errors wrapping several
+// errors that lead to err are in path.
+func flattenMessages(err error, sep string, path []error) string {
+	var msgs []string
+	var c cycle
+	for err != nil {
+		if c.seen(err) {
+			msgs = append(msgs, cycleMarker)
+			break
+		}
+		if u, ok := err.(interface{ Unwrap() []error }); ok {
+			if onPath(err, path) {
+				msgs = append(msgs, cycleMarker)
+				break
+			}
+			path = append(path, err)
+			var branches []string
+			for _, e := range u.Unwrap() {
+				if msg := flattenMessages(e, sep, path); msg != "" {
+					branches = append(branches, msg)
+				}
+			}
+			if len(branches) > 0 {
+				msgs = append(msgs, strings.Join(branches, "; "))
+			}
+			break
+		}
+		if msg := ownMessage(err); msg != "" {
+			msgs = append(msgs, msg)
+		}
+		err = Unwrap(err)
+	}
+	return strings.Join(msgs, sep)
+}
+
+// Message returns the message of the outermost layer of err's chain that
+// has one, without the text of the error it wraps:
+//
+//	errors.Message(errors.Wrap(io.EOF, "reading body")) // "reading body"
+//
+// If that layer wraps several errors, as those returned by Join do, Message
+// returns its full message. Message returns "" if err is nil.
+func Message(err error) string {
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if _, ok := err.(interface{ Unwrap() []error }); ok {
+			return err.Error()
+		}
+		if msg := ownMessage(err); msg != "" {
+			return msg
+		}
+		err = Unwrap(err)
+	}
+	return ""
+}
+
+// HasMessage reports whether the own message of any layer of err's chain,
+// as returned by FlattenMessages, contains substr. Unlike matching against
+// err.Error(), a match cannot straddle the messages of two layers.
+func HasMessage(err error, substr string) bool {
+	return anyMessage(err, func(msg string) bool { return strings.Contains(msg, substr) })
+}
+
+// MessageMatches reports whether the own message of any layer of err's
+// chain, as returned by FlattenMessages, matches re. It reports false if re
+// is nil.
+func MessageMatches(err error, re *regexp.Regexp) bool {
+	if re == nil {
+		return false
+	}
+	return anyMessage(err, re.MatchString)
+}
+
+// anyMessage reports whether match returns true for the own message of any
+// layer of err's chain.
+func anyMessage(err error, match func(string) bool) bool {
+	found := false
+	Walk(err, func(err error) bool {
+		if _, ok := err.(interface{ Unwrap() []error }); ok {
+			return true // the wrapped errors are visited next
+		}
+		if msg := ownMessage(err); msg != "" && match(msg) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// ownMessage returns the part of err's message that err adds to the message
+// of the error it wraps. Wrapping errors are assumed to format as
+// "message: cause", the convention of this package and of fmt.Errorf.
+func ownMessage(err error) string {
+	msg := err.Error()
+	cause := Unwrap(err)
+	if cause == nil {
+		return msg
+	}
+	c := cause.Error()
+	switch {
+	case msg == c:
+		return ""
+	case strings.HasSuffix(msg, ": "+c):
+		return msg[:len(msg)-len(c)-2]
+	}
+	return msg
+}
+
+// dedupMessages is non-zero while repeated messages are collapsed.
+var dedupMessages int32
+
+// SetMessageDedup sets whether Error collapses messages repeated by
+// consecutive layers of a chain. It is disabled by default. When enabled, a
+// layer whose message is already the start of the message of the error it
+// wraps, or which already ends with that message, does not repeat it:
+//
+//	err := errors.Wrap(errors.Wrap(io.EOF, "open file"), "open file")
+//	err.Error() // "open file: EOF" rather than "open file: open file: EOF"
+func SetMessageDedup(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&dedupMessages, v)
+}
+
+// prefixMessage returns the message of an error annotating cause with msg.
+func prefixMessage(msg, cause string) string {
+	if atomic.LoadInt32(&dedupMessages) != 0 {
+		switch {
+		case cause == msg, strings.HasPrefix(cause, msg+": "):
+			return cause
+		case strings.HasSuffix(msg, cause):
+			return msg
+		}
+	}
+	return msg + ": " + cause
+}
@@ -0,0 +1,126 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestFlattenMessages(t *testing.T) {
+	tests := []struct {
+		err  error
+		sep  string
+		want string
+	}{
+		{nil, " > ", ""},
+		{io.EOF, " > ", "EOF"},
+		{New("root"), " > ", "root"},
+		{WithStack(io.EOF), " > ", "EOF"},
+		{Wrap(Wrap(os.ErrPermission, "reading file"), "loading config"), " > ", "loading config > reading file > permission denied"},
+		{WithField(Wrap(io.EOF, "read"), "k", "v"), ": ", "read: EOF"},
+		{fmt.Errorf("outer: %w", Wrap(io.EOF, "inner")), "/", "outer/inner/EOF"},
+		{fmt.Errorf("opaque %w", io.EOF), "/", "opaque EOF/EOF"},
+		{Wrap(Join(Wrap(io.EOF, "a"), New("b")), "batch"), " > ", "batch > a > EOF; b"},
+	}
+
+	for i, tt := range tests {
+		if got := FlattenMessages(tt.err, tt.sep); got != tt.want {
+			t.Errorf("test %d: FlattenMessages(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, "EOF"},
+		{New("root"), "root"},
+		{Wrap(io.EOF, "reading body"), "reading body"},
+		{WithStack(Wrap(io.EOF, "reading body")), "reading body"},
+		{WithCode(WithStack(io.EOF), "EOF"), "EOF"},
+		{fmt.Errorf("outer: %w", io.EOF), "outer"},
+		{Mask(Wrap(io.EOF, "secret")), "internal error"},
+		{Join(io.EOF, New("b")), "EOF\nb"},
+	}
+
+	for i, tt := range tests {
+		if got := Message(tt.err); got != tt.want {
+			t.Errorf("test %d: Message(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestMessageDedup(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Wrap(Wrap(io.EOF, "open file"), "open file"), "open file: EOF"},
+		{Wrap(Wrap(Wrap(io.EOF, "x"), "x"), "x"), "x: EOF"},
+		{Wrap(io.EOF, "EOF"), "EOF"},
+		{Wrapf(io.EOF, "read failed: %v", io.EOF), "read failed: EOF"},
+		{WrapLazy(Wrap(io.EOF, "read"), func() string { return "read" }), "read: EOF"},
+		{Wrap(Wrap(io.EOF, "open file"), "open"), "open: open file: EOF"},
+	}
+
+	SetMessageDedup(true)
+	defer SetMessageDedup(false)
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	SetMessageDedup(false)
+	if got, want := tests[0].err.Error(), "open file: open file: EOF"; got != want {
+		t.Errorf("Error() with dedup disabled: got %q, want %q", got, want)
+	}
+}
+
+func TestHasMessage(t *testing.T) {
+	err := Wrap(fmt.Errorf("open config: %w", Join(io.EOF, New("no such file"))), "loading")
+	tests := []struct {
+		substr string
+		want   bool
+	}{
+		{"loading", true},
+		{"open config", true},
+		{"EOF", true},
+		{"no such", true},
+		{"loading: open", false}, // straddles two layers
+		{"EOF\nno", false},
+		{"missing", false},
+	}
+
+	for i, tt := range tests {
+		if got := HasMessage(err, tt.substr); got != tt.want {
+			t.Errorf("test %d: HasMessage(err, %q): got %v, want %v", i+1, tt.substr, got, tt.want)
+		}
+	}
+	if HasMessage(nil, "") {
+		t.Error("HasMessage(nil, \"\"): got true, want false")
+	}
+}
+
+func TestMessageMatches(t *testing.T) {
+	err := Wrapf(io.EOF, "read %d bytes", 42)
+	tests := []struct {
+		re   string
+		want bool
+	}{
+		{`^read \d+ bytes$`, true},
+		{`^EOF$`, true},
+		{`bytes: EOF`, false},
+	}
+
+	for i, tt := range tests {
+		if got := MessageMatches(err, regexp.MustCompile(tt.re)); got != tt.want {
+			t.Errorf("test %d: MessageMatches(err, %q): got %v, want %v", i+1, tt.re, got, tt.want)
+		}
+	}
+}
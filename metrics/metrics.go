@@ -0,0 +1,101 @@
+// Package metrics exposes counts of errors, broken down by code, kind and
+// severity, as a Prometheus collector.
+//
+// Errors are counted with Observe where the program handles them, such as
+// where they are logged or turned into responses, by which point they carry
+// the code, kind and severity they were annotated with on their way up:
+//
+//	func main() {
+//	        if err := metrics.Register(prometheus.DefaultRegisterer); err != nil {
+//	                log.Fatal(err)
+//	        }
+//	        ...
+//	}
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	        if err := serve(w, r); err != nil {
+//	                metrics.Observe(err)
+//	                ...
+//	        }
+//	}
+//
+// Alternatively, CountCreated arranges for errors to be counted as they are
+// created. An error is then counted once, when it first acquires a stack
+// trace; wrapping it further does not count it again, so the code, kind and
+// severity it is counted under are those it carries at that point, often
+// none.
+package metrics
+
+import (
+	"sync"
+
+	"github.com/peakle/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector counts errors by code, kind and severity.
+// It implements prometheus.Collector.
+type Collector struct {
+	total     *prometheus.CounterVec
+	countOnce sync.Once
+}
+
+// NewCollector returns a Collector whose counter is named
+// <namespace>_errors_total, or errors_total if namespace is empty.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		total: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Number of errors, partitioned by code, kind and severity.",
+		}, []string{"code", "kind", "severity"}),
+	}
+}
+
+// Observe counts err. Nil errors are ignored.
+func (c *Collector) Observe(err error) {
+	if err == nil {
+		return
+	}
+	c.total.WithLabelValues(
+		errors.Code(err),
+		errors.KindOf(err).String(),
+		errors.SeverityOf(err).String(),
+	).Inc()
+}
+
+// CountCreated arranges for every error created by package errors from then
+// on to be counted by c, once, as it first acquires a stack trace. It is safe
+// to call more than once.
+func (c *Collector) CountCreated() {
+	c.countOnce.Do(func() { errors.RegisterHook(c.observeCreated) })
+}
+
+// observeCreated counts err if it is the first error in its chain to carry
+// a stack trace.
+func (c *Collector) observeCreated(err error) {
+	if errors.FirstStack(err) {
+		c.Observe(err)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) { c.total.Describe(ch) }
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) { c.total.Collect(ch) }
+
+// Default is the Collector used by Observe and Register.
+var Default = NewCollector("")
+
+// Observe counts err with the Default collector.
+func Observe(err error) { Default.Observe(err) }
+
+// CountCreated arranges for every error created by package errors from then
+// on to be counted by the Default collector, as Collector.CountCreated does.
+func CountCreated() { Default.CountCreated() }
+
+// Register registers the Default collector with reg.
+func Register(reg prometheus.Registerer) error {
+	return reg.Register(Default)
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"io"
+	"testing"
+
+	"github.com/peakle/errors"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func count(t *testing.T, c *Collector, code, kind, severity string) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := c.total.WithLabelValues(code, kind, severity).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestObserve(t *testing.T) {
+	c := NewCollector("test")
+	c.Observe(nil)
+	c.Observe(io.EOF)
+	c.Observe(errors.WithKind(errors.WithCode(io.EOF, "EOF"), errors.NotFound))
+	c.Observe(errors.WithSeverity(errors.WithKind(errors.WithCode(io.EOF, "EOF"), errors.NotFound), errors.SeverityWarning))
+
+	tests := []struct {
+		code, kind, severity string
+		want                 float64
+	}{
+		{"", "unknown", "unknown", 1},
+		{"EOF", "not_found", "unknown", 1},
+		{"EOF", "not_found", "warning", 1},
+	}
+	for _, tt := range tests {
+		if got := count(t, c, tt.code, tt.kind, tt.severity); got != tt.want {
+			t.Errorf("count(%q, %q, %q): got %v, want %v", tt.code, tt.kind, tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestCountCreated(t *testing.T) {
+	c := NewCollector("created")
+	c.CountCreated()
+	c.CountCreated()
+
+	err := errors.New("error")
+	errors.WithCode(errors.Wrap(err, "wrapped"), "CODE")
+	errors.WithMessage(io.EOF, "no stack")
+
+	if got := count(t, c, "", "unknown", "unknown"); got != 1 {
+		t.Errorf("count(): got %v, want 1", got)
+	}
+}
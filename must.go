@@ -0,0 +1,27 @@
+package errors
+
+// Must returns v if err is nil, and otherwise panics with err annotated with
+// the stack trace of the call to Must. It is intended for initialisation code
+// where a panic is acceptable but losing the origin of the failure is not:
+//
+//	var tmpl = errors.Must(template.ParseFiles("index.html"))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(created(transform(err, &withStack{
+			err,
+			callers(),
+		})))
+	}
+	return v
+}
+
+// Must2 is like Must for functions returning two values and an error.
+func Must2[T, U any](v T, u U, err error) (T, U) {
+	if err != nil {
+		panic(created(transform(err, &withStack{
+			err,
+			callers(),
+		})))
+	}
+	return v, u
+}
@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func mustPanic(t *testing.T, fn func()) (err error) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("no panic")
+		}
+		err = r.(error)
+	}()
+	fn()
+	return nil
+}
+
+func TestMust(t *testing.T) {
+	if got := Must(42, nil); got != 42 {
+		t.Errorf("Must(42, nil): got %v, want 42", got)
+	}
+	if a, b := Must2("a", 1, nil); a != "a" || b != 1 {
+		t.Errorf("Must2(\"a\", 1, nil): got %v, %v", a, b)
+	}
+
+	for _, fn := range []func(){
+		func() { Must(0, io.EOF) },
+		func() { Must2(0, "", io.EOF) },
+	} {
+		err := mustPanic(t, fn)
+		if !Is(err, io.EOF) {
+			t.Errorf("Must(): panicked with %v, want %v", err, io.EOF)
+		}
+		want := "^EOF\ngithub.com/peakle/errors.TestMust.func\\d+\n\t.+/must_test.go:\\d+\n"
+		if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+			t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+		}
+	}
+}
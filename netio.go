@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"io"
+	"net"
+	"os"
+	"syscall"
+)
+
+// IsEOF reports whether any error in err's chain is io.EOF or
+// io.ErrUnexpectedEOF.
+func IsEOF(err error) bool {
+	return Is(err, io.EOF) || Is(err, io.ErrUnexpectedEOF)
+}
+
+// IsClosed reports whether any error in err's chain reports the use of a
+// closed network connection, file or pipe: net.ErrClosed, os.ErrClosed or
+// io.ErrClosedPipe.
+func IsClosed(err error) bool {
+	return Is(err, net.ErrClosed) || Is(err, os.ErrClosed) || Is(err, io.ErrClosedPipe)
+}
+
+// IsConnRefused reports whether any error in err's chain reports a
+// connection refused by its peer, syscall.ECONNREFUSED.
+func IsConnRefused(err error) bool {
+	return Is(err, syscall.ECONNREFUSED)
+}
+
+// IsDNSFailure reports whether any error in err's chain is a *net.DNSError,
+// reporting the failure to resolve a host name.
+func IsDNSFailure(err error) bool {
+	var dns *net.DNSError
+	return As(err, &dns)
+}
+
+// IsBrokenPipe reports whether any error in err's chain reports a write to
+// a pipe or connection closed by its reader, syscall.EPIPE.
+func IsBrokenPipe(err error) bool {
+	return Is(err, syscall.EPIPE)
+}
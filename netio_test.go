@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestNetIOPredicates(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	pipe := &os.PathError{Op: "write", Path: "|1", Err: syscall.EPIPE}
+	dns := &net.DNSError{Err: "no such host", Name: "nowhere.invalid", IsNotFound: true}
+
+	type predicates struct{ eof, closed, refused, dns, pipe bool }
+	tests := []struct {
+		err  error
+		want predicates
+	}{
+		{nil, predicates{}},
+		{io.EOF, predicates{eof: true}},
+		{Wrap(io.ErrUnexpectedEOF, "read body"), predicates{eof: true}},
+		{fmt.Errorf("accept: %w", net.ErrClosed), predicates{closed: true}},
+		{Wrap(os.ErrClosed, "write"), predicates{closed: true}},
+		{io.ErrClosedPipe, predicates{closed: true}},
+		{Wrap(refused, "connect db"), predicates{refused: true}},
+		{Wrap(dns, "resolve"), predicates{dns: true}},
+		{Join(io.EOF, pipe), predicates{eof: true, pipe: true}},
+		{syscall.ECONNRESET, predicates{}},
+	}
+
+	for i, tt := range tests {
+		got := predicates{
+			eof:     IsEOF(tt.err),
+			closed:  IsClosed(tt.err),
+			refused: IsConnRefused(tt.err),
+			dns:     IsDNSFailure(tt.err),
+			pipe:    IsBrokenPipe(tt.err),
+		}
+		if got != tt.want {
+			t.Errorf("test %d: %v: got %+v, want %+v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
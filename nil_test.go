@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestNilSafety(t *testing.T) {
+	var (
+		tmpl    *Template
+		builder *Builder
+		group   *Group
+		tr      *Translator
+		first   *FirstError
+		rep     *Reporter
+		frames  *Frames
+		st      *stack
+	)
+	tests := []struct {
+		name string
+		fn   func() interface{}
+		want interface{}
+	}{
+		{"Chain", func() interface{} { return len(Chain(nil)) }, 0},
+		{"Root", func() interface{} { return Root(nil) }, nil},
+		{"Cause", func() interface{} { return Cause(nil) }, nil},
+		{"Code", func() interface{} { return Code(nil) }, ""},
+		{"KindOf", func() interface{} { return KindOf(nil) }, Unknown},
+		{"SeverityOf", func() interface{} { return SeverityOf(nil) }, SeverityUnknown},
+		{"HTTPStatus", func() interface{} { return HTTPStatus(nil) }, 200},
+		{"Fields", func() interface{} { return len(Fields(nil)) }, 0},
+		{"Message", func() interface{} { return Message(nil) }, ""},
+		{"Logfmt", func() interface{} { return Logfmt(nil) }, ""},
+		{"IsPanic", func() interface{} { return IsPanic(nil) }, false},
+		{"Match", func() interface{} { return Match(io.EOF, nil) }, false},
+		{"All", func() interface{} { return All(nil)(io.EOF) }, false},
+		{"Not", func() interface{} { return Not(nil)(io.EOF) }, true},
+		{"MessageMatches", func() interface{} { return MessageMatches(io.EOF, nil) }, false},
+		{"WrapLazy", func() interface{} { return WrapLazy(io.EOF, nil).Error() }, "EOF"},
+		{"Template.Error", func() interface{} { return tmpl.Error() }, ""},
+		{"Template.Code", func() interface{} { return tmpl.Code() }, ""},
+		{"Template.Kind", func() interface{} { return tmpl.Kind() }, Unknown},
+		{"Template.HTTPStatus", func() interface{} { return tmpl.HTTPStatus() }, 0},
+		{"Template.WithKind", func() interface{} { return tmpl.WithKind(NotFound) == nil }, true},
+		{"Template.New", func() interface{} { return Code(tmpl.New()) }, ""},
+		{"Builder.Err", func() interface{} { return builder.Code("CODE").Field("k", "v").Err() }, nil},
+		{"Frames.Next", func() interface{} { f, more := frames.Next(); return f == FrameInfo{} && !more }, true},
+		{"Group.Go", func() interface{} { group.Go(func() error { return io.EOF }); return group.Wait() }, nil},
+		{"Group.WaitAll", func() interface{} { return group.WaitAll() }, nil},
+		{"Map", func() interface{} { return Map(io.EOF, nil) }, io.EOF},
+		{"Filter", func() interface{} { return Filter(io.EOF, nil) }, io.EOF},
+		{"Partition", func() interface{} { _, rest := Partition(io.EOF, nil); return rest }, nil},
+		{"MostSevereBy", func() interface{} { return MostSevereBy(Join(io.EOF), nil) }, io.EOF},
+		{"Translator.Register", func() interface{} { return tr.Register(io.EOF, io.ErrUnexpectedEOF) == nil }, true},
+		{"Translator.Translate", func() interface{} { return tr.Translate(io.EOF) }, io.EOF},
+		{"FirstError.Set", func() interface{} { return first.Set(io.EOF) }, false},
+		{"FirstError.Err", func() interface{} { return first.Err() }, nil},
+		{"Reporter.Observe", func() interface{} { rep.Observe(io.EOF); rep.Stop(); return nil }, nil},
+		{"stack.StackTrace", func() interface{} { return len(st.StackTrace()) }, 0},
+		{"stack.Format", func() interface{} { return fmt.Sprintf("%+v", st) }, ""},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s: panics: %v", tt.name, r)
+				}
+			}()
+			if got := tt.fn(); got != tt.want {
+				t.Errorf("%s: got %#v, want %#v", tt.name, got, tt.want)
+			}
+		}()
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/peakle/errors/otel
+
+go 1.21
+
+require (
+	github.com/peakle/errors v0.0.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	go.opentelemetry.io/otel v1.28.0 // indirect
+	golang.org/x/xerrors v0.0.0-20240903120638-7835f813f4da // indirect
+)
+
+replace github.com/peakle/errors => ../
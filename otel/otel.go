@@ -0,0 +1,44 @@
+// Package otel attaches OpenTelemetry trace and span IDs to the errors
+// created with a context by package github.com/peakle/errors, so that every
+// logged error can be joined with its distributed trace.
+//
+// Call Install once during program initialisation:
+//
+//	func main() {
+//		otel.Install()
+//		...
+//	}
+//
+// From then on errors created by errors.NewCtx, errors.WrapCtx and the other
+// context-aware constructors under a context carrying a valid span context
+// carry the fields errors.TraceIDField and errors.SpanIDField.
+package otel
+
+import (
+	"context"
+	"sync"
+
+	"github.com/peakle/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var installOnce sync.Once
+
+// Install registers Extract as a context extractor of package errors.
+// Calling Install more than once has no further effect.
+func Install() {
+	installOnce.Do(func() { errors.RegisterContextExtractor(Extract) })
+}
+
+// Extract returns the trace and span IDs of the span context carried by ctx,
+// or nil if ctx carries no valid span context.
+func Extract(ctx context.Context) map[string]interface{} {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return map[string]interface{}{
+		errors.TraceIDField: sc.TraceID().String(),
+		errors.SpanIDField:  sc.SpanID().String(),
+	}
+}
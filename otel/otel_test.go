@@ -0,0 +1,32 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/peakle/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExtract(t *testing.T) {
+	if got := Extract(context.Background()); got != nil {
+		t.Errorf("Extract(background): got %v, want nil", got)
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01, 0x02},
+		SpanID:  trace.SpanID{0x03},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	Install()
+	Install()
+	err := errors.WrapCtx(ctx, io.EOF, "read")
+	if got, want := errors.TraceID(err), "01020000000000000000000000000000"; got != want {
+		t.Errorf("TraceID(): got %q, want %q", got, want)
+	}
+	if got, want := errors.Fields(err)[errors.SpanIDField], "0300000000000000"; got != want {
+		t.Errorf("Fields()[SpanIDField]: got %v, want %q", got, want)
+	}
+}
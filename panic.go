@@ -0,0 +1,195 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// Recover converts a panic in progress into an error stored in *errp.
+// It must be deferred directly:
+//
+//	func load() (err error) {
+//		defer errors.Recover(&err)
+//		...
+//	}
+//
+// The resulting error records the stack trace of the goroutine at the point
+// of the panic rather than at the deferred call, and wraps the panic value
+// if it is itself an error. If there is no panic in progress, Recover
+// leaves *errp unchanged.
+func Recover(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if rp, ok := r.(*repanicked); ok {
+		*errp = rp.p
+		return
+	}
+	*errp = created(&panicError{
+		value: r,
+		stack: panicCallers(),
+	})
+}
+
+// panicError is an error created from a recovered panic.
+type panicError struct {
+	value interface{}
+	*stack
+}
+
+func (p *panicError) Error() string {
+	return redact("panic: " + fmt.Sprint(p.value))
+}
+
+// Unwrap returns the panic value if it is an error.
+func (p *panicError) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+func (p *panicError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, p) {
+				return
+			}
+			io.WriteString(s, p.Error())
+			p.stack.Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, p.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", p.Error())
+	}
+}
+
+// FromPanic converts v, a value returned by recover, into an error, for use
+// in recovery code that cannot use Recover:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			log.Print(errors.FromPanic(r))
+//		}
+//	}()
+//
+// Like the errors of Recover, the error records the stack trace of the
+// goroutine at the point of the panic, wraps v if it is an error and formats
+// other values with fmt.Sprint, and is reported as a panic by IsPanic.
+// If v is an error returned by FromPanic or Recover, or was panicked by
+// Repanic, FromPanic returns that error unchanged. FromPanic returns nil if
+// v is nil.
+func FromPanic(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	switch p := v.(type) {
+	case *panicError:
+		return p
+	case *repanicked:
+		return p.p
+	}
+	return created(&panicError{
+		value: v,
+		stack: panicCallers(),
+	})
+}
+
+// IsPanic reports whether err, or any error in its chain, was converted
+// from a panic by Recover or FromPanic.
+func IsPanic(err error) bool {
+	var p *panicError
+	return As(err, &p)
+}
+
+// PanicValue returns the value passed to panic that the outermost error
+// converted from a panic by Recover or FromPanic in err's chain was created
+// from, and whether there is such an error.
+func PanicValue(err error) (interface{}, bool) {
+	var p *panicError
+	if !As(err, &p) {
+		return nil, false
+	}
+	return p.value, true
+}
+
+// Repanic panics again with the panic that the outermost error converted
+// from a panic by Recover or FromPanic in err's chain was created from, for
+// code which recovers panics but must let some of them through:
+//
+//	defer func() {
+//		if err := errors.FromPanic(recover()); err != nil {
+//			if v, _ := errors.PanicValue(err); v == http.ErrAbortHandler {
+//				errors.Repanic(err)
+//			}
+//			log.Print(err)
+//		}
+//	}()
+//
+// The message of the panic, as printed by the runtime if the panic is not
+// recovered, holds the panic value followed by the stack trace recorded
+// when the panic was first recovered, which the stack trace printed by the
+// runtime, that of the call to Repanic, would otherwise hide. A panic of
+// Repanic recovered by Recover or FromPanic gives back the original error.
+// If err holds no error converted from a panic, Repanic panics with err
+// itself. Repanic does nothing if err is nil.
+func Repanic(err error) {
+	if err == nil {
+		return
+	}
+	var p *panicError
+	if !As(err, &p) {
+		panic(err)
+	}
+	panic(&repanicked{p})
+}
+
+// repanicked is the value Repanic panics with.
+type repanicked struct {
+	p *panicError
+}
+
+func (r *repanicked) Error() string {
+	return redact(fmt.Sprint(r.p.value) + " [recovered]" + fmt.Sprintf("%+v", r.p.stack))
+}
+
+// Unwrap returns the error converted from the original panic.
+func (r *repanicked) Unwrap() error { return r.p }
+
+// panicCallers returns the stack of the panicking goroutine as seen from a
+// deferred function called during the panic, trimmed so that it starts at
+// the frame which panicked.
+func panicCallers() *stack {
+	if !StackCaptureEnabled() {
+		return &stack{}
+	}
+	// Leave room for the frames of the panic machinery trimmed below.
+	const slack = 16
+	pcs := make([]uintptr, int(atomic.LoadInt32(&stackDepth))+slack)
+	n := runtime.Callers(3, pcs)
+	pcs = pcs[:n]
+
+	for i := len(pcs) - 1; i >= 0; i-- {
+		if Frame(pcs[i]).name() == "runtime.gopanic" {
+			pcs = pcs[i+1:]
+			break
+		}
+	}
+	// Runtime errors such as nil dereferences and out of range indexes
+	// panic from within helpers of package runtime.
+	for len(pcs) > 1 && strings.HasPrefix(Frame(pcs[0]).name(), "runtime.") {
+		pcs = pcs[1:]
+	}
+	if depth := int(atomic.LoadInt32(&stackDepth)); len(pcs) > depth {
+		pcs = pcs[:depth]
+	}
+	st := &stack{pcs: filterFrames(pcs)}
+	recordGoroutine(st)
+	return st
+}
@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func panicWith(v interface{}) {
+	panic(v)
+}
+
+func recoverFrom(fn func()) (err error) {
+	defer Recover(&err)
+	fn()
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		fn   func()
+		want string
+	}{
+		{func() { panicWith("boom") }, "panic: boom"},
+		{func() { panicWith(io.EOF) }, "panic: EOF"},
+		{func() {
+			var m map[string]int
+			m["x"]++
+		}, "panic: assignment to entry in nil map"},
+	}
+
+	for i, tt := range tests {
+		err := recoverFrom(tt.fn)
+		if err == nil {
+			t.Fatalf("test %d: Recover(): got nil error", i+1)
+		}
+		if got := err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	if err := recoverFrom(func() {}); err != nil {
+		t.Errorf("Recover() without panic: got %v, want nil", err)
+	}
+	if err := recoverFrom(func() { panicWith(io.EOF) }); !Is(err, io.EOF) {
+		t.Errorf("Is(Recover(), io.EOF): got false, want true")
+	}
+}
+
+func TestRecoverStack(t *testing.T) {
+	err := recoverFrom(func() { panicWith("boom") })
+	got := fmt.Sprintf("%+v", err)
+	want := "^panic: boom\n" +
+		"github.com/peakle/errors.panicWith\n" +
+		"\t.+/panic_test.go:11\n" +
+		"github.com/peakle/errors.TestRecoverStack.func1\n"
+	if !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+
+	err = recoverFrom(func() {
+		var p *struct{ x int }
+		_ = p.x
+	})
+	want = "^panic: runtime error: invalid memory address or nil pointer dereference\n" +
+		"github.com/peakle/errors.TestRecoverStack.func2\n"
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func fromPanicIn(fn func()) (err error) {
+	defer func() {
+		err = FromPanic(recover())
+	}()
+	fn()
+	return nil
+}
+
+func TestFromPanic(t *testing.T) {
+	if err := FromPanic(nil); err != nil {
+		t.Errorf("FromPanic(nil): got %v, want nil", err)
+	}
+	if err := fromPanicIn(func() {}); err != nil {
+		t.Errorf("FromPanic() without panic: got %v, want nil", err)
+	}
+
+	err := fromPanicIn(func() { panicWith(io.EOF) })
+	if got, want := err.Error(), "panic: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(FromPanic(), io.EOF): got false, want true")
+	}
+	if !IsPanic(Wrap(err, "handle")) {
+		t.Errorf("IsPanic(Wrap(FromPanic())): got false, want true")
+	}
+	if IsPanic(io.EOF) || IsPanic(nil) {
+		t.Errorf("IsPanic(): got true for an error not from a panic")
+	}
+	if again := fromPanicIn(func() { panic(err) }); again != err {
+		t.Errorf("FromPanic() of a repanicked error: got %v, want %v", again, err)
+	}
+
+	want := "^panic: 42\n" +
+		"github.com/peakle/errors.panicWith\n" +
+		"\t.+/panic_test.go:11\n"
+	if got := fmt.Sprintf("%+v", fromPanicIn(func() { panicWith(42) })); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestRepanic(t *testing.T) {
+	orig := recoverFrom(func() { panicWith("boom") })
+	if v, ok := PanicValue(Wrap(orig, "handle")); v != "boom" || !ok {
+		t.Errorf("PanicValue(): got %v, %t, want boom, true", v, ok)
+	}
+	if v, ok := PanicValue(io.EOF); v != nil || ok {
+		t.Errorf("PanicValue(io.EOF): got %v, %t, want nil, false", v, ok)
+	}
+
+	var v interface{}
+	func() {
+		defer func() { v = recover() }()
+		Repanic(Wrap(orig, "handle"))
+	}()
+	msg := v.(error).Error()
+	want := "^boom \\[recovered\\]\n" +
+		"github.com/peakle/errors.panicWith\n" +
+		"\t.+/panic_test.go:11\n"
+	if !regexp.MustCompile(want).MatchString(msg) {
+		t.Errorf("Repanic(): got panic message %q, want %q", msg, want)
+	}
+
+	other := fromPanicIn(func() { panicWith(42) })
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{Wrap(orig, "handle"), orig},
+		{other, other},
+	}
+	for i, tt := range tests {
+		if got := recoverFrom(func() { Repanic(tt.err) }); got != tt.want {
+			t.Errorf("test %d: Recover() of Repanic(): got %v, want %v", i+1, got, tt.want)
+		}
+		if got := fromPanicIn(func() { Repanic(tt.err) }); got != tt.want {
+			t.Errorf("test %d: FromPanic() of Repanic(): got %v, want %v", i+1, got, tt.want)
+		}
+	}
+
+	if err := recoverFrom(func() { Repanic(io.EOF) }); !Is(err, io.EOF) || !IsPanic(err) {
+		t.Errorf("Repanic(io.EOF): got %v", err)
+	}
+	Repanic(nil)
+}
@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// PprofLabels returns the profiler labels carried by ctx, as set by
+// pprof.Do or pprof.WithLabels, as fields, or nil if there are none. It can
+// be registered with RegisterContextExtractor, so that errors created under
+// a labelled context by NewCtx, WrapCtx and the like carry its labels:
+//
+//	errors.RegisterContextExtractor(errors.PprofLabels)
+//
+//	pprof.Do(ctx, pprof.Labels("tenant", tenant), func(ctx context.Context) {
+//		err = errors.WrapCtx(ctx, err, "sync") // Fields(err)["tenant"] == tenant
+//	})
+func PprofLabels(ctx context.Context) map[string]interface{} {
+	var fields map[string]interface{}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		if fields == nil {
+			fields = make(map[string]interface{})
+		}
+		fields[key] = value
+		return true
+	})
+	return fields
+}
+
+// WithPprofLabels annotates err with the profiler labels carried by ctx as
+// fields, tying the error back to the dimensions, such as request or tenant,
+// used in profiles. If ctx carries no labels, WithPprofLabels returns err
+// unchanged. If err is nil, WithPprofLabels returns nil.
+func WithPprofLabels(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	fields := PprofLabels(ctx)
+	if len(fields) == 0 {
+		return err
+	}
+	return created(transform(err, newWithFields(err, fields)))
+}
@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestPprofLabels(t *testing.T) {
+	if got := PprofLabels(context.Background()); got != nil {
+		t.Errorf("PprofLabels(context.Background()): got %v, want nil", got)
+	}
+	if got := WithPprofLabels(context.Background(), io.EOF); got != io.EOF {
+		t.Errorf("WithPprofLabels(): got %#v, want io.EOF unchanged", got)
+	}
+
+	want := map[string]interface{}{"tenant": "acme", "route": "/sync"}
+	pprof.Do(context.Background(), pprof.Labels("tenant", "acme", "route", "/sync"), func(ctx context.Context) {
+		if got := PprofLabels(ctx); !reflect.DeepEqual(got, want) {
+			t.Errorf("PprofLabels(): got %v, want %v", got, want)
+		}
+		err := WithPprofLabels(ctx, io.EOF)
+		if got := Fields(err); !reflect.DeepEqual(got, want) {
+			t.Errorf("Fields(WithPprofLabels()): got %v, want %v", got, want)
+		}
+		if WithPprofLabels(ctx, nil) != nil {
+			t.Errorf("WithPprofLabels(ctx, nil): got non-nil error")
+		}
+	})
+}
+
+func TestPprofLabelsExtractor(t *testing.T) {
+	defer resetContextExtractors()
+	RegisterContextExtractor(PprofLabels)
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("tenant", "acme"))
+	err := WrapCtx(ctx, io.EOF, "sync")
+	if got := Fields(err)["tenant"]; got != "acme" {
+		t.Errorf("Fields(WrapCtx())[%q]: got %v, want %q", "tenant", got, "acme")
+	}
+}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// redactedValue is the text substituted for the value of a sensitive field.
+const redactedValue = "[REDACTED]"
+
+var redactor atomic.Value // of func(string) string
+
+// SetRedactor installs fn as the package-wide redactor. The redactor is
+// applied to every message and field value rendered by Error, Format and
+// any other output produced by this package, so that tokens and personally
+// identifiable information can be scrubbed before they reach a log.
+//
+// Because wrappers render the text of the errors they wrap, the redactor may
+// be applied more than once to the same text and must therefore be idempotent.
+// Passing nil removes the redactor.
+func SetRedactor(fn func(string) string) {
+	redactor.Store(fn)
+}
+
+// redact applies the installed redactor, if any, to s.
+func redact(s string) string {
+	fn, _ := redactor.Load().(func(string) string)
+	if fn == nil {
+		return s
+	}
+	return fn(s)
+}
+
+// redactf formats according to a format specifier and redacts the result.
+func redactf(format string, args ...interface{}) string {
+	return redact(fmt.Sprintf(format, args...))
+}
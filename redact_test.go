@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetRedactor(t *testing.T) {
+	SetRedactor(func(s string) string {
+		return strings.ReplaceAll(s, "hunter2", "***")
+	})
+	defer SetRedactor(nil)
+
+	tests := []struct {
+		err    error
+		format string
+		want   string
+	}{
+		{New("password hunter2"), "%s", "password ***"},
+		{Errorf("password %s", "hunter2"), "%v", "password ***"},
+		{Wrap(io.EOF, "token hunter2"), "%s", "token ***: EOF"},
+		{WithMessage(fmt.Errorf("dial hunter2"), "connect"), "%v", "connect: dial ***"},
+		{WithStack(fmt.Errorf("dial hunter2")), "%s", "dial ***"},
+		{WithField(io.EOF, "token", "hunter2"), "%+v", "EOF\ntoken=***"},
+		{Wrap(New("hunter2"), "hunter2"), "%q", `"***: ***"`},
+	}
+
+	for i, tt := range tests {
+		got := fmt.Sprintf(tt.format, tt.err)
+		if got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%q, err): got: %q, want: %q", i+1, tt.format, got, tt.want)
+		}
+	}
+
+	got := fmt.Sprintf("%+v", Wrap(fmt.Errorf("dial hunter2"), "connect"))
+	if strings.Contains(got, "hunter2") {
+		t.Errorf("fmt.Sprintf(%%+v, err): leaked secret: %q", got)
+	}
+}
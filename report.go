@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// WriteReport writes a crash report for err to w: a diagnostic bundle fit
+// to attach to a bug report, made of
+//
+//   - the time, Go version, platform and process ID,
+//   - the module, version and VCS revision of the program, if known,
+//   - err in detail, as by %+v, with its chain and stack traces,
+//   - the fields of err, as returned by Fields,
+//   - the memory statistics of the process, as returned by
+//     runtime.ReadMemStats, and
+//   - a dump of the stacks of all its goroutines, limited to 1 MiB.
+//
+// The report is written as plain text, section by section. Reading the
+// memory statistics and dumping the goroutines briefly stop the world.
+// WriteReport returns the first error encountered writing to w.
+func WriteReport(w io.Writer, err error) error {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	fmt.Fprintf(bw, "crash report\n\ntime: %s\ngo: %s %s/%s\npid: %d\n",
+		time.Now().Format(time.RFC3339Nano), runtime.Version(), runtime.GOOS, runtime.GOARCH, os.Getpid())
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if b := newBuildStamp(info); b != nil {
+			fmt.Fprintf(bw, "module: %s %s\n", b.Module, b.Version)
+			if b.Revision != "" {
+				fmt.Fprintf(bw, "revision: %s", b.Revision)
+				if b.Dirty {
+					io.WriteString(bw, " (dirty)")
+				}
+				io.WriteString(bw, "\n")
+			}
+		}
+	}
+
+	io.WriteString(bw, "\nerror:\n")
+	if err == nil {
+		io.WriteString(bw, "<nil>")
+	} else {
+		WriteError(bw, err)
+	}
+	io.WriteString(bw, "\n")
+
+	if fields := exportFields(err); len(fields) > 0 {
+		io.WriteString(bw, "\nfields:\n")
+		for _, k := range sortedKeys(fields) {
+			fmt.Fprintf(bw, "%s=%s\n", k, redact(fmt.Sprint(fields[k])))
+		}
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	fmt.Fprintf(bw, "\nmemory:\nalloc=%d total_alloc=%d sys=%d heap_objects=%d num_gc=%d goroutines=%d\n",
+		m.Alloc, m.TotalAlloc, m.Sys, m.HeapObjects, m.NumGC, runtime.NumGoroutine())
+
+	dump, truncated := goroutineDump()
+	io.WriteString(bw, "\ngoroutines:\n")
+	bw.Write(dump)
+	if truncated {
+		io.WriteString(bw, "\n... truncated\n")
+	}
+
+	bw.Flush()
+	return cw.err
+}
+
+// WriteReportFile writes a crash report for err, as by WriteReport, to a
+// new file in dir named after the current time, such as
+// "crash-20060102T150405Z-123456.txt", and returns its path. If dir is
+// empty, the file is created in the default directory for temporary files,
+// as returned by os.TempDir.
+func WriteReportFile(dir string, err error) (string, error) {
+	name := "crash-" + time.Now().UTC().Format("20060102T150405Z") + "-*.txt"
+	f, ferr := os.CreateTemp(dir, name)
+	if ferr != nil {
+		return "", ferr
+	}
+	werr := WriteReport(f, err)
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		os.Remove(f.Name())
+		return "", werr
+	}
+	return f.Name(), nil
+}
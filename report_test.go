@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteReport(t *testing.T) {
+	err := WithField(Wrap(io.EOF, "read config"), "path", "/etc/app.conf")
+	var buf bytes.Buffer
+	if werr := WriteReport(&buf, err); werr != nil {
+		t.Fatalf("WriteReport(): %v", werr)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"crash report\n\ntime: ",
+		"\npid: ",
+		"\nerror:\nEOF\nread config\ngithub.com/peakle/errors.TestWriteReport\n",
+		"\nfields:\npath=/etc/app.conf\n",
+		"\nmemory:\nalloc=",
+		"\ngoroutines:\ngoroutine ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteReport(): got %q, want it to contain %q", got, want)
+		}
+	}
+
+	if werr := WriteReport(&errWriter{n: 10}, err); werr != io.ErrShortWrite {
+		t.Errorf("WriteReport(): got error %v, want %v", werr, io.ErrShortWrite)
+	}
+}
+
+func TestWriteReportFile(t *testing.T) {
+	dir := t.TempDir()
+	path, err := WriteReportFile(dir, New("crash"))
+	if err != nil {
+		t.Fatalf("WriteReportFile(): %v", err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "crash-") {
+		t.Errorf("WriteReportFile(): got path %q, want a crash- file in %q", path, dir)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(b), "\nerror:\ncrash\n") {
+		t.Errorf("WriteReportFile(): got report %q, %v", b, err)
+	}
+
+	if _, err := WriteReportFile(filepath.Join(dir, "missing"), New("crash")); err == nil {
+		t.Errorf("WriteReportFile() in a missing directory: got no error")
+	}
+}
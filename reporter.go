@@ -0,0 +1,221 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// reporterQueue is the number of errors a Reporter holds before it has
+// aggregated them; errors observed while it is full are dropped.
+const reporterQueue = 1024
+
+// Digest summarises the errors observed by a Reporter during a window of
+// time.
+type Digest struct {
+	Start, End time.Time
+
+	// Total is the number of errors observed, including dropped ones.
+	Total int
+
+	// Dropped is the number of errors observed but not aggregated into
+	// groups, as they were created faster than the Reporter aggregated them.
+	Dropped int
+
+	// Groups are the errors aggregated by fingerprint, by decreasing count.
+	Groups []DigestGroup
+}
+
+// DigestGroup is a group of errors of a Digest sharing a fingerprint.
+type DigestGroup struct {
+	// Fingerprint is the fingerprint of the errors, as returned by
+	// Fingerprint.
+	Fingerprint string
+
+	// Code is the error code of the errors, if any.
+	Code string
+
+	// Message is the message of the first error of the group.
+	Message string
+
+	// Count is the number of errors in the group.
+	Count int
+
+	// Stack is the stack trace recorded closest to the root cause of the
+	// first error of the group.
+	Stack StackTrace
+}
+
+// String renders d as a header line followed by a line per group, giving
+// its count, code, message and the innermost frame of its stack trace:
+//
+//	errors: 42 in 1m0s, 2 groups, 0 dropped
+//	    40 DB_TIMEOUT query users: timeout (app.(*Store).Users store.go:87)
+//	     2 - open /etc/app.conf: permission denied (app.loadConfig config.go:42)
+func (d Digest) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "errors: %d in %v, %d groups, %d dropped", d.Total, d.End.Sub(d.Start).Round(time.Millisecond), len(d.Groups), d.Dropped)
+	for _, g := range d.Groups {
+		code := g.Code
+		if code == "" {
+			code = "-"
+		}
+		fmt.Fprintf(&b, "\n%6d %s %s", g.Count, code, strings.Replace(g.Message, "\n", "; ", -1))
+		if len(g.Stack) > 0 {
+			fmt.Fprintf(&b, " (%s %v)", g.Stack[0].name(), g.Stack[0])
+		}
+	}
+	return b.String()
+}
+
+// Reporter aggregates the errors created by this package in the background
+// and periodically reports a Digest of them, as lightweight error analytics
+// for services without an error tracking system:
+//
+//	r := errors.NewReporter(time.Minute, func(d errors.Digest) {
+//		if d.Total > 0 {
+//			log.Print(d)
+//		}
+//	})
+//	defer r.Stop()
+//
+// An error is observed once, when it first acquires a stack trace; wrapping
+// it further does not observe it again. The fingerprint, code, message and
+// stack trace of an error are taken as it is observed and handed off to the
+// goroutine of the Reporter, which aggregates them, so that the Reporter
+// holds no reference to the error itself; if it falls behind, errors are
+// counted as dropped rather than slowing their creators down.
+//
+// The methods of a nil *Reporter do nothing.
+type Reporter struct {
+	fn       func(Digest)
+	observed chan DigestGroup
+	dropped  int64 // atomically
+	remove   func()
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewReporter returns a Reporter which calls fn, on a goroutine of its own,
+// with a Digest of the errors observed during each interval, even when there
+// are none. If fn is nil, the digests are written to standard error, except
+// those of intervals without errors. The Reporter observes errors until Stop
+// is called.
+func NewReporter(interval time.Duration, fn func(Digest)) *Reporter {
+	if fn == nil {
+		fn = func(d Digest) {
+			if d.Total > 0 {
+				io.WriteString(stderr, d.String()+"\n")
+			}
+		}
+	}
+	r := &Reporter{
+		fn:       fn,
+		observed: make(chan DigestGroup, reporterQueue),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	r.remove = addHook(r.created)
+	go r.run(interval)
+	return r
+}
+
+// Observe adds err to the current digest of r, whether or not it has been
+// observed as it was created. Nil errors are ignored.
+func (r *Reporter) Observe(err error) {
+	if err == nil || r == nil {
+		return
+	}
+	g := DigestGroup{
+		Fingerprint: Fingerprint(err),
+		Code:        Code(err),
+		Message:     err.Error(),
+		Stack:       originStack(err),
+		Count:       1,
+	}
+	select {
+	case r.observed <- g:
+	default:
+		atomic.AddInt64(&r.dropped, 1)
+	}
+}
+
+// Stop stops r observing errors, and reports the digest of the errors
+// observed since the last one before returning. Calling Stop more than once
+// has no further effect.
+func (r *Reporter) Stop() {
+	if r == nil {
+		return
+	}
+	r.stopOnce.Do(func() {
+		r.remove()
+		close(r.stop)
+	})
+	<-r.done
+}
+
+// created observes err if it is the first error in its chain to carry a
+// stack trace.
+func (r *Reporter) created(err error) {
+	if FirstStack(err) {
+		r.Observe(err)
+	}
+}
+
+// run aggregates the observed errors, reporting a digest every interval
+// until r is stopped.
+func (r *Reporter) run(interval time.Duration) {
+	defer close(r.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	d := Digest{Start: time.Now()}
+	index := make(map[string]int)
+	flush := func(now time.Time) {
+	drain:
+		for {
+			select {
+			case g := <-r.observed:
+				d.add(g, index)
+			default:
+				break drain
+			}
+		}
+		d.End = now
+		d.Dropped = int(atomic.SwapInt64(&r.dropped, 0))
+		d.Total += d.Dropped
+		sort.SliceStable(d.Groups, func(i, j int) bool { return d.Groups[i].Count > d.Groups[j].Count })
+		r.fn(d)
+		d = Digest{Start: now}
+		index = make(map[string]int)
+	}
+	for {
+		select {
+		case g := <-r.observed:
+			d.add(g, index)
+		case now := <-ticker.C:
+			flush(now)
+		case <-r.stop:
+			flush(time.Now())
+			return
+		}
+	}
+}
+
+// add aggregates g, a group of the errors observed, into d, index holding
+// the position of each group in d.Groups by fingerprint.
+func (d *Digest) add(g DigestGroup, index map[string]int) {
+	i, ok := index[g.Fingerprint]
+	if !ok {
+		index[g.Fingerprint] = len(d.Groups)
+		d.Groups = append(d.Groups, g)
+	} else {
+		d.Groups[i].Count += g.Count
+	}
+	d.Total += g.Count
+}
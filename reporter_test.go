@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReporter(t *testing.T) {
+	var digests []Digest
+	r := NewReporter(time.Hour, func(d Digest) { digests = append(digests, d) })
+	for i := 0; i < 3; i++ {
+		_ = WithMessage(Wrap(WithCode(io.ErrClosedPipe, "DB"), "query"), "load") // observed once
+	}
+	_ = WithStack(io.EOF)
+	r.Observe(io.ErrUnexpectedEOF)
+	r.Observe(nil)
+	r.Stop()
+	r.Stop()
+	_ = New("after stop")
+
+	if len(digests) != 1 {
+		t.Fatalf("got %d digests, want 1", len(digests))
+	}
+	d := digests[0]
+	if d.Total != 5 || d.Dropped != 0 || d.End.Before(d.Start) {
+		t.Errorf("got digest of %d errors, %d dropped, from %v to %v", d.Total, d.Dropped, d.Start, d.End)
+	}
+	tests := []struct {
+		code    string
+		message string
+		count   int
+		stack   bool
+	}{
+		{"DB", "query: io: read/write on closed pipe", 3, true},
+		{"", "EOF", 1, true},
+		{"", "unexpected EOF", 1, false},
+	}
+	if len(d.Groups) != len(tests) {
+		t.Fatalf("got %d groups, want %d: %v", len(d.Groups), len(tests), d)
+	}
+	for i, tt := range tests {
+		g := d.Groups[i]
+		if g.Code != tt.code || g.Message != tt.message || g.Count != tt.count || (len(g.Stack) > 0) != tt.stack {
+			t.Errorf("test %d: got group %q %q ×%d with %d frames, want %q %q ×%d", i+1,
+				g.Code, g.Message, g.Count, len(g.Stack), tt.code, tt.message, tt.count)
+		}
+	}
+	for _, want := range []string{"errors: 5 in ", "3 groups, 0 dropped", "\n     3 DB query: io: read/write on closed pipe (github.com/peakle/errors.TestReporter reporter_test.go:", "\n     1 - unexpected EOF"} {
+		if !strings.Contains(d.String(), want) {
+			t.Errorf("String(): got %q, want it to contain %q", d.String(), want)
+		}
+	}
+}
+
+func TestReporterDefault(t *testing.T) {
+	defer func(w io.Writer) { stderr = w }(stderr)
+	var buf bytes.Buffer
+	stderr = &buf
+
+	r := NewReporter(time.Millisecond, nil)
+	time.Sleep(5 * time.Millisecond)
+	if buf.Len() != 0 {
+		t.Errorf("empty digests: got %q", buf.String())
+	}
+	r.Observe(io.EOF)
+	r.Stop()
+	if got := buf.String(); !strings.HasPrefix(got, "errors: 1 in ") || !strings.HasSuffix(got, "\n     1 - EOF\n") {
+		t.Errorf("got %q", got)
+	}
+}
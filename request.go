@@ -0,0 +1,42 @@
+package errors
+
+// The field keys under which request and trace identifiers are recorded.
+const (
+	RequestIDField = "request_id"
+	TraceIDField   = "trace_id"
+	SpanIDField    = "span_id"
+)
+
+// WithRequestID annotates err with the ID of the request during which it
+// occurred, recorded as the field RequestIDField.
+// If err is nil, WithRequestID returns nil.
+func WithRequestID(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withFields{
+		cause:  err,
+		fields: []field{{key: RequestIDField, value: id}},
+	}))
+}
+
+// RequestID returns the request ID attached to err's chain, or the empty
+// string if there is none.
+func RequestID(err error) string {
+	return stringField(err, RequestIDField)
+}
+
+// TraceID returns the distributed trace ID attached to err's chain, or the
+// empty string if there is none. Trace IDs are attached by the context
+// extractors registered with RegisterContextExtractor, such as the one
+// installed by package github.com/peakle/errors/otel.
+func TraceID(err error) string {
+	return stringField(err, TraceIDField)
+}
+
+// stringField returns the outermost value of the field key in err's chain,
+// if it is a string.
+func stringField(err error, key string) string {
+	s, _ := Fields(err)[key].(string)
+	return s
+}
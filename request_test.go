@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{WithRequestID(io.EOF, "r1"), "r1"},
+		{Wrap(WithRequestID(io.EOF, "r1"), "read"), "r1"},
+		{WithRequestID(WithRequestID(io.EOF, "r1"), "r2"), "r2"},
+		{WithField(io.EOF, RequestIDField, 42), ""},
+		{NewCtx(WithContextFields(context.Background(), map[string]interface{}{RequestIDField: "r3"}), "error"), "r3"},
+	}
+
+	for i, tt := range tests {
+		if got := RequestID(tt.err); got != tt.want {
+			t.Errorf("test %d: RequestID(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	if got := WithRequestID(nil, "r1"); got != nil {
+		t.Errorf("WithRequestID(nil, \"r1\"): got %#v, expected nil", got)
+	}
+}
+
+func TestRegisterContextExtractor(t *testing.T) {
+	type traceKey struct{}
+	RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+		id, _ := ctx.Value(traceKey{}).(string)
+		if id == "" {
+			return nil
+		}
+		return map[string]interface{}{TraceIDField: id, "source": "extractor"}
+	})
+	RegisterContextExtractor(nil)
+	defer resetContextExtractors()
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "t1")
+	ctx = WithContextFields(ctx, map[string]interface{}{"source": "context"})
+
+	err := WrapCtx(ctx, io.EOF, "read")
+	if got := TraceID(err); got != "t1" {
+		t.Errorf("TraceID(): got %q, want %q", got, "t1")
+	}
+	if got := Fields(err)["source"]; got != "context" {
+		t.Errorf("Fields()[\"source\"]: got %v, want context fields to take precedence", got)
+	}
+	if got := TraceID(NewCtx(context.Background(), "error")); got != "" {
+		t.Errorf("TraceID() without span: got %q, want \"\"", got)
+	}
+}
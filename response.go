@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxResponseExcerpt is the number of bytes of a response body FromResponse
+// records.
+const maxResponseExcerpt = 1 << 10
+
+// statusKind maps HTTP status codes to the kinds conventionally used for
+// them, the inverse of kindStatus.
+var statusKind = map[int]Kind{
+	http.StatusBadRequest:          InvalidArgument,
+	http.StatusUnauthorized:        Unauthenticated,
+	http.StatusForbidden:           PermissionDenied,
+	http.StatusNotFound:            NotFound,
+	http.StatusRequestTimeout:      DeadlineExceeded,
+	http.StatusConflict:            Conflict,
+	http.StatusUnprocessableEntity: InvalidArgument,
+	http.StatusTooManyRequests:     Unavailable,
+	http.StatusBadGateway:          Unavailable,
+	http.StatusServiceUnavailable:  Unavailable,
+	http.StatusGatewayTimeout:      DeadlineExceeded,
+	499:                            Canceled,
+}
+
+// FromResponse returns an error describing resp if its status code is not
+// 2xx, with a stack trace recorded at the point FromResponse was called, or
+// nil otherwise. The error's message holds the method and URL of the request,
+// without password or query, and the status of the response, as in
+// "GET https://api.example.com/users/7: 404 Not Found". It carries
+//
+//   - the code "HTTP_" followed by the status code, such as "HTTP_404",
+//   - the kind conventionally used for the status code, as the inverse of
+//     HTTPStatus, or Internal for other 5xx status codes,
+//   - the status code, as returned by HTTPStatus,
+//   - the fields "status", the status code, "body", up to the first KiB of
+//     the response body, and "retry_after", the delay set by the Retry-After
+//     header as a time.Duration, if any, and
+//   - the retryability, as returned by IsRetryable: true for the status
+//     codes 429 Too Many Requests and 5xx, false otherwise.
+//
+// FromResponse reads the excerpt from resp.Body, but does not close it.
+// FromResponse returns nil if resp is nil.
+func FromResponse(resp *http.Response) error {
+	if resp == nil || resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg := resp.Status
+	if msg == "" {
+		msg = strconv.Itoa(resp.StatusCode) + " " + http.StatusText(resp.StatusCode)
+	}
+	if req := resp.Request; req != nil && req.URL != nil {
+		u := *req.URL
+		u.RawQuery, u.Fragment = "", ""
+		msg = req.Method + " " + u.Redacted() + ": " + msg
+	}
+	var err error = newFundamental(msg)
+
+	fields := []field{{key: "status", value: resp.StatusCode}}
+	if resp.Body != nil {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseExcerpt))
+		if body := strings.TrimSpace(string(b)); body != "" {
+			fields = append(fields, field{key: "body", value: body})
+		}
+	}
+	if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+		fields = append(fields, field{key: "retry_after", value: d})
+	}
+	err = &withFields{cause: err, fields: fields}
+	err = &withCode{cause: err, code: "HTTP_" + strconv.Itoa(resp.StatusCode)}
+
+	kind, ok := statusKind[resp.StatusCode]
+	if !ok && resp.StatusCode >= 500 {
+		kind = Internal
+	}
+	if kind != Unknown {
+		err = &withKind{cause: err, kind: kind}
+	}
+	err = &withHTTPStatus{cause: err, status: resp.StatusCode}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return created(&withRetryable{cause: err, retryable: retryable})
+}
+
+// retryAfter returns the delay set by the value of a Retry-After header,
+// either a number of seconds or an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
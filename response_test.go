@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromResponse(t *testing.T) {
+	if got := FromResponse(nil); got != nil {
+		t.Errorf("FromResponse(nil): got %v, want nil", got)
+	}
+
+	tests := []struct {
+		status    int
+		header    http.Header
+		body      string
+		kind      Kind
+		retryable bool
+		fields    map[string]interface{}
+	}{
+		{http.StatusOK, nil, "", Unknown, false, nil},
+		{http.StatusNoContent, nil, "", Unknown, false, nil},
+		{http.StatusNotFound, nil, " no such user\n", NotFound, false, map[string]interface{}{"status": 404, "body": "no such user"}},
+		{http.StatusTeapot, nil, "", Unknown, false, map[string]interface{}{"status": 418}},
+		{http.StatusTooManyRequests, http.Header{"Retry-After": {"30"}}, "", Unavailable, true, map[string]interface{}{"status": 429, "retry_after": 30 * time.Second}},
+		{http.StatusNotImplemented, nil, strings.Repeat("x", 2000), Internal, true, map[string]interface{}{"status": 501, "body": strings.Repeat("x", 1024)}},
+	}
+
+	for i, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range tt.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+		resp, err := http.Get(srv.URL + "/users/7?token=secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = FromResponse(resp)
+		resp.Body.Close()
+		srv.Close()
+
+		if tt.status < 300 {
+			if err != nil {
+				t.Errorf("test %d: FromResponse(): got %v, want nil", i+1, err)
+			}
+			continue
+		}
+		if want := "GET " + srv.URL + "/users/7: " + resp.Status; err.Error() != want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, err.Error(), want)
+		}
+		if got, want := Code(err), "HTTP_"+resp.Status[:3]; got != want {
+			t.Errorf("test %d: Code(): got %q, want %q", i+1, got, want)
+		}
+		if got := KindOf(err); got != tt.kind {
+			t.Errorf("test %d: KindOf(): got %q, want %q", i+1, got, tt.kind)
+		}
+		if got := HTTPStatus(err); got != tt.status {
+			t.Errorf("test %d: HTTPStatus(): got %d, want %d", i+1, got, tt.status)
+		}
+		if got := IsRetryable(err); got != tt.retryable {
+			t.Errorf("test %d: IsRetryable(): got %v, want %v", i+1, got, tt.retryable)
+		}
+		if got := Fields(err); !reflect.DeepEqual(got, tt.fields) {
+			t.Errorf("test %d: Fields(): got %v, want %v", i+1, got, tt.fields)
+		}
+		if st := originStack(err); len(st) == 0 || st[0].name() != "github.com/peakle/errors.TestFromResponse" {
+			t.Errorf("test %d: got stack trace %v, want one starting in TestFromResponse", i+1, st)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0, true},
+	}
+
+	for i, tt := range tests {
+		if got, ok := retryAfter(tt.value); got != tt.want || ok != tt.ok {
+			t.Errorf("test %d: retryAfter(%q): got %v, %v, want %v, %v", i+1, tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// A RetryPolicy sets how many times and how often Retry calls a function.
+// The zero RetryPolicy makes 3 attempts without waiting.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts; zero means 3.
+	MaxAttempts int
+
+	// Delay is the time waited after the first failed attempt.
+	Delay time.Duration
+
+	// Multiplier is the factor the delay is multiplied by after each
+	// further failed attempt; zero means 2.
+	Multiplier float64
+
+	// MaxDelay caps the delay, if positive.
+	MaxDelay time.Duration
+}
+
+// Retry calls fn until it returns nil, it has been called policy.MaxAttempts
+// times, it returns an error for which IsRetryable reports false, or ctx is
+// done, waiting between attempts as set by policy.
+//
+// Retry returns nil if an attempt succeeds. Otherwise it returns the errors
+// of every attempt, as by Join, each annotated with the fields "attempt",
+// its number counting from 1, and "delay", the time waited after it,
+// followed by the error of ctx if it was done first.
+func Retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	attempts := policy.MaxAttempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	multiplier := policy.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	var errs []error
+	delay := policy.Delay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if attempt == attempts || !IsRetryable(err) {
+			errs = append(errs, attemptError(err, attempt, 0))
+			break
+		}
+		errs = append(errs, attemptError(err, attempt, delay))
+		if err := sleep(ctx, delay); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		delay = time.Duration(float64(delay) * multiplier)
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+	return Join(errs...)
+}
+
+// attemptError annotates err, returned by attempt number attempt, with its
+// number and the delay waited after it.
+func attemptError(err error, attempt int, delay time.Duration) error {
+	return &withFields{cause: err, fields: []field{
+		{key: "attempt", value: attempt},
+		{key: "delay", value: delay},
+	}}
+}
+
+// sleep waits for d, or until ctx is done, in which case it returns the
+// error of ctx.
+func sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil || d <= 0 {
+		return err
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsRetryable reports whether the failure err reports may succeed if the
+// operation is retried. The outermost error in err's chain implementing the
+// following interface decides:
+//
+//	type retryabler interface {
+//	       Retryable() bool
+//	}
+//
+// as do the errors of Permanent and Transient. Otherwise, errors reporting
+// a cancellation or an exceeded deadline, as by IsCanceled and
+// IsDeadlineExceeded, are not retryable, and other errors are. IsRetryable
+// returns false if err is nil.
+func IsRetryable(err error) bool {
+	type retryabler interface {
+		Retryable() bool
+	}
+
+	if err == nil {
+		return false
+	}
+	var c cycle
+	for e := err; e != nil && !c.seen(e); e = Unwrap(e) {
+		if r, ok := e.(retryabler); ok {
+			return r.Retryable()
+		}
+	}
+	return !IsCanceled(err) && !IsDeadlineExceeded(err)
+}
+
+// Permanent annotates err as not retryable: IsRetryable reports false for
+// it, and Retry stops at it. If err is nil, Permanent returns nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withRetryable{cause: err, retryable: false}))
+}
+
+// Transient annotates err as retryable: IsRetryable reports true for it,
+// even if an error it wraps is marked Permanent. Like the temporary errors
+// of package net, it reports true from its Temporary method.
+// If err is nil, Transient returns nil.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withRetryable{cause: err, retryable: true}))
+}
+
+// ToBackoff converts err to the conventions of a retry package which
+// retries every error except those marked by one of its functions, such as
+// backoff.Permanent of github.com/cenkalti/backoff or retry.Unrecoverable of
+// github.com/avast/retry-go, passed as permanent:
+//
+//	operation := func() error {
+//		return errors.ToBackoff(call(), backoff.Permanent)
+//	}
+//
+// ToBackoff returns permanent(err) if err is not retryable, as reported by
+// IsRetryable, and err otherwise. If err is nil, ToBackoff returns nil.
+func ToBackoff(err error, permanent func(error) error) error {
+	if err == nil || IsRetryable(err) {
+		return err
+	}
+	return permanent(err)
+}
+
+type withRetryable struct {
+	cause     error
+	retryable bool
+}
+
+func (w *withRetryable) Error() string   { return redact(w.cause.Error()) }
+func (w *withRetryable) Cause() error    { return w.cause }
+func (w *withRetryable) Retryable() bool { return w.retryable }
+func (w *withRetryable) Temporary() bool { return w.retryable }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withRetryable) Unwrap() error { return w.cause }
+
+func (w *withRetryable) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, "retryable="+strconv.FormatBool(w.retryable))
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
@@ -0,0 +1,152 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// retryable is an error reporting whether it is retryable.
+type retryable bool
+
+func (r retryable) Error() string   { return "retryable" }
+func (r retryable) Retryable() bool { return bool(r) }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, true},
+		{Wrap(context.Canceled, "query"), false},
+		{NewTimeout("query", 2, 1), false},
+		{Wrap(retryable(false), "call"), false},
+		{Wrap(retryable(true), "call"), true},
+	}
+
+	for i, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("test %d: IsRetryable(%v): got %t, want %t", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), RetryPolicy{}, func() error {
+		calls++
+		if calls < 3 {
+			return io.EOF
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Retry(): got %v after %d calls, want nil after 3", err, calls)
+	}
+
+	calls = 0
+	policy := RetryPolicy{MaxAttempts: 4, Delay: time.Millisecond, Multiplier: 3, MaxDelay: 5 * time.Millisecond}
+	err = Retry(context.Background(), policy, func() error {
+		calls++
+		return io.EOF
+	})
+	if calls != 4 {
+		t.Errorf("Retry(): got %d calls, want 4", calls)
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(err, io.EOF): got false, want true")
+	}
+	var attempts []map[string]interface{}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		attempts = append(attempts, Fields(e))
+	}
+	want := []map[string]interface{}{
+		{"attempt": 1, "delay": time.Millisecond},
+		{"attempt": 2, "delay": 3 * time.Millisecond},
+		{"attempt": 3, "delay": 5 * time.Millisecond},
+		{"attempt": 4, "delay": time.Duration(0)},
+	}
+	if !reflect.DeepEqual(attempts, want) {
+		t.Errorf("Retry(): got attempts %v, want %v", attempts, want)
+	}
+}
+
+func TestRetryStops(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), RetryPolicy{MaxAttempts: 5}, func() error {
+		calls++
+		return retryable(false)
+	})
+	if calls != 1 || !Is(err, retryable(false)) {
+		t.Errorf("Retry() with a permanent error: got %v after %d calls, want 1 call", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = Retry(ctx, RetryPolicy{MaxAttempts: 5, Delay: time.Hour}, func() error {
+		calls++
+		cancel()
+		return io.EOF
+	})
+	if calls != 1 || !Is(err, io.EOF) || !Is(err, context.Canceled) {
+		t.Errorf("Retry() with a canceled context: got %v after %d calls, want io.EOF and context.Canceled after 1 call", err, calls)
+	}
+}
+
+func TestPermanentTransient(t *testing.T) {
+	if Permanent(nil) != nil || Transient(nil) != nil {
+		t.Errorf("Permanent(nil), Transient(nil): got non-nil errors")
+	}
+
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{Permanent(io.EOF), false},
+		{Wrap(Permanent(io.EOF), "call"), false},
+		{Transient(context.DeadlineExceeded), true},
+		{Transient(Permanent(io.EOF)), true},
+		{Permanent(Transient(io.EOF)), false},
+	}
+	for i, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("test %d: IsRetryable(%v): got %t, want %t", i+1, tt.err, got, tt.want)
+		}
+		var temporary interface{ Temporary() bool }
+		if !As(tt.err, &temporary) || temporary.Temporary() != tt.want {
+			t.Errorf("test %d: Temporary(): want %t", i+1, tt.want)
+		}
+	}
+	if got := Permanent(io.EOF).Error(); got != "EOF" {
+		t.Errorf("Permanent(io.EOF).Error(): got %q, want %q", got, "EOF")
+	}
+}
+
+// backoffPermanent mimics backoff.PermanentError of github.com/cenkalti/backoff.
+type backoffPermanent struct{ err error }
+
+func (e *backoffPermanent) Error() string { return e.err.Error() }
+
+func TestToBackoff(t *testing.T) {
+	permanent := func(err error) error { return &backoffPermanent{err} }
+	tests := []struct {
+		err       error
+		permanent bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{Permanent(io.EOF), true},
+		{context.Canceled, true},
+	}
+
+	for i, tt := range tests {
+		got := ToBackoff(tt.err, permanent)
+		_, isPermanent := got.(*backoffPermanent)
+		if isPermanent != tt.permanent || !isPermanent && got != tt.err {
+			t.Errorf("test %d: ToBackoff(%v): got %#v, want permanent %t", i+1, tt.err, got, tt.permanent)
+		}
+	}
+}
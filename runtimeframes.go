@@ -0,0 +1,53 @@
+package errors
+
+import "runtime"
+
+// RuntimeFrames returns the frames of st as runtime.Frame values, from
+// innermost to outermost, for use with code built around
+// runtime.CallersFrames, such as profiling and tracing libraries. Each Frame
+// of st yields exactly one runtime.Frame. Frames created by NewFrame and the
+// zero Frame have no program counter: their PC, Func and Entry are zero.
+func (st StackTrace) RuntimeFrames() []runtime.Frame {
+	if st == nil {
+		return nil
+	}
+	frames := make([]runtime.Frame, len(st))
+	for i, f := range st {
+		name, file, line := f.resolve()
+		frames[i] = runtime.Frame{Function: name, File: file, Line: line}
+		if _, ok := lookupSynthetic(f); ok || f == 0 {
+			continue
+		}
+		frames[i].PC = f.pc()
+		if fn := runtime.FuncForPC(f.pc()); fn != nil {
+			frames[i].Func = fn
+			frames[i].Entry = fn.Entry()
+		}
+	}
+	return frames
+}
+
+// FromRuntimeFrames returns a StackTrace holding frames, as obtained from
+// runtime.CallersFrames. A frame is kept as its program counter if that
+// resolves to the same function, file and line in this program. Other
+// frames, such as those of another program or the inlined frames
+// runtime.CallersFrames expands a single program counter into, cannot be
+// represented by a Frame and are recorded as the zero Frame, which reports
+// "unknown". To keep their function, file and line, use the runtime.Frame
+// values themselves.
+func FromRuntimeFrames(frames []runtime.Frame) StackTrace {
+	if frames == nil {
+		return nil
+	}
+	st := make(StackTrace, len(frames))
+	for i, fr := range frames {
+		if fr.PC == 0 {
+			continue
+		}
+		f := Frame(fr.PC + 1)
+		if name, file, line := f.resolve(); name == fr.Function && file == fr.File && line == fr.Line {
+			st[i] = f
+		}
+	}
+	return st
+}
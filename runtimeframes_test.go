@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestRuntimeFrames(t *testing.T) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+	var want []runtime.Frame
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		fr, more := frames.Next()
+		want = append(want, fr)
+		if !more {
+			break
+		}
+	}
+
+	st := FromRuntimeFrames(want)
+	if len(st) != len(want) {
+		t.Fatalf("FromRuntimeFrames(): got %d frames, want %d", len(st), len(want))
+	}
+	if st[0] == 0 {
+		t.Errorf("FromRuntimeFrames(): frame %v recorded without its program counter", st[0])
+	}
+	got := st.RuntimeFrames()
+	for i := range want {
+		if got[i].Function != want[i].Function || got[i].File != want[i].File || got[i].Line != want[i].Line {
+			t.Errorf("test %d: RuntimeFrames(): got %s %s:%d, want %s %s:%d", i+1,
+				got[i].Function, got[i].File, got[i].Line, want[i].Function, want[i].File, want[i].Line)
+		}
+	}
+	if got[0].PC != want[0].PC || got[0].Func == nil || got[0].Entry != want[0].Entry {
+		t.Errorf("RuntimeFrames(): got PC %#x entry %#x, want PC %#x entry %#x", got[0].PC, got[0].Entry, want[0].PC, want[0].Entry)
+	}
+
+	remote := []runtime.Frame{{PC: 0x10, Function: "example.com/app.handler", File: "/src/app/handler.go", Line: 42}}
+	st = FromRuntimeFrames(remote)
+	if st[0] != 0 {
+		t.Errorf("FromRuntimeFrames(remote): got %v, want the zero Frame", st[0])
+	}
+	synthetic := NewFrame("example.com/app.handler", "/src/app/handler.go", 42)
+	if got := (StackTrace{synthetic}).RuntimeFrames()[0]; got.PC != 0 || got.Func != nil || got.Function != "example.com/app.handler" || got.Line != 42 {
+		t.Errorf("RuntimeFrames() of synthetic frame: got %+v", got)
+	}
+	if FromRuntimeFrames(nil) != nil || StackTrace(nil).RuntimeFrames() != nil {
+		t.Error("nil: got non-nil result")
+	}
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingPolicy limits how often stack traces are captured at a single call
+// site. Errors created at a call site whose quota is exhausted are created
+// without a stack trace; they are otherwise identical.
+type SamplingPolicy struct {
+	// Every captures one stack trace out of every Every errors created at
+	// a call site. Zero and one capture every stack trace.
+	Every uint64
+
+	// PerSecond caps the number of stack traces captured at a call site in
+	// any one second. Zero means no cap.
+	PerSecond uint64
+}
+
+var sampling atomic.Value // of *sampler
+
+// SetStackSampling installs p as the stack capture sampling policy, replacing
+// any previous policy and discarding the per call site counters it kept.
+// The zero SamplingPolicy captures every stack trace.
+func SetStackSampling(p SamplingPolicy) {
+	if p.Every <= 1 && p.PerSecond == 0 {
+		sampling.Store((*sampler)(nil))
+		return
+	}
+	sampling.Store(&sampler{policy: p})
+}
+
+// now is the clock used by samplers; it is a variable for testing.
+var now = time.Now
+
+// sampler applies a SamplingPolicy to each call site independently.
+type sampler struct {
+	policy SamplingPolicy
+	sites  sync.Map // of uintptr to *site
+}
+
+// site holds the sampling state of a single call site.
+type site struct {
+	count  uint64 // errors created at the site
+	second int64  // the second the quota below applies to
+	taken  uint64 // stack traces captured during second
+}
+
+// sample reports whether a stack trace should be captured for an error
+// created at the call site pc.
+func (s *sampler) sample(pc uintptr) bool {
+	v, ok := s.sites.Load(pc)
+	if !ok {
+		v, _ = s.sites.LoadOrStore(pc, new(site))
+	}
+	st := v.(*site)
+
+	if every := s.policy.Every; every > 1 && (atomic.AddUint64(&st.count, 1)-1)%every != 0 {
+		return false
+	}
+	if limit := s.policy.PerSecond; limit > 0 {
+		sec := now().Unix()
+		if old := atomic.LoadInt64(&st.second); old != sec && atomic.CompareAndSwapInt64(&st.second, old, sec) {
+			atomic.StoreUint64(&st.taken, 0)
+		}
+		if atomic.AddUint64(&st.taken, 1) > limit {
+			return false
+		}
+	}
+	return true
+}
+
+// loadSampler returns the installed sampler, or nil if every stack trace is
+// to be captured.
+func loadSampler() *sampler {
+	s, _ := sampling.Load().(*sampler)
+	return s
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"testing"
+	"time"
+)
+
+func sampledErrors(n int) (captured int) {
+	for i := 0; i < n; i++ {
+		err := New("error")
+		if len(err.(*fundamental).stack.pcs) > 0 {
+			captured++
+		}
+	}
+	return captured
+}
+
+func TestSetStackSampling(t *testing.T) {
+	defer SetStackSampling(SamplingPolicy{})
+	defer func() { now = time.Now }()
+
+	second := int64(0)
+	now = func() time.Time { return time.Unix(second, 0) }
+
+	tests := []struct {
+		policy SamplingPolicy
+		n      int
+		want   int
+	}{
+		{SamplingPolicy{}, 10, 10},
+		{SamplingPolicy{Every: 1}, 10, 10},
+		{SamplingPolicy{Every: 3}, 10, 4},
+		{SamplingPolicy{PerSecond: 2}, 10, 2},
+		{SamplingPolicy{Every: 2, PerSecond: 3}, 10, 3},
+	}
+
+	for i, tt := range tests {
+		SetStackSampling(tt.policy)
+		if got := sampledErrors(tt.n); got != tt.want {
+			t.Errorf("test %d: %+v: captured %d stacks, want %d", i+1, tt.policy, got, tt.want)
+		}
+	}
+
+	SetStackSampling(SamplingPolicy{PerSecond: 1})
+	sampledErrors(5)
+	second++
+	if got := sampledErrors(5); got != 1 {
+		t.Errorf("next second: captured %d stacks, want 1", got)
+	}
+}
+
+func TestSamplingPerCallSite(t *testing.T) {
+	SetStackSampling(SamplingPolicy{PerSecond: 1})
+	defer SetStackSampling(SamplingPolicy{})
+
+	a, b := New("a"), New("b")
+	for _, err := range []error{a, b} {
+		if len(err.(*fundamental).stack.pcs) == 0 {
+			t.Errorf("%v: stack not captured at first use of call site", err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Secret returns v wrapped so that it renders as "[REDACTED]" wherever it
+// is formatted, whether as an argument of Errorf, Wrapf and the like, or as
+// the value of a field: in Error, %+v, the JSON and logfmt renderings, the
+// values returned by Fields and thus every integration built on them. The
+// value of a field can still be retrieved with SecretValue:
+//
+//	err = errors.WithField(err, "token", errors.Secret(token))
+func Secret(v interface{}) interface{} {
+	if s, ok := v.(secret); ok {
+		return s
+	}
+	return secret{v}
+}
+
+// secret is a value that must never be rendered.
+type secret struct {
+	v interface{}
+}
+
+func (s secret) String() string   { return redactedValue }
+func (s secret) GoString() string { return redactedValue }
+
+func (s secret) Format(f fmt.State, verb rune) { io.WriteString(f, redactedValue) }
+
+func (s secret) MarshalText() ([]byte, error) { return []byte(redactedValue), nil }
+
+// SecretValue returns the original value of the outermost field named key
+// in err's chain, unwrapping a value passed through Secret and revealing
+// the value of a field attached with WithSensitiveField, and whether there
+// is such a field. It is meant for explicit debugging only: the value it
+// returns is not redacted.
+func SecretValue(err error, key string) (interface{}, bool) {
+	type fielder interface {
+		fieldList() []field
+	}
+
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if f, ok := err.(fielder); ok {
+			for _, fl := range f.fieldList() {
+				if fl.key != key {
+					continue
+				}
+				if s, ok := fl.value.(secret); ok {
+					return s.v, true
+				}
+				return fl.value, true
+			}
+		}
+		err = Unwrap(err)
+	}
+	return nil, false
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	defer SetFormatStyle(StyleClassic)
+
+	err := WithField(Wrapf(io.EOF, "login %s with %v", "alice", Secret("hunter2")), "token", Secret("t0k3n"))
+	outputs := []string{
+		err.Error(),
+		fmt.Sprintf("%+v", err),
+		fmt.Sprintf("%#v", Secret("hunter2")),
+		fmt.Sprint(Fields(err)),
+		Logfmt(err),
+		Summary(err),
+	}
+	SetFormatStyle(StyleJSON)
+	outputs = append(outputs, fmt.Sprintf("%+v", err))
+	b, _ := json.Marshal(map[string]interface{}{"token": Secret("t0k3n")})
+	outputs = append(outputs, string(b))
+
+	for i, out := range outputs {
+		if strings.Contains(out, "hunter2") || strings.Contains(out, "t0k3n") {
+			t.Errorf("output %d: got %q, revealing a secret", i+1, out)
+		}
+	}
+	if got, want := err.Error(), "login alice with [REDACTED]: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got := Fields(err)["token"]; got != redactedValue {
+		t.Errorf("Fields(): got token %v, want %q", got, redactedValue)
+	}
+
+	tests := []struct {
+		err   error
+		key   string
+		want  interface{}
+		found bool
+	}{
+		{err, "token", "t0k3n", true},
+		{err, "user", nil, false},
+		{WithSensitiveField(io.EOF, "password", "pw"), "password", "pw", true},
+		{WithField(WithField(io.EOF, "id", 1), "id", Secret(Secret(2))), "id", 2, true},
+		{nil, "token", nil, false},
+	}
+	for i, tt := range tests {
+		if got, found := SecretValue(tt.err, tt.key); got != tt.want || found != tt.found {
+			t.Errorf("test %d: SecretValue(%q): got %v, %v, want %v, %v", i+1, tt.key, got, found, tt.want, tt.found)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sections renders err in the sections style, documented by StyleSections.
+func sections(err error) string {
+	var b strings.Builder
+	writeSections(&b, err, "")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeSections writes the sections of err to b, each line preceded by
+// indent.
+func writeSections(b *strings.Builder, err error, indent string) {
+	header := sectionHeader(err)
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if header != "" {
+			b.WriteString(header + "\n")
+			header = ""
+		}
+		if j, ok := err.(*joinError); ok {
+			for i, e := range j.errs {
+				prefix := indent + "  [" + strconv.Itoa(i) + "] "
+				var branch strings.Builder
+				writeSections(&branch, e, "")
+				lines := strings.SplitAfter(strings.TrimSuffix(branch.String(), "\n"), "\n")
+				for k, line := range lines {
+					if k == 0 {
+						b.WriteString(prefix + line)
+					} else {
+						b.WriteString(indent + "      " + line)
+					}
+				}
+				b.WriteString("\n")
+			}
+			return
+		}
+		writeLayer(b, err, indent+"  ")
+		cause := Unwrap(err)
+		if cause != nil && cause.Error() != err.Error() {
+			header = indent + "caused by: " + sectionHeader(cause)
+		} else if cause != nil {
+			header = ""
+		}
+		err = cause
+	}
+}
+
+// sectionHeader returns the first line of the section of err: its message,
+// or the number of errors it joins.
+func sectionHeader(err error) string {
+	if j, ok := err.(*joinError); ok {
+		return j.header()
+	}
+	return err.Error()
+}
+
+// writeLayer writes the attachments of err itself, excluding those of the
+// errors it wraps, to b, each line preceded by indent.
+func writeLayer(b *strings.Builder, err error, indent string) {
+	attr := func(key, value string) { b.WriteString(indent + key + ": " + value + "\n") }
+	block := func(key string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		b.WriteString(indent + key + ":\n")
+		for _, line := range lines {
+			b.WriteString(indent + "  " + line + "\n")
+		}
+	}
+	stack := func(key string, s *stack) {
+		if s != nil && len(s.pcs) > 0 {
+			var buf bytes.Buffer
+			writeFrames(&buf, &streamState{&buf}, len(s.pcs), func(i int) Frame { return Frame(s.pcs[i]) })
+			block(key, strings.Split(strings.TrimPrefix(strings.Replace(buf.String(), "\t", "  ", -1), "\n"), "\n"))
+		}
+	}
+	fields := func(fl []field) {
+		lines := make([]string, len(fl))
+		for i, f := range fl {
+			lines[i] = f.String()
+		}
+		block("fields", lines)
+	}
+
+	switch e := err.(type) {
+	case *fundamental:
+		stack("stack", e.stack)
+	case *withStack:
+		stack("stack", e.stack)
+	case *withFields:
+		fields(e.fields)
+	case *withCode:
+		attr("code", e.code)
+	case typedCoder:
+		attr("code", e.(interface{ Code() string }).Code())
+	case *withKind:
+		attr("kind", e.kind.String())
+	case *withSeverity:
+		attr("severity", e.severity.String())
+	case *withHTTPStatus:
+		attr("http_status", strconv.Itoa(e.status))
+	case *withTime:
+		attr("time", e.time.Format(time.RFC3339Nano))
+	case *withRetryable:
+		attr("retryable", strconv.FormatBool(e.retryable))
+	case *withExitCode:
+		attr("exit_code", strconv.Itoa(e.code))
+	case *withCaller:
+		if e.frame != 0 {
+			name, file, line := e.frame.resolve()
+			attr("caller", file+":"+strconv.Itoa(line)+" "+name)
+		}
+	case *withStackChain:
+		stack("observed in", e.stack)
+	case *withGoroutines:
+		lines := strings.Split(strings.TrimRight(string(e.dump), "\n"), "\n")
+		if e.truncated {
+			lines = append(lines, "... truncated")
+		}
+		block("goroutines", lines)
+	case *withFormatter:
+		var buf bytes.Buffer
+		e.formatter(&buf, e.cause)
+		if text := strings.TrimRight(redact(buf.String()), "\n"); text != "" {
+			block("details", strings.Split(text, "\n"))
+		}
+	case *withCollapsed:
+		attr("collapsed", e.more())
+	case *templateError:
+		if code := e.tmpl.Code(); code != "" {
+			attr("code", code)
+		}
+		stack("stack", e.stack)
+	case *masked:
+		stack("stack", e.stack)
+	case *timeoutError:
+		fields(e.fieldList())
+		stack("stack", e.stack)
+	case *panicError:
+		stack("stack", e.stack)
+	case *dumpError:
+		lines := e.stackLines()
+		for i := range lines {
+			lines[i] = strings.Replace(lines[i], "\t", "  ", 1)
+		}
+		block("stack", lines)
+	case *Validation:
+		var lines []string
+		for _, f := range e.fields {
+			lines = append(lines, f.path+": "+redact(f.msg))
+		}
+		block("invalid fields", lines)
+		stack("stack", e.stack)
+	case *rpcError:
+		attr("jsonrpc_code", strconv.Itoa(e.code))
+		block("remote stack", e.stack)
+	}
+}
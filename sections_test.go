@@ -0,0 +1,77 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+)
+
+func TestStyleSections(t *testing.T) {
+	defer SetFormatStyle(StyleClassic)
+
+	f := NewFrame("example.com/app.handler", "/src/app/handler.go", 42)
+	g := NewFrame("example.com/app.loadConfig", "/src/app/config.go", 7)
+	root := &fundamental{msg: "permission denied", stack: &stack{pcs: []uintptr{uintptr(g)}}}
+	wrapped := &withFields{
+		cause: &withCode{
+			cause: &withStack{
+				&withMessage{cause: root, msg: "read config"},
+				&stack{pcs: []uintptr{uintptr(f)}},
+			},
+			code: "CONFIG",
+		},
+		fields: []field{{key: "path", value: "/etc/app.conf"}},
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{{
+		stderrors.New("plain"),
+		"plain",
+	}, {
+		root,
+		"permission denied\n" +
+			"  stack:\n" +
+			"    example.com/app.loadConfig\n" +
+			"      /src/app/config.go:7",
+	}, {
+		wrapped,
+		"read config: permission denied\n" +
+			"  fields:\n" +
+			"    path=/etc/app.conf\n" +
+			"  code: CONFIG\n" +
+			"  stack:\n" +
+			"    example.com/app.handler\n" +
+			"      /src/app/handler.go:42\n" +
+			"caused by: permission denied\n" +
+			"  stack:\n" +
+			"    example.com/app.loadConfig\n" +
+			"      /src/app/config.go:7",
+	}, {
+		&withSeverity{cause: &withKind{cause: stderrors.New("gone"), kind: NotFound}, severity: SeverityWarning},
+		"gone\n" +
+			"  severity: warning\n" +
+			"  kind: " + NotFound.String(),
+	}, {
+		&joinError{errs: []error{root, stderrors.New("timeout")}},
+		"2 errors occurred:\n" +
+			"  [0] permission denied\n" +
+			"        stack:\n" +
+			"          example.com/app.loadConfig\n" +
+			"            /src/app/config.go:7\n" +
+			"  [1] timeout",
+	}}
+	SetFormatStyle(StyleSections)
+	for i, tt := range tests {
+		if _, ok := tt.err.(interface{ Format(fmt.State, rune) }); !ok {
+			if got := sections(tt.err); got != tt.want {
+				t.Errorf("test %d: sections(err):\ngot:\n%s\nwant:\n%s", i+1, got, tt.want)
+			}
+			continue
+		}
+		if got := fmt.Sprintf("%+v", tt.err); got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%%+v, err):\ngot:\n%s\nwant:\n%s", i+1, got, tt.want)
+		}
+	}
+}
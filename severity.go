@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Severity ranks how serious an error is. Greater values are more severe.
+type Severity int
+
+// The severities known to this package, from least to most severe.
+const (
+	SeverityUnknown Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = [...]string{
+	SeverityUnknown:  "unknown",
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// String returns the lower case name of the severity.
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// WithSeverity annotates err with severity.
+// If err is nil, WithSeverity returns nil.
+func WithSeverity(err error, severity Severity) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withSeverity{
+		cause:    err,
+		severity: severity,
+	}))
+}
+
+// SeverityOf returns the outermost Severity attached to err's chain, or
+// SeverityUnknown if there is none. An error value carries a severity if it
+// implements the following interface:
+//
+//	type severer interface {
+//	       Severity() errors.Severity
+//	}
+func SeverityOf(err error) Severity {
+	type severer interface {
+		Severity() Severity
+	}
+
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if s, ok := err.(severer); ok {
+			return s.Severity()
+		}
+		err = Unwrap(err)
+	}
+	return SeverityUnknown
+}
+
+type withSeverity struct {
+	cause    error
+	severity Severity
+}
+
+func (w *withSeverity) Error() string      { return redact(w.cause.Error()) }
+func (w *withSeverity) Cause() error       { return w.cause }
+func (w *withSeverity) Severity() Severity { return w.severity }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withSeverity) Unwrap() error { return w.cause }
+
+func (w *withSeverity) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, "severity="+w.severity.String())
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestWithSeverityNil(t *testing.T) {
+	if got := WithSeverity(nil, SeverityError); got != nil {
+		t.Errorf("WithSeverity(nil, SeverityError): got %#v, expected nil", got)
+	}
+}
+
+func TestSeverityOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Severity
+	}{
+		{nil, SeverityUnknown},
+		{io.EOF, SeverityUnknown},
+		{WithSeverity(io.EOF, SeverityWarning), SeverityWarning},
+		{Wrap(WithSeverity(io.EOF, SeverityCritical), "read"), SeverityCritical},
+	}
+
+	for i, tt := range tests {
+		if got := SeverityOf(tt.err); got != tt.want {
+			t.Errorf("test %d: SeverityOf(): got %v, want %v", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	tests := []struct {
+		Severity
+		want string
+	}{
+		{SeverityUnknown, "unknown"},
+		{SeverityWarning, "warning"},
+		{SeverityCritical, "critical"},
+		{Severity(42), "severity(42)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.Severity.String(); got != tt.want {
+			t.Errorf("Severity(%d).String(): got %q, want %q", int(tt.Severity), got, tt.want)
+		}
+	}
+
+	if got := fmt.Sprintf("%+v", WithSeverity(io.EOF, SeverityError)); got != "EOF\nseverity=error" {
+		t.Errorf("fmt.Sprintf(%%+v, err): got %q", got)
+	}
+}
@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogHandler returns a slog.Handler which passes records on to next with
+// every error attribute replaced by a group describing the error, so that
+// errors are logged as structured data without changing the calls logging
+// them:
+//
+//	logger := slog.New(errors.NewSlogHandler(slog.NewJSONHandler(os.Stderr, nil)))
+//	logger.Error("payment failed", "err", err)
+//
+// The group of an error, under the key of the attribute, holds:
+//
+//	msg        the message of the error
+//	chain      the messages of the layers of its chain, outermost first,
+//	           when there are several, as in FlattenMessages
+//	code       its code, if any
+//	kind       its kind, if known
+//	fields     its fields, as a group of redacted values
+//	stack      the stack trace recorded closest to its root cause, if any,
+//	           or stack_encoded if enabled by SetOfflineSymbolization
+//	goroutine  the ID of the goroutine which recorded that stack trace,
+//	           if enabled by SetGoroutineCapture
+//	time       the time recorded by WithTime, if any
+//
+// Errors of other packages are described too, from what their chains hold,
+// including errors of this package they wrap. Errors implementing
+// slog.LogValuer are logged as the value they resolve to. Attributes of
+// groups, and those added by WithAttrs, are rewritten as well.
+func NewSlogHandler(next slog.Handler) slog.Handler {
+	return &slogHandler{next: next}
+}
+
+type slogHandler struct {
+	next slog.Handler
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(slogAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	rewritten := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		rewritten[i] = slogAttr(a)
+	}
+	return &slogHandler{next: h.next.WithAttrs(rewritten)}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{next: h.next.WithGroup(name)}
+}
+
+// slogAttr returns a with the errors it holds replaced by their groups.
+func slogAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		attrs := v.Group()
+		rewritten := make([]slog.Attr, len(attrs))
+		for i, ga := range attrs {
+			rewritten[i] = slogAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(rewritten...)}
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.Attr{Key: a.Key, Value: slogError(err)}
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// slogError returns the group describing err.
+func slogError(err error) slog.Value {
+	rec := jsonError(err)
+	attrs := []slog.Attr{slog.String("msg", rec.Message)}
+	if chain := messageChain(err); len(chain) > 1 {
+		attrs = append(attrs, slog.Any("chain", chain))
+	}
+	if rec.Code != "" {
+		attrs = append(attrs, slog.String("code", rec.Code))
+	}
+	if rec.Kind != "" {
+		attrs = append(attrs, slog.String("kind", rec.Kind))
+	}
+	if len(rec.Fields) > 0 {
+		fields := make([]slog.Attr, 0, len(rec.Fields))
+		for _, k := range sortedKeys(rec.Fields) {
+			fields = append(fields, slog.Any(k, rec.Fields[k]))
+		}
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fields...)})
+	}
+	if len(rec.Stack) > 0 {
+		attrs = append(attrs, slog.Any("stack", rec.Stack))
+	}
+	if rec.Encoded != "" {
+		attrs = append(attrs, slog.String("stack_encoded", rec.Encoded))
+	}
+	if rec.Goroutine != 0 {
+		attrs = append(attrs, slog.Int64("goroutine", rec.Goroutine))
+	}
+	if rec.Time != nil {
+		attrs = append(attrs, slog.Time("time", *rec.Time))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// messageChain returns the messages of the layers of err's chain, from
+// outermost to innermost, as FlattenMessages joins them. A layer wrapping
+// several errors contributes its full message.
+func messageChain(err error) []string {
+	var msgs []string
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if _, ok := err.(interface{ Unwrap() []error }); ok {
+			return append(msgs, err.Error())
+		}
+		if msg := ownMessage(err); msg != "" {
+			msgs = append(msgs, msg)
+		}
+		err = Unwrap(err)
+	}
+	return msgs
+}
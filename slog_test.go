@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// foreignWrapper is an error of another package wrapping an error.
+type foreignWrapper struct{ err error }
+
+func (w foreignWrapper) Error() string { return "wrapper: " + w.err.Error() }
+func (w foreignWrapper) Unwrap() error { return w.err }
+
+func TestNewSlogHandler(t *testing.T) {
+	f := NewFrame("example.com/app.handler", "/src/app/handler.go", 42)
+	base := WithStackTrace(WithKind(WithField(io.EOF, "user", "alice"), NotFound), StackTrace{f})
+	err := WithMessage(WithCode(base, "LOAD"), "load user")
+
+	tests := []struct {
+		args []interface{}
+		want string
+	}{{
+		[]interface{}{"err", err},
+		`"err":{"msg":"load user: EOF","chain":["load user","EOF"],"code":"LOAD","kind":"not_found",` +
+			`"fields":{"user":"alice"},"stack":["example.com/app.handler /src/app/handler.go:42"]}`,
+	}, {
+		[]interface{}{"err", io.EOF},
+		`"err":{"msg":"EOF"}`,
+	}, {
+		[]interface{}{slog.Group("req", "id", 7, "err", foreignWrapper{err})},
+		`"req":{"id":7,"err":{"msg":"wrapper: load user: EOF","chain":["wrapper","load user","EOF"],` +
+			`"code":"LOAD","kind":"not_found","fields":{"user":"alice"},"stack":["example.com/app.handler /src/app/handler.go:42"]}}`,
+	}, {
+		[]interface{}{"n", 1},
+		`"n":1`,
+	}}
+	for i, tt := range tests {
+		var buf bytes.Buffer
+		logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		})))
+		logger.Error("failed", tt.args...)
+		want := `{"level":"ERROR","msg":"failed",` + tt.want + "}\n"
+		if got := buf.String(); got != want {
+			t.Errorf("test %d: got %s want %s", i+1, got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	h := NewSlogHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Enabled(info): got true below the level of the handler")
+	}
+	slog.New(h).WithGroup("job").With("err", io.ErrUnexpectedEOF).Warn("retrying", "attempt", 2)
+	if got, want := buf.String(), `job.err.msg="unexpected EOF" job.attempt=2`; !strings.Contains(got, want) {
+		t.Errorf("WithGroup, With: got %q, want it to contain %q", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// sourceContext is the number of source lines printed on either side of the
+// line of each application frame, or zero if none are.
+var sourceContext int32
+
+// SetSourceContext sets the number of source lines printed on either side of
+// the line of each application frame by the %+v verb of errors and stack
+// traces, when the source file can be read:
+//
+//	github.com/example/app.loadConfig
+//		/src/app/config.go:42
+//		  40 |	f, err := os.Open(path)
+//		  41 |	if err != nil {
+//		> 42 |		return errors.Wrap(err, "open config")
+//		  43 |	}
+//		  44 |	defer f.Close()
+//
+// Source snippets are meant for development environments, where the source
+// matches the binary. Frames of the standard library are printed without
+// snippet. A value of zero, the default, disables source snippets.
+func SetSourceContext(lines int) {
+	if lines < 0 {
+		lines = 0
+	}
+	atomic.StoreInt32(&sourceContext, int32(lines))
+}
+
+// sourceFiles caches the lines of the source files read for snippets, by
+// path. Files that cannot be read are cached as nil.
+var sourceFiles sync.Map // of string to []string
+
+// sourceLines returns the lines of the source file at path, or nil if it
+// cannot be read.
+func sourceLines(path string) []string {
+	if lines, ok := sourceFiles.Load(path); ok {
+		return lines.([]string)
+	}
+	var lines []string
+	if b, err := os.ReadFile(path); err == nil {
+		lines = strings.Split(string(bytes.TrimRight(b, "\n")), "\n")
+	}
+	sourceFiles.Store(path, lines)
+	return lines
+}
+
+// writeSource writes the source snippet of f to w, if source snippets are
+// enabled and f is an application frame whose source can be read.
+func writeSource(w io.Writer, f Frame) {
+	n := int(atomic.LoadInt32(&sourceContext))
+	if n == 0 {
+		return
+	}
+	name, file, line := f.resolve()
+	if isStdlib(name) {
+		return
+	}
+	lines := sourceLines(file)
+	if line < 1 || line > len(lines) {
+		return
+	}
+	first, last := line-n, line+n
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+	width := len(strconv.Itoa(last))
+	for i := first; i <= last; i++ {
+		marker := "  "
+		if i == line {
+			marker = "> "
+		}
+		num := strconv.Itoa(i)
+		io.WriteString(w, "\n\t"+marker+strings.Repeat(" ", width-len(num))+num+" |"+lines[i-1])
+	}
+}
@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSetSourceContext(t *testing.T) {
+	defer SetSourceContext(0)
+
+	err := New("error") // the line printed by the snippet
+	SetSourceContext(1)
+	want := "\n\t  11 |\n" +
+		"\t> 12 |\terr := New(\"error\") // the line printed by the snippet\n" +
+		"\t  13 |\tSetSourceContext(1)\n"
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, want) {
+		t.Errorf("fmt.Sprintf(%%+v, err): got %q, want it to contain %q", got, want)
+	}
+	st := err.(*fundamental).StackTrace()
+	if got := fmt.Sprintf("%+v", st[:1]); !strings.HasSuffix(got, strings.TrimSuffix(want, "\n")) {
+		t.Errorf("fmt.Sprintf(%%+v, st): got %q, want suffix %q", got, want)
+	}
+	if got := fmt.Sprintf("%+v", st); strings.Contains(got, "testing.go:") && strings.Count(got, ">") != 1 {
+		t.Errorf("fmt.Sprintf(%%+v, st): got %q, want no snippet for standard library frames", got)
+	}
+
+	SetSourceContext(0)
+	if got := fmt.Sprintf("%+v", err); strings.Contains(got, " |") {
+		t.Errorf("fmt.Sprintf(%%+v, err) with source context disabled: got %q", got)
+	}
+
+	SetSourceContext(2)
+	unknown := &withStack{fmt.Errorf("error"), &stack{pcs: []uintptr{uintptr(NewFrame("app.main", "/nonexistent/main.go", 7))}}}
+	if got, want := fmt.Sprintf("%+v", unknown), "error\napp.main\n\t/nonexistent/main.go:7"; got != want {
+		t.Errorf("fmt.Sprintf(%%+v, err) with unreadable source: got %q, want %q", got, want)
+	}
+}
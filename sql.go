@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"unicode"
+)
+
+// WrapSQL returns an error annotating err, returned by running query with
+// args, with the message "query", a stack trace at the point WrapSQL is
+// called, and the fields:
+//
+//	query       query normalized, with literals replaced by "?", and redacted
+//	query_args  the number of arguments
+//
+// The error is of kind NotFound if err is sql.ErrNoRows, Unavailable if it
+// is sql.ErrConnDone, and Canceled or DeadlineExceeded if it is the
+// corresponding error of package context. If err is nil, WrapSQL returns
+// nil.
+func WrapSQL(err error, query string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	var w error = &withStack{&withMessage{cause: err, msg: "query"}, callers()}
+	w = &withFields{cause: w, fields: []field{
+		{key: "query", value: redact(normalizeQuery(query))},
+		{key: "query_args", value: len(args)},
+	}}
+	if kind := sqlKind(err); kind != Unknown {
+		w = &withKind{cause: w, kind: kind}
+	}
+	return created(transform(err, w))
+}
+
+// sqlKind returns the kind of err, returned by package database/sql.
+func sqlKind(err error) Kind {
+	switch {
+	case Is(err, sql.ErrNoRows):
+		return NotFound
+	case Is(err, sql.ErrConnDone):
+		return Unavailable
+	case Is(err, context.Canceled):
+		return Canceled
+	case Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	}
+	return Unknown
+}
+
+// normalizeQuery returns query with runs of white space collapsed to single
+// spaces and its string and numeric literals replaced by "?", so that the
+// values they hold do not leak, and queries differing only by them compare
+// equal.
+func normalizeQuery(query string) string {
+	var b strings.Builder
+	rs := []rune(strings.TrimSpace(query))
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			for i+1 < len(rs) && unicode.IsSpace(rs[i+1]) {
+				i++
+			}
+			b.WriteByte(' ')
+		case r == '\'':
+			// Skip to the closing quote; doubled quotes escape a quote.
+			for i++; i < len(rs); i++ {
+				if rs[i] == '\'' {
+					if i+1 < len(rs) && rs[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			b.WriteByte('?')
+		case unicode.IsDigit(r) && (i == 0 || !isIdentRune(rs[i-1])):
+			for i+1 < len(rs) && (unicode.IsDigit(rs[i+1]) || rs[i+1] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isIdentRune reports whether r may be part of an SQL identifier or
+// placeholder, such as t1 or $1.
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '$' || r == '@' || r == ':' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
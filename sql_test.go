@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestWrapSQLNil(t *testing.T) {
+	if got := WrapSQL(nil, "SELECT 1"); got != nil {
+		t.Errorf("WrapSQL(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestWrapSQL(t *testing.T) {
+	tests := []struct {
+		err  error
+		kind Kind
+	}{
+		{sql.ErrNoRows, NotFound},
+		{sql.ErrConnDone, Unavailable},
+		{context.Canceled, Canceled},
+		{Wrap(context.DeadlineExceeded, "scan"), DeadlineExceeded},
+		{io.EOF, Unknown},
+	}
+
+	for i, tt := range tests {
+		err := WrapSQL(tt.err, "SELECT name FROM users WHERE id = $1", 42)
+		if got := KindOf(err); got != tt.kind {
+			t.Errorf("test %d: KindOf(): got %v, want %v", i+1, got, tt.kind)
+		}
+		if got, want := err.Error(), "query: "+tt.err.Error(); got != want {
+			t.Errorf("test %d: WrapSQL(): got %q, want %q", i+1, got, want)
+		}
+		if !Is(err, tt.err) {
+			t.Errorf("test %d: Is(err, %v): got false, want true", i+1, tt.err)
+		}
+	}
+
+	err := WrapSQL(sql.ErrNoRows, "SELECT * FROM t WHERE a = 'x'", 1, 2)
+	want := map[string]interface{}{"query": "SELECT * FROM t WHERE a = ?", "query_args": 2}
+	if got := Fields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields(): got %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"SELECT 1", "SELECT ?"},
+		{"  SELECT *\n\tFROM users\n WHERE id = $1  ", "SELECT * FROM users WHERE id = $1"},
+		{"SELECT * FROM t1 WHERE name = 'O''Brien' AND age > 42.5", "SELECT * FROM t1 WHERE name = ? AND age > ?"},
+		{"INSERT INTO t (a, b) VALUES ('secret', -7)", "INSERT INTO t (a, b) VALUES (?, -?)"},
+		{"SELECT 'unterminated", "SELECT ?"},
+		{"UPDATE t SET v = :v1 WHERE id = @p2", "UPDATE t SET v = :v1 WHERE id = @p2"},
+	}
+
+	for i, tt := range tests {
+		if got := normalizeQuery(tt.query); got != tt.want {
+			t.Errorf("test %d: normalizeQuery(%q): got %q, want %q", i+1, tt.query, got, tt.want)
+		}
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"path"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Frame represents a program counter inside a stack frame.
@@ -19,30 +20,40 @@ type Frame uintptr
 // multiple frames may have the same PC value.
 func (f Frame) pc() uintptr { return uintptr(f) - 1 }
 
-// file returns the full path to the file that contains the
-// function for this Frame's pc.
-func (f Frame) file() string {
+// resolve returns the function name, source file and line number of this
+// Frame's pc, looking the pc up once. It returns "unknown", "unknown" and
+// zero if the pc cannot be resolved.
+func (f Frame) resolve() (name, file string, line int) {
+	if sf, ok := lookupSynthetic(f); ok {
+		return sf.name, sf.file, sf.line
+	}
 	fn := runtime.FuncForPC(f.pc())
 	if fn == nil {
-		return "unknown"
+		return "unknown", "unknown", 0
 	}
-	file, _ := fn.FileLine(f.pc())
+	file, line = fn.FileLine(f.pc())
+	return fn.Name(), file, line
+}
+
+// file returns the full path to the file that contains the
+// function for this Frame's pc.
+func (f Frame) file() string {
+	_, file, _ := f.resolve()
 	return file
 }
 
 // line returns the line number of source code of the
 // function for this Frame's pc.
 func (f Frame) line() int {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return 0
-	}
-	_, line := fn.FileLine(f.pc())
+	_, _, line := f.resolve()
 	return line
 }
 
 // name returns the name of this function, if known.
 func (f Frame) name() string {
+	if sf, ok := lookupSynthetic(f); ok {
+		return sf.name
+	}
 	fn := runtime.FuncForPC(f.pc())
 	if fn == nil {
 		return "unknown"
@@ -50,50 +61,116 @@ func (f Frame) name() string {
 	return fn.Name()
 }
 
-func (f Frame) Format(s fmt.State, verb rune) { f.format(s, s, verb) }
+// PC returns the program counter for this frame, or zero for the zero Frame.
+func (f Frame) PC() uintptr {
+	if f == 0 {
+		return 0
+	}
+	return f.pc()
+}
+
+// File returns the full path to the source file of this frame, or
+// "unknown" if it cannot be resolved.
+func (f Frame) File() string { return f.file() }
+
+// Line returns the source line number of this frame, or zero if it cannot be
+// resolved.
+func (f Frame) Line() int { return f.line() }
+
+// Func returns the package path-qualified name of the function of this
+// frame, such as "github.com/pkg/errors.New", or "unknown" if it cannot be
+// resolved.
+func (f Frame) Func() string { return f.name() }
+
+// Package returns the import path of the package of the function of this
+// frame, such as "github.com/pkg/errors", or "" if it cannot be resolved.
+func (f Frame) Package() string {
+	name := f.name()
+	if name == "unknown" {
+		return ""
+	}
+	return pkgPath(name)
+}
 
 // Format formats the frame according to the fmt.Formatter interface.
 //
 //    %s    source file
 //    %d    source line
 //    %n    function name
+//    %P    import path of the package of the function; %p is reserved
+//          by fmt for pointers
 //    %v    equivalent to %s:%d
+//    %q    %v as a double-quoted Go string literal
 //
 // Format accepts flags that alter the printing of some verbs, as follows:
 //
 //    %+s   function name and path of source file relative to the compile time
 //          GOPATH separated by \n\t (<funcname>\n\t<path>)
 //    %+v   equivalent to %+s:%d
+//    %+q   %+v as a double-quoted Go string literal
+func (f Frame) Format(s fmt.State, verb rune) { f.format(s, s, verb) }
+
+// format writes f to w as Format does, reading flags from s.
 func (f Frame) format(w io.Writer, s fmt.State, verb rune) {
 	switch verb {
 	case 's':
-		switch {
-		case s.Flag('+'):
-			io.WriteString(w, f.name())
-			io.WriteString(w, "\n\t")
-			io.WriteString(w, f.file())
-		default:
-			io.WriteString(w, path.Base(f.file()))
-		}
+		r := f.resolveCached()
+		f.writeFile(w, s, r.name, r.file)
 	case 'd':
 		io.WriteString(w, strconv.Itoa(f.line()))
 	case 'n':
-		io.WriteString(w, funcname(f.name()))
+		io.WriteString(w, f.resolveCached().short)
+	case 'P':
+		io.WriteString(w, f.Package())
 	case 'v':
-		f.format(w, s, 's')
+		if fn := loadFrameFormatter(); fn != nil {
+			fn(w, f, s.Flag('+'))
+			return
+		}
+		r := f.resolveCached()
+		name, file, line := r.name, r.file, r.line
+		if target := hyperlink(f); target != "" && s.Flag('+') {
+			io.WriteString(w, name)
+			io.WriteString(w, "\n\t")
+			if loadPathStyle() == PathBase {
+				file = baseName(file)
+			}
+			io.WriteString(w, osc8(target, file+":"+strconv.Itoa(line)))
+			return
+		}
+		f.writeFile(w, s, name, file)
 		io.WriteString(w, ":")
-		f.format(w, s, 'd')
+		io.WriteString(w, strconv.Itoa(line))
+	case 'q':
+		var b bytes.Buffer
+		f.format(&b, s, 'v')
+		io.WriteString(w, strconv.Quote(b.String()))
+	}
+}
+
+// writeFile writes file, the source file of the frame, as by %s, preceded
+// by name, the function of the frame, if the + flag is given.
+func (f Frame) writeFile(w io.Writer, s fmt.State, name, file string) {
+	if !s.Flag('+') {
+		io.WriteString(w, baseName(file))
+		return
+	}
+	io.WriteString(w, name)
+	io.WriteString(w, "\n\t")
+	if loadPathStyle() == PathBase {
+		file = baseName(file)
 	}
+	io.WriteString(w, file)
 }
 
+// String returns the frame formatted as by %v: the base name of its source
+// file and its line number.
+func (f Frame) String() string { return fmt.Sprint(f) }
+
 // MarshalText formats a stacktrace Frame as a text string. The output is the
 // same as that of fmt.Sprintf("%+v", f), but without newlines or tabs.
 func (f Frame) MarshalText() ([]byte, error) {
-	name := f.name()
-	if name == "unknown" {
-		return []byte(name), nil
-	}
-	return []byte(fmt.Sprintf("%s %s:%d", name, f.file(), f.line())), nil
+	return []byte(f.text()), nil
 }
 
 // StackTrace is stack of Frames from innermost (newest) to outermost (oldest).
@@ -103,46 +180,115 @@ type StackTrace []Frame
 //
 //    %s	lists source files for each Frame in the stack
 //    %v	lists the source file and line number for each Frame in the stack
+//    %q	lists the source file and line number for each Frame in the stack,
+//      	each as a double-quoted Go string literal
 //
 // Format accepts flags that alter the printing of some verbs, as follows:
 //
 //    %+v   Prints filename, function, and line number for each Frame in the stack.
+//
+// A precision limits the frames printed to the given number of innermost
+// frames, in place of the limit set by SetFrameLimit: %+.5v prints the top 5
+// frames followed by a line such as "... 19 frames omitted", and %.5v the
+// top 5 frames followed by "...". Frames merged as by %+v count as one.
 func (st StackTrace) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		switch {
 		case s.Flag('+'):
-			for i := range st {
-				io.WriteString(s, "\n")
-				(&st[i]).Format(s, verb)
-			}
+			writeFrames(s, s, len(st), func(i int) Frame { return st[i] })
 		case s.Flag('#'):
 			fmt.Fprintf(s, "%#v", []Frame(st))
 		default:
 			st.formatSlice(s, verb)
 		}
-	case 's':
+	case 's', 'q':
 		st.formatSlice(s, verb)
 	}
 }
 
+// String returns the stack trace formatted as by %v: the base name of the
+// source file and the line number of each frame, in brackets.
+func (st StackTrace) String() string { return fmt.Sprint(st) }
+
 // formatSlice will format this StackTrace into the given buffer as a slice of
-// Frame, only valid when called with '%s' or '%v'.
+// Frame, only valid when called with '%s', '%v' or '%q'.
 func (st StackTrace) formatSlice(s fmt.State, verb rune) {
-	io.WriteString(s, "[")
-	if len(st) > 0 {
-		(&st[0]).Format(s, verb)
+	n := len(st)
+	if prec, ok := s.Precision(); ok && prec < n {
+		n = prec
 	}
-
-	for i := range st[0:] {
-		io.WriteString(s, " ")
+	io.WriteString(s, "[")
+	for i := range st[:n] {
+		if i > 0 {
+			io.WriteString(s, " ")
+		}
 		(&st[i]).Format(s, verb)
 	}
+	if n < len(st) {
+		if n > 0 {
+			io.WriteString(s, " ")
+		}
+		io.WriteString(s, "...")
+	}
 	io.WriteString(s, "]")
 }
 
-// stack represents a stack of program counters.
-type stack []uintptr
+// FrameInfo holds the resolved components of a Frame.
+type FrameInfo struct {
+	Frame    Frame
+	PC       uintptr
+	Function string // package path-qualified function name
+	File     string
+	Line     int
+}
+
+// Frames iterates over the resolved frames of a StackTrace, resolving each
+// frame only when it is reached.
+type Frames struct {
+	st StackTrace
+}
+
+// Frames returns an iterator over the frames of st, from innermost to
+// outermost. Like runtime.Frames, the iterator is used by calling Next until
+// it reports that no frames remain:
+//
+//	frames := st.Frames()
+//	for {
+//		frame, more := frames.Next()
+//		// process frame
+//		if !more {
+//			break
+//		}
+//	}
+func (st StackTrace) Frames() *Frames {
+	return &Frames{st: st}
+}
+
+// Next returns the next frame and whether there are frames after it. When st
+// has no frames left, or fs is nil, Next returns the zero FrameInfo and false.
+func (fs *Frames) Next() (frame FrameInfo, more bool) {
+	if fs == nil || len(fs.st) == 0 {
+		return FrameInfo{}, false
+	}
+	f := fs.st[0]
+	fs.st = fs.st[1:]
+	name, file, line := f.resolve()
+	return FrameInfo{
+		Frame:    f,
+		PC:       f.PC(),
+		Function: name,
+		File:     file,
+		Line:     line,
+	}, len(fs.st) > 0
+}
+
+// stack represents a stack of program counters, and the goroutine which
+// recorded it.
+type stack struct {
+	pcs []uintptr
+	g   *goroutineInfo // nil unless recorded, see SetGoroutineCapture
+}
 
 // stackMinLen is a best-guess at the minimum length of a stack trace. It
 // doesn't need to be exact, just give a good enough head start for the buffer
@@ -150,33 +296,172 @@ type stack []uintptr
 const stackMinLen = 96
 
 func (s *stack) Format(st fmt.State, verb rune) {
+	if s == nil {
+		return
+	}
 	if verb == 'v' && st.Flag('+') {
 		var b = &bytes.Buffer{}
-		b.Grow(len(*s) * stackMinLen)
-
-		for i := range *s {
-			b.WriteByte('\n')
-			Frame((*s)[i]).format(b, st, verb)
+		b.Grow(len(s.pcs) * stackMinLen)
+		if s.g != nil {
+			s.g.writeTo(b)
 		}
 
+		writeFrames(b, st, len(s.pcs), func(i int) Frame { return Frame(s.pcs[i]) })
+
 		io.Copy(st, b)
 	}
 }
 
+// writeFrames writes the n frames returned by frame to w as by %+v, each on
+// its own line, leaving out those beyond the limit set by SetFrameLimit, or
+// by the precision given to s.
+// Consecutive identical frames, left by recursive calls, are written once
+// followed by their count, as in "(× 200)".
+func writeFrames(w io.Writer, s fmt.State, n int, frame func(i int) Frame) {
+	if hideLibraryFrames() && !s.Flag('#') {
+		frames := make([]Frame, 0, n)
+		for i := 0; i < n; i++ {
+			if f := frame(i); !isLibraryFrame(f) {
+				frames = append(frames, f)
+			}
+		}
+		n, frame = len(frames), func(i int) Frame { return frames[i] }
+	}
+	runs := frameRuns(n, frame)
+	head, tail := loadFrameLimit()
+	limited := head+tail > 0
+	if prec, ok := s.Precision(); ok {
+		head, tail, limited = prec, 0, true
+	}
+	skip := 0
+	if limited && len(runs) > head+tail {
+		skip = len(runs) - head - tail
+	}
+	for i := 0; i < len(runs); i++ {
+		if i == head && skip > 0 {
+			omitted := 0
+			for _, r := range runs[i : i+skip] {
+				omitted += r.count
+			}
+			io.WriteString(w, "\n... "+strconv.Itoa(omitted)+" frames omitted")
+			i += skip - 1
+			continue
+		}
+		r := runs[i]
+		io.WriteString(w, "\n")
+		r.frame.format(w, s, 'v')
+		if r.count > 1 {
+			io.WriteString(w, " (× "+strconv.Itoa(r.count)+")")
+		}
+		writeSource(w, r.frame)
+	}
+}
+
+// A frameRun is a frame repeated count times in a row in a stack trace.
+type frameRun struct {
+	frame Frame
+	count int
+}
+
+// frameRuns returns the n frames returned by frame, with consecutive
+// identical frames merged.
+func frameRuns(n int, frame func(i int) Frame) []frameRun {
+	runs := make([]frameRun, 0, n)
+	for i := 0; i < n; i++ {
+		f := frame(i)
+		if last := len(runs) - 1; last >= 0 && runs[last].frame == f {
+			runs[last].count++
+			continue
+		}
+		runs = append(runs, frameRun{frame: f, count: 1})
+	}
+	return runs
+}
+
 func (s *stack) StackTrace() StackTrace {
-	f := make([]Frame, 0, len(*s))
-	for i := 0; i < len(*s); i++ {
-		f = append(f, Frame((*s)[i]))
+	if s == nil {
+		return nil
+	}
+	f := make([]Frame, 0, len(s.pcs))
+	for i := 0; i < len(s.pcs); i++ {
+		f = append(f, Frame(s.pcs[i]))
 	}
 	return f
 }
 
-func callers() *stack {
-	const depth = 32
-	var pcs [depth]uintptr
-	n := runtime.Callers(3, pcs[:])
-	var st stack = pcs[0:n]
-	return &st
+// captureDisabled is non-zero while stack capture is disabled.
+var captureDisabled int32
+
+// DisableStackCapture turns off stack trace capture: errors created from
+// then on carry an empty stack trace. It is safe to call at any time, from
+// any goroutine.
+func DisableStackCapture() { atomic.StoreInt32(&captureDisabled, 1) }
+
+// EnableStackCapture turns stack trace capture back on after a call to
+// DisableStackCapture. Stack capture is enabled by default.
+func EnableStackCapture() { atomic.StoreInt32(&captureDisabled, 0) }
+
+// StackCaptureEnabled reports whether errors created now capture a stack
+// trace, subject to the sampling policy in effect.
+func StackCaptureEnabled() bool { return atomic.LoadInt32(&captureDisabled) == 0 }
+
+func callers() *stack { return callersSkip(5) }
+
+// callersSkip returns the stack trace starting skip frames up, as counted by
+// runtime.Callers, copied into a slice of its exact length.
+func callersSkip(skip int) *stack {
+	buf := pcBuffers.Get().(*[defaultStackDepth]uintptr)
+	st := &stack{}
+	if pcs := captureStack(skip, buf[:]); len(pcs) > 0 {
+		if s := cachedStack(pcs); s != nil {
+			pcBuffers.Put(buf)
+			return s
+		}
+		st.pcs = make([]uintptr, len(pcs))
+		copy(st.pcs, pcs)
+	}
+	pcBuffers.Put(buf)
+	recordGoroutine(st)
+	return st
+}
+
+// pcBuffers holds the buffers callers records stack traces into, before
+// copying them into a slice of their exact length, so that errors do not
+// each hold on to a buffer of the full stack depth.
+var pcBuffers = sync.Pool{
+	New: func() interface{} { return new([defaultStackDepth]uintptr) },
+}
+
+// captureStack returns the stack trace starting skip frames up, as counted
+// by runtime.Callers, recorded into buf if it can hold the stack depth, or
+// into a new buffer otherwise. It returns nil if stack capture is disabled
+// or the stack trace is not sampled.
+func captureStack(skip int, buf []uintptr) []uintptr {
+	if !StackCaptureEnabled() {
+		return nil
+	}
+	if depth := int(atomic.LoadInt32(&stackDepth)); depth > len(buf) {
+		buf = make([]uintptr, depth)
+	} else {
+		buf = buf[:depth]
+	}
+	if s := loadSampler(); s != nil {
+		if n := runtime.Callers(skip, buf[:1]); n == 0 || !s.sample(buf[0]) {
+			return nil
+		}
+	}
+	n := runtime.Callers(skip, buf)
+	return filterFrames(buf[0:n])
+}
+
+// baseName returns the last element of file, a source file path recorded at
+// compile time. Unlike path.Base, it also splits at the backslashes of paths
+// recorded on Windows, whatever the platform it runs on.
+func baseName(file string) string {
+	if file == "" {
+		return "."
+	}
+	return file[strings.LastIndexAny(file, `/\`)+1:]
 }
 
 // funcname removes the path prefix component of a function's name reported by func.Name().
@@ -172,7 +172,7 @@ func stackTrace() StackTrace {
 	const depth = 8
 	var pcs [depth]uintptr
 	n := runtime.Callers(1, pcs[:])
-	var st stack = pcs[0:n]
+	st := stack{pcs: pcs[0:n]}
 	return st.StackTrace()
 }
 
@@ -248,3 +248,198 @@ func caller() Frame {
 	frame, _ := frames.Next()
 	return Frame(frame.PC)
 }
+
+func TestDisableStackCapture(t *testing.T) {
+	DisableStackCapture()
+	if StackCaptureEnabled() {
+		t.Error("StackCaptureEnabled(): got true after DisableStackCapture")
+	}
+	err := New("error")
+	EnableStackCapture()
+	if !StackCaptureEnabled() {
+		t.Error("StackCaptureEnabled(): got false after EnableStackCapture")
+	}
+
+	if st := err.(*fundamental).StackTrace(); len(st) != 0 {
+		t.Errorf("New() with capture disabled: got %d frames, want 0", len(st))
+	}
+	if got := fmt.Sprintf("%+v", err); got != "error" {
+		t.Errorf("fmt.Sprintf(%%+v, err) with capture disabled: got %q, want %q", got, "error")
+	}
+	if st := New("error").(*fundamental).StackTrace(); len(st) == 0 {
+		t.Error("New() with capture enabled: got no frames")
+	}
+}
+
+func TestStackTraceFrames(t *testing.T) {
+	st := StackTrace{
+		NewFrame("example.com/app.handle", "/src/app/handle.go", 12),
+		NewFrame("example.com/app.main", "/src/app/main.go", 7),
+	}
+	want := []FrameInfo{
+		{st[0], st[0].PC(), "example.com/app.handle", "/src/app/handle.go", 12},
+		{st[1], st[1].PC(), "example.com/app.main", "/src/app/main.go", 7},
+	}
+
+	frames := st.Frames()
+	for i, w := range want {
+		got, more := frames.Next()
+		if got != w {
+			t.Errorf("frame %d: got %+v, want %+v", i, got, w)
+		}
+		if wantMore := i < len(want)-1; more != wantMore {
+			t.Errorf("frame %d: more: got %v, want %v", i, more, wantMore)
+		}
+	}
+	if got, more := frames.Next(); got != (FrameInfo{}) || more {
+		t.Errorf("Next() after last frame: got %+v, %v, want zero FrameInfo, false", got, more)
+	}
+	if got, more := StackTrace(nil).Frames().Next(); got != (FrameInfo{}) || more {
+		t.Errorf("Next() on empty stack: got %+v, %v, want zero FrameInfo, false", got, more)
+	}
+}
+
+func TestFormatQuoted(t *testing.T) {
+	f := NewFrame("example.com/app.handle", "/src/app/handle.go", 12)
+	g := NewFrame("example.com/app.main", "/src/app/main \"x\".go", 7)
+	tests := []struct {
+		arg    interface{}
+		format string
+		want   string
+	}{
+		{f, "%q", `"handle.go:12"`},
+		{f, "%+q", `"example.com/app.handle\n\t/src/app/handle.go:12"`},
+		{g, "%q", `"main \"x\".go:7"`},
+		{Frame(0), "%q", `"unknown:0"`},
+		{StackTrace{f, g}, "%q", `["handle.go:12" "main \"x\".go:7"]`},
+		{StackTrace{f, g}, "%v", `[handle.go:12 main "x".go:7]`},
+		{StackTrace{}, "%q", `[]`},
+	}
+
+	for i, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.arg); got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%q, arg): got %s, want %s", i+1, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	f := NewFrame("example.com/app.handle", "/src/app/handle.go", 12)
+	g := NewFrame("example.com/app.main", "/src/app/main.go", 7)
+	tests := []struct {
+		s    fmt.Stringer
+		want string
+	}{
+		{f, "handle.go:12"},
+		{Frame(0), "unknown:0"},
+		{StackTrace{f, g}, "[handle.go:12 main.go:7]"},
+		{StackTrace(nil), "[]"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.s.String(); got != tt.want {
+			t.Errorf("test %d: String(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRecursion(t *testing.T) {
+	defer SetFrameLimit(0, 0)
+	defer SetFormatStyle(StyleClassic)
+
+	r := NewFrame("app.walk", "/src/app/walk.go", 3)
+	m := NewFrame("app.main", "/src/app/main.go", 9)
+	st := StackTrace{r, r, r, m, m}
+	if got, want := fmt.Sprintf("%+v", st), "\napp.walk\n\t/src/app/walk.go:3 (× 3)\napp.main\n\t/src/app/main.go:9 (× 2)"; got != want {
+		t.Errorf("fmt.Sprintf(%%+v, st): got %q, want %q", got, want)
+	}
+
+	SetFrameLimit(0, 1)
+	if got, want := fmt.Sprintf("%+v", st), "\n... 3 frames omitted\napp.main\n\t/src/app/main.go:9 (× 2)"; got != want {
+		t.Errorf("fmt.Sprintf(%%+v, st) with a frame limit: got %q, want %q", got, want)
+	}
+	SetFrameLimit(0, 0)
+
+	err := &withStack{fmt.Errorf("error"), &stack{pcs: []uintptr{uintptr(r), uintptr(r), uintptr(m)}}}
+	SetFormatStyle(StyleJSON)
+	if got, want := fmt.Sprintf("%+v", err), `{"message":"error","stack":["app.walk /src/app/walk.go:3 (× 2)","app.main /src/app/main.go:9"]}`; got != want {
+		t.Errorf("fmt.Sprintf(%%+v, err) in JSON style: got %q, want %q", got, want)
+	}
+}
+
+func TestCallersExactLength(t *testing.T) {
+	s := callers()
+	if len(s.pcs) == 0 || cap(s.pcs) != len(s.pcs) {
+		t.Errorf("callers(): got len %d, cap %d, want a stack of exact length", len(s.pcs), cap(s.pcs))
+	}
+}
+
+func TestFramePackage(t *testing.T) {
+	var pcs [1]uintptr
+	runtime.Callers(0, pcs[:])
+
+	tests := []struct {
+		Frame
+		want string
+	}{{
+		Frame(initpc),
+		"github.com/peakle/errors",
+	}, {
+		Frame(pcs[0]),
+		"runtime",
+	}, {
+		0,
+		"",
+	}}
+
+	for i, tt := range tests {
+		if got := tt.Frame.Package(); got != tt.want {
+			t.Errorf("test %d: Package(): got %q, want %q", i+1, got, tt.want)
+		}
+		if got := fmt.Sprintf("%P", tt.Frame); got != tt.want {
+			t.Errorf("test %d: %%P: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestStackTracePrecision(t *testing.T) {
+	st := StackTrace{Frame(initpc), Frame(initpc + 1), Frame(initpc + 2), Frame(initpc + 3)}
+	lines := func(s string) (n int) {
+		for _, c := range s {
+			if c == '\n' {
+				n++
+			}
+		}
+		return n
+	}
+
+	tests := []struct {
+		format string
+		lines  int
+		suffix string
+	}{
+		{"%+v", 8, ":9"},
+		{"%+.2v", 5, "... 2 frames omitted"},
+		{"%+.0v", 1, "... 4 frames omitted"},
+		{"%+.9v", 8, ":9"},
+	}
+	for i, tt := range tests {
+		got := fmt.Sprintf(tt.format, st)
+		if lines(got) != tt.lines || len(got) < len(tt.suffix) || got[len(got)-len(tt.suffix):] != tt.suffix {
+			t.Errorf("test %d: %s: got %q, want %d lines ending in %q", i+1, tt.format, got, tt.lines, tt.suffix)
+		}
+	}
+
+	slices := []struct {
+		format, want string
+	}{
+		{"%.2v", "[stack_test.go:9 stack_test.go:9 ...]"},
+		{"%.0s", "[...]"},
+		{"%.4v", "[stack_test.go:9 stack_test.go:9 stack_test.go:9 stack_test.go:9]"},
+	}
+	for i, tt := range slices {
+		if got := fmt.Sprintf(tt.format, st); got != tt.want {
+			t.Errorf("test %d: %s: got %q, want %q", i+1, tt.format, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"encoding/binary"
+)
+
+// AppendBinary appends st to b in a compact binary form, for log pipelines
+// carrying stack traces in high volumes, and returns the extended buffer.
+// The form holds the number of frames followed by their program counters,
+// each as a varint of its difference to the previous one; the first is
+// relative to a function of this package, as in EncodeStack, so that the
+// stack trace can be decoded by UnmarshalBinary in any process running the
+// same binary, whatever address it was loaded at.
+func (st StackTrace) AppendBinary(b []byte) ([]byte, error) {
+	b = binary.AppendUvarint(b, uint64(len(st)))
+	prev := uint64(anchorPC())
+	for _, f := range st {
+		b = binary.AppendVarint(b, int64(uint64(f)-prev))
+		prev = uint64(f)
+	}
+	return b, nil
+}
+
+// MarshalBinary returns st in the binary form written by AppendBinary.
+func (st StackTrace) MarshalBinary() ([]byte, error) {
+	return st.AppendBinary(nil)
+}
+
+// UnmarshalBinary sets *st to the stack trace encoded in data by
+// AppendBinary.
+func (st *StackTrace) UnmarshalBinary(data []byte) error {
+	n, size := binary.Uvarint(data)
+	if size <= 0 || n > uint64(len(data)) {
+		return New("errors: malformed binary stack trace")
+	}
+	data = data[size:]
+	frames := make(StackTrace, 0, n)
+	prev := uint64(anchorPC())
+	for i := uint64(0); i < n; i++ {
+		delta, size := binary.Varint(data)
+		if size <= 0 {
+			return New("errors: malformed binary stack trace")
+		}
+		data = data[size:]
+		prev += uint64(delta)
+		frames = append(frames, Frame(prev))
+	}
+	if len(data) > 0 {
+		return New("errors: malformed binary stack trace")
+	}
+	*st = frames
+	return nil
+}
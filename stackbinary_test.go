@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStackTraceBinary(t *testing.T) {
+	tests := []StackTrace{
+		nil,
+		New("error").(*fundamental).StackTrace(),
+		{NewFrame("app.main", "/src/app/main.go", 7), NewFrame("app.run", "/src/app/run.go", 12)},
+	}
+
+	for i, st := range tests {
+		b, err := st.AppendBinary([]byte("prefix"))
+		if err != nil {
+			t.Fatalf("test %d: AppendBinary(): %v", i+1, err)
+		}
+		if string(b[:6]) != "prefix" {
+			t.Errorf("test %d: AppendBinary(): got %q, want the buffer extended", i+1, b)
+		}
+		var got StackTrace
+		if err := got.UnmarshalBinary(b[6:]); err != nil {
+			t.Fatalf("test %d: UnmarshalBinary(): %v", i+1, err)
+		}
+		if len(got) != len(st) || len(st) > 0 && !reflect.DeepEqual(got, st) {
+			t.Errorf("test %d: UnmarshalBinary(): got %v, want %v", i+1, got, st)
+		}
+	}
+}
+
+func TestStackTraceBinarySize(t *testing.T) {
+	st := New("error").(*fundamental).StackTrace()
+	b, _ := st.MarshalBinary()
+	text, _ := st[0].MarshalText()
+	if len(b) >= len(st)*8 || len(b) >= len(text) {
+		t.Errorf("MarshalBinary(): got %d bytes for %d frames", len(b), len(st))
+	}
+}
+
+func TestStackTraceUnmarshalBinaryErrors(t *testing.T) {
+	tests := [][]byte{
+		nil,
+		{2, 1},
+		{1, 0x80},
+		{1, 2, 3},
+		{0xff, 0xff, 0xff, 0xff, 0x0f},
+	}
+
+	for i, data := range tests {
+		var st StackTrace
+		if err := st.UnmarshalBinary(data); err == nil {
+			t.Errorf("test %d: UnmarshalBinary(%v): got %v, want an error", i+1, data, st)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// maxStackCacheEntries bounds the number of distinct stack traces held by
+// the stack cache; stack traces recorded once it is full are not shared.
+const maxStackCacheEntries = 4096
+
+var (
+	stackCacheEnabled int32
+	stackCache        sync.Map // of string, the raw PCs, to *stack
+	stackCacheSize    int32
+)
+
+// SetStackCache controls whether identical stack traces recorded by Wrap,
+// WithStack and the other functions annotating an error with a stack trace
+// share a single stack trace, rather than each holding a copy. Errors
+// created at a call site are most often created through the same path, so
+// enabling the cache reduces the memory held by long-running programs that
+// retain many errors, such as in caches or error groups. It is disabled by
+// default, and does not apply to stack traces recorded while goroutine
+// capture is enabled, whose goroutine IDs are recorded per stack trace.
+//
+// Disabling the cache empties it.
+func SetStackCache(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&stackCacheEnabled, v)
+	if !on {
+		stackCache.Range(func(k, _ interface{}) bool {
+			stackCache.Delete(k)
+			return true
+		})
+		atomic.StoreInt32(&stackCacheSize, 0)
+	}
+}
+
+// cachedStack returns the stack trace shared by the recordings of pcs if the
+// stack cache is enabled and applies, or nil otherwise.
+func cachedStack(pcs []uintptr) *stack {
+	if atomic.LoadInt32(&stackCacheEnabled) == 0 || len(pcs) == 0 ||
+		atomic.LoadInt32(&captureGoroutine) != 0 {
+		return nil
+	}
+	key := unsafe.String((*byte)(unsafe.Pointer(&pcs[0])), len(pcs)*int(unsafe.Sizeof(pcs[0])))
+	if s, ok := stackCache.Load(key); ok {
+		return s.(*stack)
+	}
+	if atomic.LoadInt32(&stackCacheSize) >= maxStackCacheEntries {
+		return nil
+	}
+	st := &stack{pcs: make([]uintptr, len(pcs))}
+	copy(st.pcs, pcs)
+	// The key is copied too, as it aliases the caller's buffer.
+	s, loaded := stackCache.LoadOrStore(string([]byte(key)), st)
+	if !loaded {
+		atomic.AddInt32(&stackCacheSize, 1)
+	}
+	return s.(*stack)
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestStackCache(t *testing.T) {
+	wrap := func() (a, b *withStack) {
+		var errs [2]*withStack
+		for i := range errs {
+			errs[i] = WithStack(io.EOF).(*withStack)
+		}
+		return errs[0], errs[1]
+	}
+
+	a, b := wrap()
+	if a.stack == b.stack {
+		t.Errorf("stack cache disabled: identical stack traces are shared")
+	}
+
+	SetStackCache(true)
+	defer SetStackCache(false)
+
+	a, b = wrap()
+	if a.stack != b.stack {
+		t.Errorf("stack cache enabled: identical stack traces are not shared")
+	}
+	if c := WithStack(io.EOF).(*withStack); c.stack == a.stack {
+		t.Errorf("stack cache enabled: different stack traces are shared")
+	}
+
+	SetGoroutineCapture(true)
+	a, b = wrap()
+	SetGoroutineCapture(false)
+	if a.stack == b.stack {
+		t.Errorf("goroutine capture enabled: identical stack traces are shared")
+	}
+}
@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// WithStackChain annotates err, typically received from another goroutine,
+// with the stack trace at the point WithStackChain was called. Both stack
+// traces matter when an error created in a worker goroutine is handled in
+// the goroutine coordinating it: %+v prints err in detail, including the
+// stack trace recorded where it was created, followed by the stack trace
+// recorded by WithStackChain under an "observed in:" label.
+//
+//	go func() { results <- work() }()
+//	if err := <-results; err != nil {
+//		return errors.WithStackChain(err)
+//	}
+//
+// If err is nil, WithStackChain returns nil.
+func WithStackChain(err error) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withStackChain{
+		cause: err,
+		stack: callers(),
+	}))
+}
+
+type withStackChain struct {
+	cause error
+	*stack
+}
+
+func (w *withStackChain) Error() string { return redact(w.cause.Error()) }
+func (w *withStackChain) Cause() error  { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withStackChain) Unwrap() error { return w.cause }
+
+func (w *withStackChain) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v", w.Cause()))
+			io.WriteString(s, "\nobserved in:")
+			w.stack.Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
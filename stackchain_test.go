@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestWithStackChainNil(t *testing.T) {
+	if got := WithStackChain(nil); got != nil {
+		t.Errorf("WithStackChain(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestFormatWithStackChain(t *testing.T) {
+	results := make(chan error)
+	go func() { results <- New("failed") }()
+	err := WithStackChain(<-results)
+
+	if got, want := fmt.Sprintf("%v", err), "failed"; got != want {
+		t.Errorf("fmt.Sprintf(%%v, err): got %q, want %q", got, want)
+	}
+	want := `(?s)^failed\n` +
+		`github.com/peakle/errors.TestFormatWithStackChain.func1\n\t.+/stackchain_test.go:18\n.*` +
+		`\nobserved in:\n` +
+		`github.com/peakle/errors.TestFormatWithStackChain\n\t.+/stackchain_test.go:19\n`
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+	if !Is(WithStackChain(io.EOF), io.EOF) {
+		t.Errorf("Is(WithStackChain(io.EOF), io.EOF): got false, want true")
+	}
+}
@@ -0,0 +1,66 @@
+package errors
+
+import "sync/atomic"
+
+var stackPolicy atomic.Value // of func(error) bool
+
+// SetStackPolicy installs fn as the policy deciding whether WithStack, Wrap,
+// Wrapf and their context variants capture a stack trace: they call fn with
+// the error they wrap, and skip the capture when it returns false, so that
+// cheap, expected errors, such as NotFound errors in hot lookups, cost no
+// call to runtime.Callers. Errors wrapped without a stack trace are
+// otherwise identical. A nil policy, the default, captures every stack
+// trace. New and Errorf, which wrap no error, do not consult the policy.
+//
+// StackForSeverity and StackUnlessKind return common policies:
+//
+//	errors.SetStackPolicy(errors.StackUnlessKind(errors.NotFound, errors.Canceled))
+func SetStackPolicy(fn func(error) bool) { stackPolicy.Store(fn) }
+
+// StackForSeverity returns a stack policy capturing stack traces only for
+// errors at least as severe as min, as returned by SeverityOf, or of
+// unknown severity.
+func StackForSeverity(min Severity) func(error) bool {
+	return func(err error) bool {
+		s := SeverityOf(err)
+		return s == SeverityUnknown || s >= min
+	}
+}
+
+// StackUnlessKind returns a stack policy capturing stack traces for all
+// errors except those of one of kinds, as returned by KindOf.
+func StackUnlessKind(kinds ...Kind) func(error) bool {
+	return func(err error) bool {
+		k := KindOf(err)
+		for _, kind := range kinds {
+			if k == kind {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// WithStackIf annotates err with a stack trace at the point WithStackIf was
+// called if cond is true, as WithStack does, and returns err unchanged
+// otherwise.
+// If err is nil, WithStackIf returns nil.
+func WithStackIf(err error, cond bool) error {
+	if err == nil || !cond {
+		return err
+	}
+	return created(transform(err, &withStack{
+		err,
+		callersFor(err),
+	}))
+}
+
+// callersFor returns the stack trace of the caller of its caller, as
+// callers does, if the stack policy allows capturing one for an error
+// wrapping cause, or an empty stack trace otherwise.
+func callersFor(cause error) *stack {
+	if fn, _ := stackPolicy.Load().(func(error) bool); fn != nil && !fn(cause) {
+		return new(stack)
+	}
+	return callersSkip(5)
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestWithStackIf(t *testing.T) {
+	if got := WithStackIf(nil, true); got != nil {
+		t.Errorf("WithStackIf(nil, true): got %#v, want nil", got)
+	}
+	if got := WithStackIf(io.EOF, false); got != io.EOF {
+		t.Errorf("WithStackIf(io.EOF, false): got %#v, want io.EOF", got)
+	}
+	err := WithStackIf(io.EOF, true)
+	if st := err.(*withStack).StackTrace(); len(st) == 0 || st[0].name() != "github.com/peakle/errors.TestWithStackIf" {
+		t.Errorf("WithStackIf(io.EOF, true): got stack trace %v, want one starting in TestWithStackIf", st)
+	}
+}
+
+func TestSetStackPolicy(t *testing.T) {
+	defer SetStackPolicy(nil)
+
+	tests := []struct {
+		policy func(error) bool
+		err    error
+		want   bool
+	}{
+		{nil, io.EOF, true},
+		{StackUnlessKind(NotFound), io.EOF, true},
+		{StackUnlessKind(NotFound), WithKind(io.EOF, NotFound), false},
+		{StackUnlessKind(Canceled, NotFound), WithKind(io.EOF, NotFound), false},
+		{StackForSeverity(SeverityError), io.EOF, true},
+		{StackForSeverity(SeverityError), WithSeverity(io.EOF, SeverityInfo), false},
+		{StackForSeverity(SeverityError), WithSeverity(io.EOF, SeverityCritical), true},
+	}
+
+	for i, tt := range tests {
+		SetStackPolicy(tt.policy)
+		for _, err := range []error{WithStack(tt.err), Wrap(tt.err, "wrap"), Wrapf(tt.err, "wrap %d", i)} {
+			st := err.(*withStack).StackTrace()
+			if got := len(st) > 0; got != tt.want {
+				t.Errorf("test %d: %v: got stack trace %v, want %v", i+1, err, got, tt.want)
+			}
+			if len(st) > 0 && st[0].name() != "github.com/peakle/errors.TestSetStackPolicy" {
+				t.Errorf("test %d: %v: got stack trace starting in %s, want TestSetStackPolicy", i+1, err, st[0].name())
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// FormatStyle selects the layout errors of this package are printed in by
+// the %+v verb.
+type FormatStyle int32
+
+const (
+	// StyleClassic prints each layer of the chain, from the root cause out,
+	// followed by the stack trace it recorded, if any. This is the default.
+	StyleClassic FormatStyle = iota
+
+	// StyleSingleLine prints the message of the error, its code and fields,
+	// and the innermost frame of the stack trace recorded closest to its
+	// root cause, on a single line.
+	StyleSingleLine
+
+	// StyleJSON prints the error as a JSON object holding its message, code,
+	// kind, fields and the stack trace recorded closest to its root cause.
+	// Consecutive identical frames are merged as by %+v. The time recorded
+	// by WithTime is included if any, and the goroutine ID, its pprof labels
+	// and build information when enabled by SetGoroutineCapture and
+	// SetBuildInfo.
+	StyleJSON
+
+	// StyleTraceback prints the error as by Traceback.
+	StyleTraceback
+
+	// StyleSections prints the chain of the error from the outermost error
+	// in, in labeled sections. Each section starts with the message of an
+	// error of the chain, prefixed by "caused by: " for all but the first,
+	// and lists the attachments of that error and of the errors wrapping it
+	// that carry no message of their own, such as its code or fields, and
+	// the stack trace recorded where it was created or wrapped, each under
+	// its label and indented by two spaces:
+	//
+	//	read config: open /etc/app.conf: permission denied
+	//	  code: CONFIG
+	//	  fields:
+	//	    path=/etc/app.conf
+	//	  stack:
+	//	    main.loadConfig
+	//	      /src/app/config.go:42
+	//	caused by: open /etc/app.conf: permission denied
+	//
+	// The errors joined by Join are listed after a line such as
+	// "2 errors occurred:", each in its own sections, numbered from [0] and
+	// indented by six spaces. This layout is stable.
+	StyleSections
+)
+
+var formatStyle int32 // of FormatStyle
+
+// SetFormatStyle sets the layout errors of this package are printed in by
+// the %+v verb, so that the error output of a whole program can be changed
+// in one place. The %s, %v and %q verbs are not affected.
+func SetFormatStyle(style FormatStyle) { atomic.StoreInt32(&formatStyle, int32(style)) }
+
+func loadFormatStyle() FormatStyle { return FormatStyle(atomic.LoadInt32(&formatStyle)) }
+
+// formatStyled prints err, which is being formatted with %+v, in the style
+// set by SetFormatStyle and reports whether it did. It does nothing in the
+// classic style, which the Format methods implement themselves.
+func formatStyled(s fmt.State, err error) bool {
+	switch loadFormatStyle() {
+	case StyleSingleLine:
+		io.WriteString(s, singleLine(err))
+	case StyleJSON:
+		b, _ := json.Marshal(jsonError(err))
+		s.Write(b)
+	case StyleTraceback:
+		io.WriteString(s, strings.TrimSuffix(Traceback(err), "\n"))
+	case StyleSections:
+		io.WriteString(s, sections(err))
+	default:
+		return false
+	}
+	return true
+}
+
+// singleLine renders err in the single line style:
+//
+//	read config: EOF code=CONFIG path=/etc/app.conf (app.loadConfig config.go:42)
+func singleLine(err error) string {
+	var b strings.Builder
+	b.WriteString(strings.Replace(err.Error(), "\n", "; ", -1))
+	if code := Code(err); code != "" {
+		b.WriteString(" code=" + code)
+	}
+	fields := Fields(err)
+	for _, k := range sortedKeys(fields) {
+		fmt.Fprintf(&b, " %s=%v", k, redact(fmt.Sprint(fields[k])))
+	}
+	if st := originStack(err); len(st) > 0 {
+		fmt.Fprintf(&b, " (%s %v)", st[0].name(), st[0])
+	}
+	return b.String()
+}
+
+// errorRecord is the value an error is rendered as in the JSON style.
+type errorRecord struct {
+	Message   string                 `json:"message"`
+	Code      string                 `json:"code,omitempty"`
+	Kind      string                 `json:"kind,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Stack     []string               `json:"stack,omitempty"`
+	Encoded   string                 `json:"stack_encoded,omitempty"`
+	Goroutine int64                  `json:"goroutine,omitempty"`
+	Labels    map[string]string      `json:"goroutine_labels,omitempty"`
+	Build     *buildStamp            `json:"build,omitempty"`
+	Time      *time.Time             `json:"time,omitempty"`
+}
+
+// jsonError returns the value err is rendered as in the JSON style.
+func jsonError(err error) errorRecord {
+	v := errorRecord{
+		Message: err.Error(),
+		Code:    Code(err),
+		Fields:  exportFields(err),
+		Build:   currentBuild(),
+	}
+	if kind := KindOf(err); kind != Unknown {
+		v.Kind = kind.String()
+	}
+	if g := goroutineOf(err); g != nil {
+		v.Goroutine, v.Labels = g.id, g.labelMap()
+	}
+	if t, ok := OccurredAt(err); ok {
+		v.Time = &t
+	}
+	st := originStack(err)
+	if offlineSymbolization() && len(st) > 0 {
+		v.Encoded = EncodeStack(st)
+		st = nil
+	}
+	v.Stack = stackTexts(st)
+	for k, val := range v.Fields {
+		v.Fields[k] = redact(fmt.Sprint(val))
+	}
+	return v
+}
+
+// stackTexts returns the frames of st as text, as in the JSON style, with
+// consecutive identical frames merged.
+func stackTexts(st StackTrace) []string {
+	var texts []string
+	for _, r := range frameRuns(len(st), func(i int) Frame { return st[i] }) {
+		text := r.frame.text()
+		if r.count > 1 {
+			text += " (× " + strconv.Itoa(r.count) + ")"
+		}
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+// sortedKeys returns the keys of m in increasing order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
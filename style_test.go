@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestSetFormatStyle(t *testing.T) {
+	defer SetFormatStyle(StyleClassic)
+
+	f := NewFrame("app.loadConfig", "/src/app/config.go", 42)
+	err := WithKind(WithCode(WithField(WithMessage(
+		&withStack{io.EOF, &stack{pcs: []uintptr{uintptr(f)}}}, "read config"),
+		"path", "/etc/app.conf"), "CONFIG"), NotFound)
+
+	tests := []struct {
+		style FormatStyle
+		want  string
+	}{{
+		StyleClassic,
+		"EOF\napp.loadConfig\n\t/src/app/config.go:42\nread config\npath=/etc/app.conf\ncode=CONFIG\nkind=not_found",
+	}, {
+		StyleSingleLine,
+		"read config: EOF code=CONFIG path=/etc/app.conf (app.loadConfig config.go:42)",
+	}, {
+		StyleJSON,
+		`{"message":"read config: EOF","code":"CONFIG","kind":"not_found","fields":{"path":"/etc/app.conf"},"stack":["app.loadConfig /src/app/config.go:42"]}`,
+	}, {
+		StyleTraceback,
+		"read config: EOF\n\ngoroutine 0 [running]:\napp.loadConfig(...)\n\t/src/app/config.go:42",
+	}}
+
+	for i, tt := range tests {
+		SetFormatStyle(tt.style)
+		if got := fmt.Sprintf("%+v", err); got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%%+v, err): got %q, want %q", i+1, got, tt.want)
+		}
+		if got := fmt.Sprintf("%v", err); got != "read config: EOF" {
+			t.Errorf("test %d: fmt.Sprintf(%%v, err): got %q, want %q", i+1, got, "read config: EOF")
+		}
+	}
+
+	SetFormatStyle(StyleSingleLine)
+	if got, want := fmt.Sprintf("%+v", Join(io.EOF, io.ErrUnexpectedEOF)), "EOF; unexpected EOF"; got != want {
+		t.Errorf("fmt.Sprintf(%%+v, Join()): got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,32 @@
+package errors
+
+import "strings"
+
+// Summary returns a one line digest of err, made of its code, kind,
+// message and origin, the innermost frame of the stack trace recorded
+// closest to its root cause:
+//
+//	code=CONFIG kind=not_found msg="read config: EOF" origin=app.loadConfig(config.go:42)
+//
+// The parts err does not carry are left out, and values are quoted as by
+// Logfmt where needed. Summary is meant for routine logs, where the full
+// trace printed by %+v is too much. Summary returns "" if err is nil.
+func Summary(err error) string {
+	if err == nil {
+		return ""
+	}
+	var b strings.Builder
+	if code := Code(err); code != "" {
+		writeLogfmt(&b, "code", code)
+	}
+	if kind := KindOf(err); kind != Unknown {
+		writeLogfmt(&b, "kind", kind.String())
+	}
+	writeLogfmt(&b, "msg", err.Error())
+	if st := originStack(err); len(st) > 0 {
+		name := st[0].name()
+		name = name[strings.LastIndex(name, "/")+1:]
+		writeLogfmt(&b, "origin", name+"("+st[0].String()+")")
+	}
+	return b.String()
+}
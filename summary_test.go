@@ -0,0 +1,29 @@
+package errors
+
+import (
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, "^$"},
+		{io.EOF, "^msg=EOF$"},
+		{WithCode(io.EOF, "EOF"), "^code=EOF msg=EOF$"},
+		{
+			WithKind(WithCode(Wrap(io.EOF, "read config"), "CONFIG"), NotFound),
+			`^code=CONFIG kind=not_found msg="read config: EOF" origin=errors.TestSummary\(summary_test.go:\d+\)$`,
+		},
+		{Wrap(New("origin"), "outer"), `^msg="outer: origin" origin=errors.TestSummary\(summary_test.go:\d+\)$`},
+	}
+
+	for i, tt := range tests {
+		if got := Summary(tt.err); !regexp.MustCompile(tt.want).MatchString(got) {
+			t.Errorf("test %d: Summary(%v): got %q, want match for %q", i+1, tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package errors
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// offlineSymbols is non-zero while serialized stack traces are left
+// unresolved.
+var offlineSymbols int32
+
+// SetOfflineSymbolization sets whether errors rendered in the JSON style or
+// by Logfmt carry their stack trace unresolved, encoded by EncodeStack as a
+// "stack_encoded" value in place of the resolved "stack". It is disabled by
+// default. Unresolved stack traces are smaller, and can be resolved by
+// Symbolize from a copy of the binary which still holds the symbols, or the
+// source paths, stripped from the binary running in production.
+func SetOfflineSymbolization(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&offlineSymbols, v)
+}
+
+func offlineSymbolization() bool { return atomic.LoadInt32(&offlineSymbols) != 0 }
+
+// EncodeStack encodes st, without resolving the function, file and line of
+// its frames, as text from which Symbolize can resolve them given the binary
+// st was captured by. The text holds the Go build ID of the binary, if it
+// can be read, followed by the program counter of each frame relative to
+// that of a function of this package, so that it does not depend on the
+// address the binary was loaded at:
+//
+//	abc123/def456@-4f2e0,-4f10b,-8c3a1
+func EncodeStack(st StackTrace) string {
+	anchor := anchorPC()
+	var b strings.Builder
+	b.WriteString(executableBuildID())
+	b.WriteByte('@')
+	for i, f := range st {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatInt(int64(f.PC()-anchor), 16))
+	}
+	return b.String()
+}
+
+// Symbolize resolves the stack trace encoded by EncodeStack from the symbol
+// table of the binary at binaryPath, which must be the binary the stack
+// trace was captured by, or an unstripped build of the same source. It
+// returns each frame as "function file:line", innermost first, as the frames
+// of a resolved stack trace are serialized. Frames which cannot be resolved
+// are returned as "unknown". Symbolize supports ELF and Mach-O binaries.
+func Symbolize(encoded, binaryPath string) ([]string, error) {
+	i := strings.LastIndexByte(encoded, '@')
+	if i < 0 {
+		return nil, Errorf("symbolize: malformed stack %q", encoded)
+	}
+	buildID, pcs := encoded[:i], encoded[i+1:]
+	if buildID != "" {
+		if id, err := readBuildID(binaryPath); err == nil && id != "" && id != buildID {
+			return nil, Errorf("symbolize: %s has build ID %s, want %s", binaryPath, id, buildID)
+		}
+	}
+
+	tab, err := readSymbols(binaryPath)
+	if err != nil {
+		return nil, Wrap(err, "symbolize")
+	}
+	anchor := tab.LookupFunc(runtime.FuncForPC(anchorPC()).Name())
+	if anchor == nil {
+		return nil, Errorf("symbolize: %s was not built with this package", binaryPath)
+	}
+	if pcs == "" {
+		return nil, nil
+	}
+
+	var frames []string
+	for _, s := range strings.Split(pcs, ",") {
+		off, err := strconv.ParseInt(s, 16, 64)
+		if err != nil {
+			return nil, Errorf("symbolize: malformed program counter %q", s)
+		}
+		file, line, fn := tab.PCToLine(uint64(int64(anchor.Entry) + off))
+		if fn == nil {
+			frames = append(frames, "unknown")
+			continue
+		}
+		frames = append(frames, fmt.Sprintf("%s %s:%d", fn.Name, file, line))
+	}
+	return frames, nil
+}
+
+// anchorPC returns the entry program counter of a function of this package,
+// against which EncodeStack records the program counters of frames.
+func anchorPC() uintptr {
+	return runtime.FuncForPC(reflect.ValueOf(EncodeStack).Pointer()).Entry()
+}
+
+var (
+	buildIDOnce sync.Once
+	buildID     string
+)
+
+// executableBuildID returns the Go build ID of the running binary, or "" if
+// it cannot be read.
+func executableBuildID() string {
+	buildIDOnce.Do(func() {
+		if path, err := os.Executable(); err == nil {
+			buildID, _ = readBuildID(path)
+		}
+	})
+	return buildID
+}
+
+// readBuildID returns the Go build ID of the binary at path, or "" if it has
+// none. The linker records the build ID of ELF binaries in a note, and that
+// of other binaries at the start of the text segment, as
+// `\xff Go build ID: "<id>"\n \xff`.
+func readBuildID(path string) (string, error) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		s := f.Section(".note.go.buildid")
+		if s == nil {
+			return "", nil
+		}
+		data, err := s.Data()
+		if err != nil {
+			return "", err
+		}
+		// The note is a header of three 4 byte words, the name and
+		// description sizes and the note type, followed by the name "Go"
+		// padded to 4 bytes and the build ID.
+		if len(data) < 16 {
+			return "", nil
+		}
+		size := int(f.ByteOrder.Uint32(data[4:]))
+		if len(data) < 16+size {
+			return "", nil
+		}
+		return string(data[16 : 16+size]), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 32<<10)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	prefix := []byte("\xff Go build ID: \"")
+	data := buf[:n]
+	i := bytes.Index(data, prefix)
+	if i < 0 {
+		return "", nil
+	}
+	data = data[i+len(prefix):]
+	j := bytes.IndexByte(data, '"')
+	if j < 0 {
+		return "", nil
+	}
+	return string(data[:j]), nil
+}
+
+// readSymbols returns the Go symbol table of the binary at path.
+func readSymbols(path string) (*gosym.Table, error) {
+	var pclntab []byte
+	var text uint64
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		s := f.Section(".gopclntab")
+		if s == nil {
+			return nil, Errorf("%s has no Go symbol table", path)
+		}
+		if pclntab, err = s.Data(); err != nil {
+			return nil, err
+		}
+		if t := f.Section(".text"); t != nil {
+			text = t.Addr
+		}
+	} else if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		s := f.Section("__gopclntab")
+		if s == nil {
+			return nil, Errorf("%s has no Go symbol table", path)
+		}
+		if pclntab, err = s.Data(); err != nil {
+			return nil, err
+		}
+		if t := f.Section("__text"); t != nil {
+			text = t.Addr
+		}
+	} else {
+		return nil, Errorf("%s is not an ELF or Mach-O binary", path)
+	}
+	return gosym.NewTable(nil, gosym.NewLineTable(pclntab, text))
+}
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSymbolize(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	st := New("error").(*fundamental).StackTrace()
+	encoded := EncodeStack(st[:1])
+	if !strings.HasPrefix(encoded, executableBuildID()+"@") {
+		t.Errorf("EncodeStack(): got %q, want the build ID %q first", encoded, executableBuildID())
+	}
+
+	frames, err := Symbolize(encoded, path)
+	if err != nil {
+		t.Fatalf("Symbolize(): %v", err)
+	}
+	want := `^github.com/peakle/errors.TestSymbolize .+/symbolize_test.go:16$`
+	if len(frames) != 1 || !regexp.MustCompile(want).MatchString(frames[0]) {
+		t.Errorf("Symbolize(): got %q, want %s", frames, want)
+	}
+}
+
+func TestSymbolizeErrors(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		encoded, path string
+		want          string
+	}{
+		{"no separator", path, "symbolize: malformed stack"},
+		{"@zz", path, "symbolize: malformed program counter"},
+		{"other-build@0", path, "has build ID"},
+		{"@0", "symbolize_test.go", "is not an ELF or Mach-O binary"},
+	}
+	for i, tt := range tests {
+		_, err := Symbolize(tt.encoded, tt.path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("test %d: Symbolize(%q): got %v, want an error containing %q", i+1, tt.encoded, err, tt.want)
+		}
+	}
+}
+
+func TestSetOfflineSymbolization(t *testing.T) {
+	SetOfflineSymbolization(true)
+	defer SetOfflineSymbolization(false)
+
+	err := New("error")
+	want := EncodeStack(err.(*fundamental).StackTrace())
+	if got := Logfmt(err); !strings.Contains(got, " stack_encoded="+want) || strings.Contains(got, " stack=") {
+		t.Errorf("Logfmt(): got %q, want stack_encoded=%s", got, want)
+	}
+
+	SetFormatStyle(StyleJSON)
+	defer SetFormatStyle(StyleClassic)
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, `"stack_encoded":"`+want+`"`) || strings.Contains(got, `"stack":`) {
+		t.Errorf("StyleJSON: got %s, want stack_encoded %s", got, want)
+	}
+}
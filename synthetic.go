@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// syntheticFrame holds the metadata of a Frame created by NewFrame.
+type syntheticFrame struct {
+	name string
+	file string
+	line int
+}
+
+var (
+	syntheticMu    sync.Mutex
+	syntheticIndex = map[syntheticFrame]Frame{}
+	syntheticCount uint32       // number of synthetic frames, read atomically
+	synthetic      atomic.Value // of []syntheticFrame
+)
+
+// NewFrame returns a Frame that reports the supplied function name, source
+// file and line number instead of resolving a program counter. It is meant
+// for tests of code that formats or serializes stack traces, which need
+// deterministic frames:
+//
+//	st := errors.StackTrace{
+//		errors.NewFrame("example.com/app.handler", "/src/app/handler.go", 42),
+//	}
+//
+// Calling NewFrame more than once with the same arguments returns the same
+// Frame.
+func NewFrame(funcName, file string, line int) Frame {
+	sf := syntheticFrame{name: funcName, file: file, line: line}
+
+	syntheticMu.Lock()
+	defer syntheticMu.Unlock()
+	if f, ok := syntheticIndex[sf]; ok {
+		return f
+	}
+	frames, _ := synthetic.Load().([]syntheticFrame)
+	next := make([]syntheticFrame, len(frames), len(frames)+1)
+	copy(next, frames)
+	next = append(next, sf)
+	synthetic.Store(next)
+	atomic.StoreUint32(&syntheticCount, uint32(len(next)))
+
+	// Synthetic frames are numbered down from the top of the address space,
+	// where no program counter of user code can be found.
+	f := Frame(^uintptr(0) - uintptr(len(frames)))
+	syntheticIndex[sf] = f
+	return f
+}
+
+// lookupSynthetic returns the metadata of f if f was created by NewFrame.
+func lookupSynthetic(f Frame) (syntheticFrame, bool) {
+	n := uintptr(atomic.LoadUint32(&syntheticCount))
+	if n == 0 || uintptr(f) <= ^uintptr(0)-n {
+		return syntheticFrame{}, false
+	}
+	frames, _ := synthetic.Load().([]syntheticFrame)
+	i := ^uintptr(0) - uintptr(f)
+	if i >= uintptr(len(frames)) {
+		return syntheticFrame{}, false
+	}
+	return frames[i], true
+}
@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewFrame(t *testing.T) {
+	f := NewFrame("example.com/app.(*Server).handle", "/src/app/server.go", 42)
+	if g := NewFrame("example.com/app.(*Server).handle", "/src/app/server.go", 42); g != f {
+		t.Errorf("NewFrame() with the same arguments: got %#x, want %#x", g, f)
+	}
+	g := NewFrame("example.com/app.main", "/src/app/main.go", 7)
+	if g == f {
+		t.Errorf("NewFrame() with different arguments: got the same frame %#x", g)
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%s", "server.go"},
+		{"%d", "42"},
+		{"%n", "(*Server).handle"},
+		{"%v", "server.go:42"},
+		{"%+v", "example.com/app.(*Server).handle\n\t/src/app/server.go:42"},
+	}
+	for i, tt := range tests {
+		if got := fmt.Sprintf(tt.format, f); got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%q, frame): got %q, want %q", i+1, tt.format, got, tt.want)
+		}
+	}
+
+	st := StackTrace{f, g}
+	if got, want := fmt.Sprintf("%+v", st), "\n"+
+		"example.com/app.(*Server).handle\n\t/src/app/server.go:42\n"+
+		"example.com/app.main\n\t/src/app/main.go:7"; got != want {
+		t.Errorf("fmt.Sprintf(%%+v, st): got %q, want %q", got, want)
+	}
+	if got, want := f.Func(), "example.com/app.(*Server).handle"; got != want {
+		t.Errorf("Frame.Func(): got %q, want %q", got, want)
+	}
+	if got, want := Frame(0).Func(), "unknown"; got != want {
+		t.Errorf("Frame(0).Func(): got %q, want %q", got, want)
+	}
+}
+
+func TestFrameResolve(t *testing.T) {
+	tests := []struct {
+		f          Frame
+		name, file string
+		line       int
+	}{
+		{NewFrame("app.main", "/src/app/main.go", 7), "app.main", "/src/app/main.go", 7},
+		{0, "unknown", "unknown", 0},
+	}
+
+	for i, tt := range tests {
+		name, file, line := tt.f.resolve()
+		if name != tt.name || file != tt.file || line != tt.line {
+			t.Errorf("test %d: resolve(): got %q, %q, %d, want %q, %q, %d", i+1, name, file, line, tt.name, tt.file, tt.line)
+		}
+		if name != tt.f.name() || file != tt.f.file() || line != tt.f.line() {
+			t.Errorf("test %d: resolve(): got %q, %q, %d, want the same as name, file and line", i+1, name, file, line)
+		}
+	}
+}
+
+func TestWindowsPaths(t *testing.T) {
+	tests := []struct {
+		file   string
+		format string
+		want   string
+	}{
+		{`C:\src\app\main.go`, "%s", "main.go"},
+		{`C:\src\app\main.go`, "%v", "main.go:7"},
+		{`C:/src/app\main.go`, "%v", "main.go:7"},
+		{`\\server\share\main.go`, "%s", "main.go"},
+		{"/src/app/main.go", "%s", "main.go"},
+		{"main.go", "%s", "main.go"},
+	}
+
+	for i, tt := range tests {
+		f := NewFrame("app.main", tt.file, 7)
+		if got := fmt.Sprintf(tt.format, f); got != tt.want {
+			t.Errorf("test %d: fmt.Sprintf(%q, %q): got %q, want %q", i+1, tt.format, tt.file, got, tt.want)
+		}
+	}
+
+	SetPathStyle(PathBase)
+	defer SetPathStyle(PathFull)
+	f := NewFrame("app.main", `C:\src\app\main.go`, 7)
+	if got, want := fmt.Sprintf("%+v", f), "app.main\n\tmain.go:7"; got != want {
+		t.Errorf("fmt.Sprintf(%%+v) with PathBase: got %q, want %q", got, want)
+	}
+	if !isLibraryFrame(NewFrame("example.com/lib.Do", `C:\Users\me\go\pkg\mod\example.com\lib@v1.0.0\lib.go`, 3)) {
+		t.Errorf("isLibraryFrame(): got false for a Windows module cache path, want true")
+	}
+}
@@ -0,0 +1,166 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// A Template defines a class of error identified by a code, such as
+// "USER_NOT_FOUND", with a message format shared by every occurrence:
+//
+//	var ErrUserNotFound = errors.Define("USER_NOT_FOUND", "user %s not found").
+//		WithKind(errors.NotFound)
+//
+// Each occurrence is created with New or Wrap, and records its own stack
+// trace, while errors.Is(err, ErrUserNotFound) reports whether err is an
+// occurrence of the template. A Template is itself an error so that it can
+// serve as the target of Is.
+type Template struct {
+	code   string
+	format string
+	kind   Kind
+	status int
+}
+
+// Define returns a new Template with the supplied code and message format.
+// The Template is recorded in the catalog returned by Catalog.
+func Define(code, format string) *Template {
+	t := &Template{
+		code:   code,
+		format: format,
+	}
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	catalog = append(catalog, t)
+	return t
+}
+
+// WithKind sets the kind of the occurrences of t and returns t. It is meant
+// to be chained to Define.
+func (t *Template) WithKind(kind Kind) *Template {
+	if t != nil {
+		t.kind = kind
+	}
+	return t
+}
+
+// WithHTTPStatus sets the HTTP status code of the occurrences of t and
+// returns t. It is meant to be chained to Define.
+func (t *Template) WithHTTPStatus(status int) *Template {
+	if t != nil {
+		t.status = status
+	}
+	return t
+}
+
+// Error returns the code and message format of t, or "" if t is nil.
+func (t *Template) Error() string {
+	if t == nil {
+		return ""
+	}
+	return t.code + ": " + t.format
+}
+
+// Code returns the code of t, or "" if t is nil.
+func (t *Template) Code() string {
+	if t == nil {
+		return ""
+	}
+	return t.code
+}
+
+// Kind returns the kind of t, or Unknown if t is nil.
+func (t *Template) Kind() Kind {
+	if t == nil {
+		return Unknown
+	}
+	return t.kind
+}
+
+// HTTPStatus returns the HTTP status code of t, or zero if it has none or t
+// is nil.
+func (t *Template) HTTPStatus() int {
+	if t == nil {
+		return 0
+	}
+	return t.status
+}
+
+// MessageFormat returns the message format of t, or "" if t is nil.
+func (t *Template) MessageFormat() string {
+	if t == nil {
+		return ""
+	}
+	return t.format
+}
+
+// New returns an occurrence of t whose message is formatted from args,
+// recording the stack trace at the point New was called.
+func (t *Template) New(args ...interface{}) error {
+	return created(&templateError{
+		tmpl:  t,
+		msg:   sprintf(t.MessageFormat(), args...),
+		stack: callers(),
+	})
+}
+
+// Wrap returns an occurrence of t caused by err, whose message is formatted
+// from args, recording the stack trace at the point Wrap was called.
+// If err is nil, Wrap returns nil.
+func (t *Template) Wrap(err error, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &templateError{
+		tmpl:  t,
+		msg:   sprintf(t.MessageFormat(), args...),
+		cause: err,
+		stack: callers(),
+	}))
+}
+
+// templateError is an occurrence of a Template.
+type templateError struct {
+	tmpl  *Template
+	msg   string
+	cause error
+	*stack
+}
+
+func (e *templateError) Error() string {
+	if e.cause == nil {
+		return redact(e.msg)
+	}
+	return redact(prefixMessage(e.msg, e.cause.Error()))
+}
+
+func (e *templateError) Cause() error    { return e.cause }
+func (e *templateError) Unwrap() error   { return e.cause }
+func (e *templateError) Code() string    { return e.tmpl.Code() }
+func (e *templateError) Kind() Kind      { return e.tmpl.Kind() }
+func (e *templateError) HTTPStatus() int { return e.tmpl.HTTPStatus() }
+
+// Is reports whether target is the Template e is an occurrence of.
+func (e *templateError) Is(target error) bool { return target == e.tmpl }
+
+func (e *templateError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, e) {
+				return
+			}
+			if e.cause != nil {
+				io.WriteString(s, redactf("%+v\n", e.cause))
+			}
+			io.WriteString(s, redact(e.msg))
+			e.stack.Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
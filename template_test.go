@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+var (
+	errUserNotFound = Define("USER_NOT_FOUND", "user %s not found").WithKind(NotFound)
+	errReadFailed   = Define("READ_FAILED", "reading %s").WithHTTPStatus(502)
+)
+
+func TestTemplate(t *testing.T) {
+	tests := []struct {
+		err    error
+		msg    string
+		code   string
+		kind   Kind
+		status int
+		is     error
+	}{
+		{errUserNotFound.New("bob"), "user bob not found", "USER_NOT_FOUND", NotFound, 404, errUserNotFound},
+		{Wrap(errUserNotFound.New("bob"), "login"), "login: user bob not found", "USER_NOT_FOUND", NotFound, 404, errUserNotFound},
+		{errReadFailed.Wrap(io.EOF, "config"), "reading config: EOF", "READ_FAILED", Unknown, 502, io.EOF},
+		{WithCode(errReadFailed.Wrap(io.EOF, "config"), "OUTER"), "reading config: EOF", "OUTER", Unknown, 502, errReadFailed},
+	}
+
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.msg {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.msg)
+		}
+		if got := Code(tt.err); got != tt.code {
+			t.Errorf("test %d: Code(): got %q, want %q", i+1, got, tt.code)
+		}
+		if got := KindOf(tt.err); got != tt.kind {
+			t.Errorf("test %d: KindOf(): got %v, want %v", i+1, got, tt.kind)
+		}
+		if got := HTTPStatus(tt.err); got != tt.status {
+			t.Errorf("test %d: HTTPStatus(): got %d, want %d", i+1, got, tt.status)
+		}
+		if !Is(tt.err, tt.is) {
+			t.Errorf("test %d: Is(err, %v): got false, want true", i+1, tt.is)
+		}
+	}
+
+	if Is(errUserNotFound.New("bob"), errReadFailed) {
+		t.Error("Is(occurrence, other template): got true, want false")
+	}
+	if err := errReadFailed.Wrap(nil, "config"); err != nil {
+		t.Errorf("Wrap(nil): got %v, want nil", err)
+	}
+}
+
+func TestFormatTemplate(t *testing.T) {
+	err := errReadFailed.Wrap(io.EOF, "config")
+	want := "^EOF\nreading config\ngithub.com/peakle/errors.TestFormatTemplate\n\t.+/template_test.go:\\d+\n"
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+	if got, want := errUserNotFound.Error(), "USER_NOT_FOUND: user %s not found"; got != want {
+		t.Errorf("Template.Error(): got %q, want %q", got, want)
+	}
+}
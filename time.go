@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// WithTime annotates err with the current time, as the time the failure it
+// reports occurred, so that errors reported after a delay, such as those
+// queued for delivery, reflect when the failure actually happened. The time
+// is printed by %+v and included in the JSON style.
+// If err is nil, WithTime returns nil.
+func WithTime(err error) error {
+	if err == nil {
+		return nil
+	}
+	return created(transform(err, &withTime{
+		cause: err,
+		time:  time.Now(),
+	}))
+}
+
+// OccurredAt returns the time recorded by WithTime closest to the root cause
+// of err, and whether there is one.
+func OccurredAt(err error) (time.Time, bool) {
+	var t time.Time
+	var found bool
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if w, ok := err.(*withTime); ok {
+			t, found = w.time, true
+		}
+		err = Unwrap(err)
+	}
+	return t, found
+}
+
+type withTime struct {
+	cause error
+	time  time.Time
+}
+
+func (w *withTime) Error() string { return redact(w.cause.Error()) }
+func (w *withTime) Cause() error  { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withTime) Unwrap() error { return w.cause }
+
+func (w *withTime) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, w) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", w.Cause()))
+			io.WriteString(s, "time="+w.time.Format(time.RFC3339Nano))
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
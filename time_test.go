@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithTimeNil(t *testing.T) {
+	if got := WithTime(nil); got != nil {
+		t.Errorf("WithTime(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestOccurredAt(t *testing.T) {
+	if _, ok := OccurredAt(io.EOF); ok {
+		t.Errorf("OccurredAt(io.EOF): got a time, want none")
+	}
+
+	before := time.Now()
+	inner := WithTime(io.EOF)
+	err := WithTime(Wrap(inner, "queued"))
+
+	got, ok := OccurredAt(err)
+	want, _ := OccurredAt(inner)
+	if !ok || !got.Equal(want) {
+		t.Errorf("OccurredAt(): got %v, %t, want the innermost time %v", got, ok, want)
+	}
+	if got.Before(before) || got.After(time.Now()) {
+		t.Errorf("OccurredAt(): got %v, want the time WithTime was called", got)
+	}
+	if got := err.Error(); got != "queued: EOF" {
+		t.Errorf("WithTime(): got %q, want %q", got, "queued: EOF")
+	}
+}
+
+func TestFormatWithTime(t *testing.T) {
+	err := WithTime(io.EOF)
+	at, _ := OccurredAt(err)
+	if got, want := fmt.Sprintf("%+v", err), "EOF\ntime="+at.Format(time.RFC3339Nano); got != want {
+		t.Errorf("fmt.Sprintf(%%+v): got %q, want %q", got, want)
+	}
+
+	SetFormatStyle(StyleJSON)
+	defer SetFormatStyle(StyleClassic)
+	var v struct {
+		Time time.Time `json:"time"`
+	}
+	text := fmt.Sprintf("%+v", err)
+	if err := json.Unmarshal([]byte(text), &v); err != nil || !v.Time.Equal(at) {
+		t.Errorf("StyleJSON: got %s, want time %v", text, at)
+	}
+	if text := fmt.Sprintf("%+v", New("error")); strings.Contains(text, `"time"`) {
+		t.Errorf("StyleJSON: got %s, want no time", text)
+	}
+}
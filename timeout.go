@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// NewTimeout returns an error reporting that the operation op took elapsed,
+// exceeding its time limit, such as:
+//
+//	fetch profile: timed out after 1.502s (limit 1.5s)
+//
+// The error reports true from its Timeout method, as do the timeout errors
+// of package net, matches context.DeadlineExceeded with Is, is of kind
+// DeadlineExceeded, and carries op and the durations as the fields "op",
+// "elapsed" and "limit". NewTimeout also records the stack trace at the
+// point it was called.
+func NewTimeout(op string, elapsed, limit time.Duration) error {
+	return created(&timeoutError{
+		op:      op,
+		elapsed: elapsed,
+		limit:   limit,
+		stack:   callers(),
+	})
+}
+
+// timeoutError is an operation that exceeded its time limit.
+type timeoutError struct {
+	op      string
+	elapsed time.Duration
+	limit   time.Duration
+	*stack
+}
+
+func (e *timeoutError) Error() string {
+	return redact(fmt.Sprintf("%s: timed out after %v (limit %v)", e.op, e.elapsed, e.limit))
+}
+
+// Timeout reports that e is a timeout, as net.Error does.
+func (e *timeoutError) Timeout() bool { return true }
+
+// Is reports whether target is context.DeadlineExceeded.
+func (e *timeoutError) Is(target error) bool { return target == context.DeadlineExceeded }
+
+func (e *timeoutError) Kind() Kind { return DeadlineExceeded }
+
+func (e *timeoutError) fieldList() []field {
+	return []field{
+		{key: "op", value: e.op},
+		{key: "elapsed", value: e.elapsed},
+		{key: "limit", value: e.limit},
+	}
+}
+
+func (e *timeoutError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, e) {
+				return
+			}
+			io.WriteString(s, e.Error())
+			e.stack.Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
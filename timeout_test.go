@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestNewTimeout(t *testing.T) {
+	err := NewTimeout("fetch profile", 1502*time.Millisecond, 1500*time.Millisecond)
+
+	if got, want := err.Error(), "fetch profile: timed out after 1.502s (limit 1.5s)"; got != want {
+		t.Errorf("NewTimeout(): got %q, want %q", got, want)
+	}
+	var timeout interface{ Timeout() bool }
+	if !As(err, &timeout) || !timeout.Timeout() {
+		t.Errorf("NewTimeout(): Timeout() not reported")
+	}
+	if !Is(Wrap(err, "load"), context.DeadlineExceeded) {
+		t.Errorf("Is(err, context.DeadlineExceeded): got false, want true")
+	}
+	if Is(err, context.Canceled) {
+		t.Errorf("Is(err, context.Canceled): got true, want false")
+	}
+	if got := KindOf(err); got != DeadlineExceeded {
+		t.Errorf("KindOf(): got %v, want %v", got, DeadlineExceeded)
+	}
+	want := map[string]interface{}{"op": "fetch profile", "elapsed": 1502 * time.Millisecond, "limit": 1500 * time.Millisecond}
+	if got := Fields(WithField(err, "user", "acme")); !reflect.DeepEqual(got, map[string]interface{}{
+		"op": want["op"], "elapsed": want["elapsed"], "limit": want["limit"], "user": "acme",
+	}) {
+		t.Errorf("Fields(): got %v, want %v and user", got, want)
+	}
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(`^fetch profile: timed out after 1.502s \(limit 1.5s\)\ngithub.com/peakle/errors.TestNewTimeout\n\t.+/timeout_test.go:13`).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v): got %q", got)
+	}
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"context"
+	"runtime/trace"
+	"sync/atomic"
+)
+
+// traceEvents is non-zero when error creation is logged to execution traces.
+var traceEvents int32
+
+// SetTraceEvents controls whether the creation of errors is logged to the
+// execution trace, while one is being collected with runtime/trace.Start,
+// as by the /debug/pprof/trace endpoint of net/http/pprof. Each error this
+// package constructs or wraps is logged in the category "error" with its
+// message followed by the top frame of its origin stack trace, so that
+// traces viewed with go tool trace show where failures cluster in time.
+// It is disabled by default; while no trace is collected, it costs nothing
+// beyond an atomic load.
+func SetTraceEvents(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&traceEvents, v)
+}
+
+// traceCreated logs the creation of err to the execution trace, if enabled
+// and a trace is being collected.
+func traceCreated(err error) {
+	if atomic.LoadInt32(&traceEvents) == 0 || !trace.IsEnabled() {
+		return
+	}
+	msg := err.Error()
+	if st := originStack(err); len(st) > 0 {
+		msg += " at " + st[0].text()
+	}
+	trace.Log(context.Background(), "error", msg)
+}
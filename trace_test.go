@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"bytes"
+	"runtime/trace"
+	"testing"
+)
+
+func TestSetTraceEvents(t *testing.T) {
+	tests := []struct {
+		on   bool
+		want bool
+	}{
+		{false, false},
+		{true, true},
+	}
+
+	for i, tt := range tests {
+		SetTraceEvents(tt.on)
+		var buf bytes.Buffer
+		if err := trace.Start(&buf); err != nil {
+			t.Skipf("trace.Start: %v", err)
+		}
+		New("traced failure")
+		trace.Stop()
+		SetTraceEvents(false)
+
+		got := bytes.Contains(buf.Bytes(), []byte("traced failure at github.com/peakle/errors.TestSetTraceEvents "))
+		if got != tt.want {
+			t.Errorf("test %d: SetTraceEvents(%v): event logged: got %v, want %v", i+1, tt.on, got, tt.want)
+		}
+	}
+}
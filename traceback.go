@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Traceback returns err in the layout of the traceback the Go runtime prints
+// when a program panics, so that tools built to parse those, such as
+// panicparse and the stack trace parsers of IDEs, can parse it:
+//
+//	read config: EOF
+//
+//	goroutine 0 [running]:
+//	example.com/app.loadConfig(...)
+//		/src/app/config.go:42 +0x1b
+//	main.main(...)
+//		/src/app/main.go:12 +0x25
+//
+// The message of err is followed by the stack trace recorded closest to its
+// root cause, under the ID of the goroutine that recorded it, as returned by
+// GoroutineID. If the ID is unknown, as it is unless recorded while enabled
+// by SetGoroutineCapture, it is reported as goroutine 0. Traceback returns ""
+// if err is nil.
+func Traceback(err error) string {
+	if err == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(err.Error())
+	id, _ := GoroutineID(err)
+	b.WriteString("\n\ngoroutine " + strconv.FormatInt(id, 10) + " [running]:")
+	for _, f := range originStack(err) {
+		writeTracebackFrame(&b, f)
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// writeTracebackFrame writes f to b in the layout of the Go runtime.
+func writeTracebackFrame(b *strings.Builder, f Frame) {
+	name, file, line := f.resolve()
+	b.WriteByte('\n')
+	b.WriteString(name)
+	b.WriteString("(...)\n\t")
+	b.WriteString(file)
+	b.WriteByte(':')
+	b.WriteString(strconv.Itoa(line))
+	if _, ok := lookupSynthetic(f); ok {
+		return
+	}
+	if fn := runtime.FuncForPC(f.pc()); fn != nil {
+		b.WriteString(" +0x")
+		b.WriteString(strconv.FormatUint(uint64(uintptr(f)-fn.Entry()), 16))
+	}
+}
@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestTraceback(t *testing.T) {
+	if got := Traceback(nil); got != "" {
+		t.Errorf("Traceback(nil): got %q, want %q", got, "")
+	}
+	if got, want := Traceback(io.EOF), "EOF\n\ngoroutine 0 [running]:\n"; got != want {
+		t.Errorf("Traceback(io.EOF): got %q, want %q", got, want)
+	}
+
+	err := Wrap(New("EOF"), "read config")
+	want := `^read config: EOF\n` +
+		`\n` +
+		`goroutine 0 \[running\]:\n` +
+		`github.com/peakle/errors.TestTraceback\(\.\.\.\)\n` +
+		`\t.+/traceback_test.go:17 \+0x[0-9a-f]+\n` +
+		`testing.tRunner\(\.\.\.\)\n` +
+		`\t.+/testing.go:\d+ \+0x[0-9a-f]+\n`
+	if got := Traceback(err); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("Traceback(): got %q, want match %q", got, want)
+	}
+}
+
+func TestTracebackSynthetic(t *testing.T) {
+	err := &withStack{io.EOF, &stack{pcs: []uintptr{uintptr(NewFrame("app.main", "/src/app/main.go", 7))}}}
+	want := "EOF\n\ngoroutine 0 [running]:\napp.main(...)\n\t/src/app/main.go:7\n"
+	if got := Traceback(err); got != want {
+		t.Errorf("Traceback(): got %q, want %q", got, want)
+	}
+}
+
+func TestTracebackGoroutine(t *testing.T) {
+	SetGoroutineCapture(true)
+	defer SetGoroutineCapture(false)
+
+	want := `^EOF\n\ngoroutine [1-9][0-9]* \[running\]:\n`
+	if got := Traceback(New("EOF")); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("Traceback(): got %q, want match %q", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	transformersMu sync.Mutex   // serialises writers of transformers
+	transformers   atomic.Value // of []func(error) error
+)
+
+// RegisterTransformer appends fn to the pipeline of transformers applied when
+// an error that did not originate from this package is first wrapped by it,
+// whether with Wrap, WithStack, WithMessage or any of their variants.
+// Transformers run in registration order; each receives the freshly wrapped
+// error returned by the previous one and returns its replacement, making the
+// pipeline the single place to map driver errors onto domain errors or to
+// enrich errors with deployment metadata. A transformer returning nil leaves
+// the error unchanged.
+//
+// Errors already produced by this package are not transformed again, so a
+// transformer is free to annotate the error it is given using this package.
+//
+// RegisterTransformer is safe for concurrent use, but is intended to be
+// called during program initialisation.
+func RegisterTransformer(fn func(error) error) {
+	if fn == nil {
+		return
+	}
+	transformersMu.Lock()
+	defer transformersMu.Unlock()
+	old, _ := transformers.Load().([]func(error) error)
+	fns := make([]func(error) error, len(old), len(old)+1)
+	copy(fns, old)
+	transformers.Store(append(fns, fn))
+}
+
+// resetTransformers removes all registered transformers.
+func resetTransformers() {
+	transformersMu.Lock()
+	defer transformersMu.Unlock()
+	transformers.Store([]func(error) error{})
+}
+
+// transform runs the registered transformers over err, the result of
+// wrapping cause, unless cause has already passed through this package.
+// If cause has reached the maximum wrap depth, err is dropped in favour of
+// the collapsed chain.
+func transform(cause, err error) error {
+	if c := collapse(cause); c != nil {
+		return c
+	}
+	fns, _ := transformers.Load().([]func(error) error)
+	if len(fns) == 0 || fromPackage(cause) {
+		return err
+	}
+	for _, fn := range fns {
+		if e := fn(err); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// fromPackage reports whether any error in err's chain was produced by this
+// package.
+func fromPackage(err error) bool {
+	var c cycle
+	for err != nil && !c.seen(err) {
+		if isOwn(err) {
+			return true
+		}
+		err = Unwrap(err)
+	}
+	return false
+}
+
+// isOwn reports whether err itself was produced by this package.
+func isOwn(err error) bool {
+	switch err.(type) {
+	case *fundamental, *withStack, *withMessage, *withLazyMessage, *withFields,
+		*withCode, *withKind, *withSeverity, *withHTTPStatus, *templateError, *masked,
+		*withCaller, *withFormatter, *withStackChain, *withGoroutines, *withTime,
+		*timeoutError, *translated, *withRetryable, *withExitCode, *withCollapsed, *rpcError,
+		*Validation, *panicError, *dumpError, *joinError:
+		return true
+	case typedCoder: // any instantiation of coded
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+var errDomain = New("domain error")
+
+func TestRegisterTransformer(t *testing.T) {
+	RegisterTransformer(func(err error) error {
+		if Is(err, io.ErrUnexpectedEOF) {
+			return WithMessage(err, "truncated")
+		}
+		return nil
+	})
+	RegisterTransformer(func(err error) error {
+		return WithField(err, "region", "eu-west-1")
+	})
+	RegisterTransformer(nil)
+	defer resetTransformers()
+
+	tests := []struct {
+		err    error
+		want   string
+		fields bool
+	}{
+		{Wrap(io.ErrUnexpectedEOF, "read"), "truncated: read: unexpected EOF", true},
+		{WithStack(io.EOF), "EOF", true},
+		{WithMessage(io.EOF, "read"), "read: EOF", true},
+		{Wrap(Wrap(io.ErrUnexpectedEOF, "read"), "load"), "load: truncated: read: unexpected EOF", true},
+		{Wrap(errDomain, "load"), "load: domain error", false},
+		{WithStack(fmt.Errorf("wrapped: %w", errDomain)), "wrapped: domain error", false},
+	}
+
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if got := Fields(tt.err)["region"] != nil; got != tt.fields {
+			t.Errorf("test %d: transformed: got %v, want %v", i+1, got, tt.fields)
+		}
+	}
+}
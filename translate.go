@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// A Translator translates low-level errors, such as those of drivers and
+// the standard library, into the domain errors of an application, by the
+// first of a table of rules matching them:
+//
+//	var ErrUserNotFound = errors.Define("USER_NOT_FOUND", "user not found").
+//		WithKind(errors.NotFound)
+//
+//	var translator = new(errors.Translator).
+//		Register(sql.ErrNoRows, ErrUserNotFound).
+//		RegisterFunc(errors.IsKind(errors.DeadlineExceeded), ErrStoreUnavailable)
+//
+//	return translator.Translate(err)
+//
+// The zero Translator has no rules and is ready to use. A Translator is safe
+// for concurrent use. A nil *Translator has no rules either, and rules
+// registered with it are dropped.
+type Translator struct {
+	mu    sync.RWMutex
+	rules []translation
+}
+
+// translation is a rule of a Translator.
+type translation struct {
+	match  Matcher
+	domain error
+}
+
+// Register adds a rule translating the errors for which Is(err, target)
+// reports true into domain, and returns t.
+func (t *Translator) Register(target, domain error) *Translator {
+	return t.RegisterFunc(Wraps(target), domain)
+}
+
+// RegisterFunc adds a rule translating the errors satisfying m into domain,
+// and returns t.
+func (t *Translator) RegisterFunc(m Matcher, domain error) *Translator {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rules = append(t.rules, translation{match: m, domain: domain})
+	return t
+}
+
+// Translate returns err translated by the first rule of t matching it, in
+// the order the rules were registered, or err itself if none does. The
+// translated error has the message of the domain error, or the message
+// format of a Template, and its code, kind and HTTP status. It matches the
+// domain error with Is and As, and wraps err, whose stack trace and
+// attachments are printed by %+v. Translate returns nil if err is nil.
+func (t *Translator) Translate(err error) error {
+	if err == nil || t == nil {
+		return err
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, r := range t.rules {
+		if Match(err, r.match) {
+			return created(transform(err, &translated{
+				domain: r.domain,
+				cause:  err,
+			}))
+		}
+	}
+	return err
+}
+
+// translated is an error translated into a domain error by a Translator.
+type translated struct {
+	domain error
+	cause  error
+}
+
+func (e *translated) Error() string {
+	if t, ok := e.domain.(*Template); ok {
+		return redact(t.MessageFormat())
+	}
+	return redact(e.domain.Error())
+}
+
+func (e *translated) Cause() error { return e.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (e *translated) Unwrap() error { return e.cause }
+
+func (e *translated) Code() string { return Code(e.domain) }
+func (e *translated) Kind() Kind   { return KindOf(e.domain) }
+func (e *translated) HTTPStatus() int {
+	type statuser interface {
+		HTTPStatus() int
+	}
+
+	var c cycle
+	for err := e.domain; err != nil && !c.seen(err); err = Unwrap(err) {
+		if s, ok := err.(statuser); ok && s.HTTPStatus() != 0 {
+			return s.HTTPStatus()
+		}
+	}
+	return 0
+}
+
+// Is reports whether the domain error of e matches target.
+func (e *translated) Is(target error) bool { return Is(e.domain, target) }
+
+// As finds the first error in the chain of the domain error of e that
+// matches target.
+func (e *translated) As(target interface{}) bool { return As(e.domain, target) }
+
+func (e *translated) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, e) {
+				return
+			}
+			io.WriteString(s, redactf("%+v\n", e.cause))
+			io.WriteString(s, e.Error())
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTranslator(t *testing.T) {
+	errUserNotFound := Define("USER_NOT_FOUND", "user not found").WithKind(NotFound)
+	errStorage := WithHTTPStatus(WithCode(fmt.Errorf("storage unavailable"), "STORAGE"), 503)
+
+	tr := new(Translator).
+		Register(sql.ErrNoRows, errUserNotFound).
+		RegisterFunc(IsKind(Unavailable), errStorage).
+		Register(io.EOF, errStorage)
+
+	tests := []struct {
+		err    error
+		domain error
+		msg    string
+		code   string
+		kind   Kind
+		status int
+	}{
+		{Wrap(sql.ErrNoRows, "query"), errUserNotFound, "user not found", "USER_NOT_FOUND", NotFound, 404},
+		{WithKind(io.EOF, Unavailable), errStorage, "storage unavailable", "STORAGE", Unavailable, 503},
+		{io.EOF, errStorage, "storage unavailable", "STORAGE", Unknown, 503},
+	}
+
+	for i, tt := range tests {
+		got := tr.Translate(tt.err)
+		if got.Error() != tt.msg {
+			t.Errorf("test %d: Translate(): got %q, want %q", i+1, got.Error(), tt.msg)
+		}
+		if !Is(got, tt.domain) || !Is(got, tt.err) {
+			t.Errorf("test %d: Translate(): does not match both the domain and the original error", i+1)
+		}
+		if Cause(got) != Cause(tt.err) {
+			t.Errorf("test %d: Cause(): got %v, want %v", i+1, Cause(got), Cause(tt.err))
+		}
+		if c, k, s := Code(got), KindOf(got), HTTPStatus(got); c != tt.code || k != tt.kind || s != tt.status {
+			t.Errorf("test %d: got code %q, kind %v, status %d, want %q, %v, %d", i+1, c, k, s, tt.code, tt.kind, tt.status)
+		}
+	}
+
+	if got := tr.Translate(os.ErrNotExist); got != os.ErrNotExist {
+		t.Errorf("Translate(os.ErrNotExist): got %v, want the error unchanged", got)
+	}
+	if got := tr.Translate(nil); got != nil {
+		t.Errorf("Translate(nil): got %v, want nil", got)
+	}
+	var zero Translator
+	if got := zero.Translate(io.EOF); got != io.EOF {
+		t.Errorf("Translator{}.Translate(io.EOF): got %v, want io.EOF", got)
+	}
+}
+
+func TestFormatTranslated(t *testing.T) {
+	tr := new(Translator).Register(sql.ErrNoRows, fmt.Errorf("user not found"))
+	err := tr.Translate(Wrap(sql.ErrNoRows, "query"))
+
+	got := fmt.Sprintf("%+v", err)
+	for _, want := range []string{"sql: no rows in result set\nquery\n", "errors.TestFormatTranslated\n", "\nuser not found"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("fmt.Sprintf(%%+v): %q missing:\n%s", want, got)
+		}
+	}
+	if got := fmt.Sprintf("%v", err); got != "user not found" {
+		t.Errorf("fmt.Sprintf(%%v): got %q, want %q", got, "user not found")
+	}
+}
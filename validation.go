@@ -0,0 +1,180 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A Validation accumulates the problems found validating a request or other
+// input, each attached to the path of the field at fault, such as "email"
+// or "items[2].quantity":
+//
+//	v := errors.NewValidation("invalid order")
+//	if o.Email == "" {
+//		v.AddField("email", "required")
+//	}
+//	if o.Quantity <= 0 {
+//		v.AddField("quantity", "must be positive")
+//	}
+//	return v.Err()
+//
+// A Validation is of kind InvalidArgument, so that the HTTP, gRPC and
+// JSON-RPC mappings of this package report it as a client error, and
+// errors.Is(err, ErrValidation) reports whether err is a Validation.
+// The methods of a nil *Validation do nothing, and its Err returns nil.
+type Validation struct {
+	msg    string
+	fields []fieldViolation
+	*stack
+}
+
+// fieldViolation is a problem with the field at path.
+type fieldViolation struct {
+	path string
+	msg  string
+}
+
+// ErrValidation is the target matched by errors.Is for every Validation.
+var ErrValidation error = &Validation{msg: "validation failed"}
+
+// NewValidation returns an empty Validation with the supplied message,
+// or "validation failed" if message is empty. The stack trace is recorded
+// at the point NewValidation is called.
+func NewValidation(message string) *Validation {
+	if message == "" {
+		message = "validation failed"
+	}
+	return &Validation{
+		msg:   message,
+		stack: callers(),
+	}
+}
+
+// AddField records the problem msg with the field at path and returns v.
+func (v *Validation) AddField(path, msg string) *Validation {
+	if v != nil {
+		v.fields = append(v.fields, fieldViolation{path: path, msg: msg})
+	}
+	return v
+}
+
+// Len returns the number of problems recorded by v.
+func (v *Validation) Len() int {
+	if v == nil {
+		return 0
+	}
+	return len(v.fields)
+}
+
+// Err returns v as an error if it recorded any problem, or nil otherwise.
+func (v *Validation) Err() error {
+	if v.Len() == 0 {
+		return nil
+	}
+	return created(v)
+}
+
+// Error returns the message of v followed by its problems, as in
+// "invalid order: email: required; quantity: must be positive".
+func (v *Validation) Error() string {
+	if v == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(v.msg)
+	for i, f := range v.fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(f.path + ": " + f.msg)
+	}
+	return redact(b.String())
+}
+
+// Kind returns InvalidArgument.
+func (v *Validation) Kind() Kind { return InvalidArgument }
+
+// Is reports whether target is a Validation, such as ErrValidation.
+func (v *Validation) Is(target error) bool {
+	_, ok := target.(*Validation)
+	return ok
+}
+
+// Format prints v according to the fmt.Formatter interface. The %+v verb
+// prints its message, then each of its problems on a line of its own, then
+// its stack trace.
+func (v *Validation) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatStyled(s, v) {
+				return
+			}
+			io.WriteString(s, redact(v.msg))
+			io.WriteString(s, v.fieldLines())
+			v.stack.Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, v.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", v.Error())
+	}
+}
+
+// fieldLines returns the problems of v, each on a line of its own preceded
+// by a line break.
+func (v *Validation) fieldLines() string {
+	var b strings.Builder
+	for _, f := range v.fields {
+		b.WriteString("\n    " + f.path + ": " + redact(f.msg))
+	}
+	return b.String()
+}
+
+// MarshalJSON returns the problems of v in the shape conventionally used
+// for the body of responses rejecting a request, mapping the path of each
+// field at fault to its problems:
+//
+//	{"errors":{"email":["required"],"quantity":["must be positive"]}}
+func (v *Validation) MarshalJSON() ([]byte, error) {
+	errs := v.fieldErrors(nil)
+	if errs == nil {
+		errs = map[string][]string{}
+	}
+	return json.Marshal(struct {
+		Errors map[string][]string `json:"errors"`
+	}{errs})
+}
+
+// fieldErrors adds the problems of v to errs, allocating it if nil, and
+// returns it.
+func (v *Validation) fieldErrors(errs map[string][]string) map[string][]string {
+	for _, f := range v.fields {
+		if errs == nil {
+			errs = make(map[string][]string)
+		}
+		errs[f.path] = append(errs[f.path], redact(f.msg))
+	}
+	return errs
+}
+
+// FieldErrors returns the problems recorded by every Validation in err's
+// chain, including the branches of joined errors, mapping the path of each
+// field at fault to its problems, for web frameworks rendering them to
+// clients. FieldErrors returns nil if err is nil or holds no Validation.
+func FieldErrors(err error) map[string][]string {
+	var errs map[string][]string
+	Walk(err, func(err error) bool {
+		if v, ok := err.(*Validation); ok && v != nil {
+			errs = v.fieldErrors(errs)
+		}
+		return true
+	})
+	return errs
+}
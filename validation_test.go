@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+	if err := NewValidation("invalid order").Err(); err != nil {
+		t.Errorf("Err() without problems: got %v, want nil", err)
+	}
+	var nilv *Validation
+	if err := nilv.AddField("email", "required").Err(); err != nil {
+		t.Errorf("nil Validation: got %v, want nil", err)
+	}
+
+	tests := []struct {
+		v    *Validation
+		want string
+	}{
+		{NewValidation("").AddField("email", "required"), "validation failed: email: required"},
+		{
+			NewValidation("invalid order").AddField("email", "required").AddField("items[2].quantity", "must be positive"),
+			"invalid order: email: required; items[2].quantity: must be positive",
+		},
+	}
+
+	for i, tt := range tests {
+		err := tt.v.Err()
+		if got := err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		wrapped := Wrap(err, "create order")
+		if !Is(wrapped, ErrValidation) {
+			t.Errorf("test %d: Is(ErrValidation): got false, want true", i+1)
+		}
+		if got := KindOf(wrapped); got != InvalidArgument {
+			t.Errorf("test %d: KindOf(): got %q, want %q", i+1, got, InvalidArgument)
+		}
+		if got := HTTPStatus(wrapped); got != 400 {
+			t.Errorf("test %d: HTTPStatus(): got %d, want 400", i+1, got)
+		}
+	}
+
+	if Is(io.EOF, ErrValidation) || Is(New("invalid"), ErrValidation) {
+		t.Errorf("Is(ErrValidation): got true for a non-validation error")
+	}
+
+	err := NewValidation("invalid order").AddField("email", "required").AddField("quantity", "must be positive").Err()
+	want := "^invalid order\n    email: required\n    quantity: must be positive\ngithub.com/peakle/errors.TestValidation\n\t.+validation_test.go:\\d+\n"
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("%%+v: got %q, want match for %q", got, want)
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	order := NewValidation("invalid order").AddField("email", "required").AddField("email", "invalid").Err()
+	item := NewValidation("invalid item").AddField("items[0].quantity", "must be positive").Err()
+
+	tests := []struct {
+		err  error
+		want map[string][]string
+	}{
+		{nil, nil},
+		{io.EOF, nil},
+		{Wrap(order, "create order"), map[string][]string{"email": {"required", "invalid"}}},
+		{Join(order, io.EOF, WithMessage(item, "items")), map[string][]string{
+			"email":             {"required", "invalid"},
+			"items[0].quantity": {"must be positive"},
+		}},
+	}
+
+	for i, tt := range tests {
+		if got := FieldErrors(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: FieldErrors(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestValidationMarshalJSON(t *testing.T) {
+	tests := []struct {
+		v    *Validation
+		want string
+	}{
+		{NewValidation(""), `{"errors":{}}`},
+		{
+			NewValidation("").AddField("email", "required").AddField("quantity", "must be positive"),
+			`{"errors":{"email":["required"],"quantity":["must be positive"]}}`,
+		},
+	}
+
+	for i, tt := range tests {
+		got, err := json.Marshal(tt.v)
+		if err != nil || string(got) != tt.want {
+			t.Errorf("test %d: json.Marshal(): got %s, %v, want %s", i+1, got, err, tt.want)
+		}
+	}
+}
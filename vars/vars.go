@@ -0,0 +1,74 @@
+// Package vars publishes counts of errors, broken down by code and kind, as
+// the expvar variable errors.created, served as JSON by the standard
+// /debug/vars endpoint:
+//
+//	{"errors.created": {"total": 12, "code": {"PAY_DECLINED": 3}, "kind": {"unknown": 9, "unavailable": 3}}}
+//
+// Errors are counted as they are created, once Publish has been called:
+//
+//	func main() {
+//	        vars.Publish()
+//	        ...
+//	}
+//
+// An error is counted once, when it first acquires a stack trace; wrapping
+// it further does not count it again, so the code and kind it is counted
+// under are those it carries at that point. Errors without a code are
+// counted in the total and by kind only.
+package vars
+
+import (
+	"expvar"
+	"sync"
+
+	"github.com/peakle/errors"
+)
+
+// Name is the name under which Publish publishes the counts.
+const Name = "errors.created"
+
+var (
+	created = new(expvar.Map).Init()
+	total   = new(expvar.Int)
+	byCode  = new(expvar.Map).Init()
+	byKind  = new(expvar.Map).Init()
+)
+
+func init() {
+	created.Set("total", total)
+	created.Set("code", byCode)
+	created.Set("kind", byKind)
+}
+
+var publishOnce sync.Once
+
+// Publish publishes the counts as the expvar variable named Name and
+// arranges for every error created by package errors from then on to be
+// counted. It is safe to call more than once.
+func Publish() {
+	publishOnce.Do(func() {
+		expvar.Publish(Name, created)
+		errors.RegisterHook(observeCreated)
+	})
+}
+
+// Observe counts err, whether or not it has already been counted as it was
+// created. Nil errors are ignored.
+func Observe(err error) {
+	if err == nil {
+		return
+	}
+	total.Add(1)
+	if code := errors.Code(err); code != "" {
+		byCode.Add(code, 1)
+	}
+	byKind.Add(errors.KindOf(err).String(), 1)
+}
+
+// observeCreated counts err if it is the first error in its chain to carry
+// a stack trace.
+func observeCreated(err error) {
+	if errors.FirstStack(err) {
+		Observe(err)
+	}
+}
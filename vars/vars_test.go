@@ -0,0 +1,49 @@
+package vars
+
+import (
+	"encoding/json"
+	"expvar"
+	"io"
+	"testing"
+
+	"github.com/peakle/errors"
+)
+
+func TestPublish(t *testing.T) {
+	Publish()
+	Publish()
+
+	before := total.Value()
+	errors.Wrap(errors.WithKind(errors.WithCode(io.EOF, "EOF"), errors.NotFound), "read")
+	errors.Wrap(errors.New("error"), "read")
+	errors.WithMessage(io.EOF, "read")
+
+	if got, want := total.Value()-before, int64(2); got != want {
+		t.Errorf("total: got %d more, want %d", got, want)
+	}
+
+	var v struct {
+		Total int64
+		Code  map[string]int64
+		Kind  map[string]int64
+	}
+	if err := json.Unmarshal([]byte(expvar.Get(Name).String()), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Total != total.Value() || v.Code["EOF"] < 1 || v.Kind["not_found"] < 1 || v.Kind["unknown"] < 1 {
+		t.Errorf("%s: got %+v", Name, v)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	before := byCode.Get("TEST")
+	Observe(nil)
+	Observe(errors.WithCode(io.EOF, "TEST"))
+	Observe(errors.WithCode(io.EOF, "TEST"))
+	if before != nil {
+		t.Fatalf("code TEST counted before Observe")
+	}
+	if got := byCode.Get("TEST").(*expvar.Int).Value(); got != 2 {
+		t.Errorf("code TEST: got %d, want 2", got)
+	}
+}
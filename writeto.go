@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteTo writes st to w as by %+v, frame by frame, without first building
+// the whole trace in memory. It implements io.WriterTo, returning the number
+// of bytes written and the first error encountered.
+func (st StackTrace) WriteTo(w io.Writer) (int64, error) {
+	return streamFormat(w, st)
+}
+
+// WriteError writes err to w in detail, as by %+v, streaming the output of
+// its Format method to w rather than building it in memory first, so that
+// errors carrying very large stack traces or goroutine dumps can be written
+// to files or sockets at a bounded cost. Errors that do not implement
+// fmt.Formatter are written as by %+v all the same. WriteError returns the
+// number of bytes written and the first error encountered, and writes
+// nothing if err is nil.
+func WriteError(w io.Writer, err error) (int64, error) {
+	if err == nil {
+		return 0, nil
+	}
+	f, ok := err.(fmt.Formatter)
+	if !ok {
+		n, err := fmt.Fprintf(w, "%+v", err)
+		return int64(n), err
+	}
+	return streamFormat(w, f)
+}
+
+// streamFormat formats f with %+v to w through a buffer.
+func streamFormat(w io.Writer, f fmt.Formatter) (int64, error) {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	f.Format(&streamState{bw}, 'v')
+	bw.Flush()
+	return cw.n, cw.err
+}
+
+// countWriter counts the bytes written to w, and stops writing after the
+// first error.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+// streamState is the fmt.State of the %+v verb, writing to an io.Writer.
+type streamState struct {
+	io.Writer
+}
+
+func (s *streamState) Width() (int, bool)     { return 0, false }
+func (s *streamState) Precision() (int, bool) { return 0, false }
+func (s *streamState) Flag(c int) bool        { return c == '+' }
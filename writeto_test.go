@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type errWriter struct{ n int }
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return w.n, io.ErrShortWrite
+	}
+	return len(p), nil
+}
+
+func TestStackTraceWriteTo(t *testing.T) {
+	st := WithStack(io.EOF).(*withStack).StackTrace()
+	var buf bytes.Buffer
+	n, err := st.WriteTo(&buf)
+	if want := fmt.Sprintf("%+v", st); buf.String() != want || n != int64(len(want)) || err != nil {
+		t.Errorf("WriteTo(): got %q, %d, %v, want %q, %d, nil", buf.String(), n, err, want, len(want))
+	}
+
+	n, err = st.WriteTo(&errWriter{n: 3})
+	if n != 3 || err != io.ErrShortWrite {
+		t.Errorf("WriteTo(): got %d, %v, want 3, %v", n, err, io.ErrShortWrite)
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []error{
+		nil,
+		io.EOF,
+		New("error"),
+		WithField(Wrap(New("error"), "wrap"), "key", "value"),
+		WithAllGoroutines(io.EOF),
+	}
+
+	for i, err := range tests {
+		var buf bytes.Buffer
+		n, werr := WriteError(&buf, err)
+		want := ""
+		if err != nil {
+			want = fmt.Sprintf("%+v", err)
+		}
+		if buf.String() != want || n != int64(len(want)) || werr != nil {
+			t.Errorf("test %d: WriteError(): got %q, %d, %v, want %q, %d, nil", i+1, buf.String(), n, werr, want, len(want))
+		}
+	}
+}
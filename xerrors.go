@@ -0,0 +1,280 @@
+package errors
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// The errors of this package implement xerrors.Formatter, so that they print
+// in detail through tooling built on golang.org/x/xerrors, such as errors
+// wrapped by xerrors.Errorf and printed with %+v. Each error prints its own
+// message, and its stack trace and attachments as detail, and returns the
+// error it wraps so that the printer continues with it. Errors that only
+// attach detail, such as those returned by WithStack or WithCode, print as
+// part of the error they wrap.
+var (
+	_ xerrors.Formatter = (*fundamental)(nil)
+	_ xerrors.Formatter = (*withStack)(nil)
+	_ xerrors.Formatter = (*withMessage)(nil)
+	_ xerrors.Formatter = (*withLazyMessage)(nil)
+	_ xerrors.Formatter = (*withFields)(nil)
+	_ xerrors.Formatter = (*withCode)(nil)
+	_ xerrors.Formatter = (*withKind)(nil)
+	_ xerrors.Formatter = (*withSeverity)(nil)
+	_ xerrors.Formatter = (*withHTTPStatus)(nil)
+	_ xerrors.Formatter = (*templateError)(nil)
+	_ xerrors.Formatter = (*masked)(nil)
+	_ xerrors.Formatter = (*withCaller)(nil)
+	_ xerrors.Formatter = (*withFormatter)(nil)
+	_ xerrors.Formatter = (*withStackChain)(nil)
+	_ xerrors.Formatter = (*withGoroutines)(nil)
+	_ xerrors.Formatter = (*withTime)(nil)
+	_ xerrors.Formatter = (*timeoutError)(nil)
+	_ xerrors.Formatter = (*translated)(nil)
+	_ xerrors.Formatter = (*withRetryable)(nil)
+	_ xerrors.Formatter = (*withExitCode)(nil)
+	_ xerrors.Formatter = (*withCollapsed)(nil)
+	_ xerrors.Formatter = (*rpcError)(nil)
+	_ xerrors.Formatter = (*Validation)(nil)
+	_ xerrors.Formatter = (*coded[string])(nil)
+	_ xerrors.Formatter = (*panicError)(nil)
+	_ xerrors.Formatter = (*dumpError)(nil)
+	_ xerrors.Formatter = (*joinError)(nil)
+)
+
+// formatNext prints cause through p in place of the error wrapping it, for
+// errors that only attach detail to cause and have no message of their own,
+// and returns the next error to print.
+func formatNext(p xerrors.Printer, cause error) error {
+	if f, ok := cause.(xerrors.Formatter); ok {
+		return f.FormatError(p)
+	}
+	p.Print(redact(cause.Error()))
+	return nil
+}
+
+// printStack prints s as detail through p.
+func printStack(p xerrors.Printer, s *stack) {
+	if p.Detail() && s != nil && len(s.pcs) > 0 {
+		p.Printf("%+v", s)
+	}
+}
+
+func (f *fundamental) FormatError(p xerrors.Printer) error {
+	p.Print(f.Error())
+	printStack(p, f.stack)
+	return nil
+}
+
+func (w *withStack) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.error)
+	printStack(p, w.stack)
+	return next
+}
+
+func (w *withMessage) FormatError(p xerrors.Printer) error {
+	p.Print(redact(w.msg))
+	return w.cause
+}
+
+func (w *withLazyMessage) FormatError(p xerrors.Printer) error {
+	p.Print(redact(w.message()))
+	return w.cause
+}
+
+func (w *withFields) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print(w.fieldString())
+	}
+	return next
+}
+
+func (w *withCode) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print("code=" + w.code)
+	}
+	return next
+}
+
+func (w *withKind) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print("kind=" + w.kind.String())
+	}
+	return next
+}
+
+func (w *withTime) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print("time=" + w.time.Format(time.RFC3339Nano))
+	}
+	return next
+}
+
+func (w *withSeverity) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print("severity=" + w.severity.String())
+	}
+	return next
+}
+
+func (w *withHTTPStatus) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print("http_status=" + strconv.Itoa(w.status))
+	}
+	return next
+}
+
+func (e *templateError) FormatError(p xerrors.Printer) error {
+	p.Print(redact(e.msg))
+	printStack(p, e.stack)
+	return e.cause
+}
+
+// FormatError prints the public message of m only: the hidden error is
+// not printed.
+func (m *masked) FormatError(p xerrors.Printer) error {
+	p.Print(m.Error())
+	printStack(p, m.stack)
+	return nil
+}
+
+func (w *withCaller) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() && w.frame != 0 {
+		name, file, line := w.frame.resolve()
+		p.Printf("%s:%d %s", file, line, name)
+	}
+	return next
+}
+
+func (w *withFormatter) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		var b bytes.Buffer
+		w.formatter(&b, w.cause)
+		p.Print(redact(b.String()))
+	}
+	return next
+}
+
+func (w *withStackChain) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() && len(w.stack.pcs) > 0 {
+		p.Print("observed in:")
+		p.Printf("%+v", w.stack)
+	}
+	return next
+}
+
+func (w *withGoroutines) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print("goroutines:\n")
+		p.Print(string(bytes.TrimRight(w.dump, "\n")))
+		if w.truncated {
+			p.Print("\n... truncated")
+		}
+	}
+	return next
+}
+
+func (e *timeoutError) FormatError(p xerrors.Printer) error {
+	p.Print(e.Error())
+	printStack(p, e.stack)
+	return nil
+}
+
+func (e *translated) FormatError(p xerrors.Printer) error {
+	p.Print(e.Error())
+	return e.cause
+}
+
+func (w *withRetryable) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print("retryable=" + strconv.FormatBool(w.retryable))
+	}
+	return next
+}
+
+func (w *withExitCode) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print("exit_code=" + strconv.Itoa(w.code))
+	}
+	return next
+}
+
+func (w *withCollapsed) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print(w.more())
+	}
+	return next
+}
+
+func (e *rpcError) FormatError(p xerrors.Printer) error {
+	p.Print(e.Error())
+	if p.Detail() {
+		p.Printf("jsonrpc_code=%d", e.code)
+		for _, frame := range e.stack {
+			p.Print("\n\t" + frame)
+		}
+	}
+	return nil
+}
+
+func (v *Validation) FormatError(p xerrors.Printer) error {
+	if p.Detail() {
+		p.Print(redact(v.msg) + v.fieldLines())
+	} else {
+		p.Print(v.Error())
+	}
+	printStack(p, v.stack)
+	return nil
+}
+
+func (w *coded[T]) FormatError(p xerrors.Printer) error {
+	next := formatNext(p, w.cause)
+	if p.Detail() {
+		p.Print("code=" + w.Code())
+	}
+	return next
+}
+
+func (p *panicError) FormatError(pr xerrors.Printer) error {
+	err := p.Unwrap()
+	if err != nil {
+		pr.Print("panic")
+	} else {
+		pr.Print(p.Error())
+	}
+	printStack(pr, p.stack)
+	return err
+}
+
+func (d *dumpError) FormatError(p xerrors.Printer) error {
+	p.Print(d.Error())
+	if p.Detail() {
+		for _, line := range d.stackLines() {
+			p.Print("\n" + line)
+		}
+	}
+	return nil
+}
+
+// FormatError prints the message of every joined error, as xerrors.Printer
+// does not support printing several wrapped errors.
+func (j *joinError) FormatError(p xerrors.Printer) error {
+	p.Print(j.Error())
+	return nil
+}
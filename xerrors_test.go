@@ -0,0 +1,32 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func TestXerrorsFormatter(t *testing.T) {
+	f := NewFrame("app.read", "/src/app/read.go", 5)
+	inner := WithCode(WithMessage(&withStack{io.EOF, &stack{pcs: []uintptr{uintptr(f)}}}, "read"), "READ")
+	err := xerrors.Errorf("load: %w", inner)
+
+	if got, want := fmt.Sprintf("%v", err), "load: read: EOF"; got != want {
+		t.Errorf("fmt.Sprintf(%%v, err): got %q, want %q", got, want)
+	}
+	want := `^load:\n    .+\n        .+/xerrors_test.go:\d+\n  - read:\n    code=READ\n  - EOF:\n    app.read\n    \t/src/app/read.go:5$`
+	got := fmt.Sprintf("%+v", err)
+	if !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err):\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestXerrorsFormatterMasked(t *testing.T) {
+	err := xerrors.Errorf("handle: %w", MaskWith(New("secret"), "unavailable"))
+	if got := fmt.Sprintf("%+v", err); regexp.MustCompile("secret").MatchString(got) {
+		t.Errorf("fmt.Sprintf(%%+v, err): got %q, hidden error printed", got)
+	}
+}